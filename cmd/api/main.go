@@ -227,6 +227,7 @@ func main() {
 	jobRepository := repository.NewJobRepository(dbConnection)
 	passwordResetRepo := repository.NewPasswordResetRepository(dbConnection)
 	jobApplicationRepository := repository.NewJobApplicationRepository(dbConnection)
+	scrapeRunRepository := repository.NewScrapeRunRepository(dbConnection)
 
 	// Usecases
 	userUsecase := usecase.NewUserUsercase(userRepository)
@@ -235,6 +236,7 @@ func main() {
 	siteCareerUsecase := usecase.NewSiteCareerUsecase(siteCareerRepository, s3Uploader)
 	planUsecase := usecase.NewPlanUsecase(planRepository)
 	requestedSiteUsecase := usecase.NewRequestedSiteUsecase(requestedSiteRepository)
+	siteHealthUsecase := usecase.NewSiteHealthUsecase(scrapeRunRepository)
 	paymentUsecase := usecase.NewPaymentUsecase(abacatepayGateway, redisClient, userUsecase, planRepository)
 	notificationUsecase := usecase.NewNotificationUsecase(userSiteRepository, nil, emailService, notificationRepository, planRepository, userRepository)
 
@@ -256,11 +258,13 @@ func main() {
 	accountController := controller.NewAccountController(userRepository)
 
 	adminDashboardController := controller.NewAdminDashboardController(dashboardRepository)
+	siteHealthController := controller.NewSiteHealthController(siteHealthUsecase)
 
 	statsController := controller.NewStatsController(dashboardRepository, redisClient)
 
 	emailConfigController := controller.NewEmailConfigController(emailConfigRepo, orchestrator)
 	jobApplicationController := controller.NewJobApplicationController(jobApplicationRepository)
+	jobController := controller.NewJobController(jobRepository)
 
 	// Analysis Controller (análise manual de IA)
 	var analysisController *controller.AnalysisController
@@ -363,6 +367,7 @@ func main() {
 		subscribedRoutes.PATCH("/api/applications/:id", jobApplicationController.Update)
 		subscribedRoutes.DELETE("/api/applications/:id", jobApplicationController.Delete)
 		subscribedRoutes.GET("/api/applications", jobApplicationController.GetAll)
+		subscribedRoutes.GET("/api/jobs/:id/revisions", jobController.GetJobRevisions)
 		if analysisController != nil {
 			analyzeRateLimiter := rateLimiterFn("analyze", 3, 60)
 			subscribedRoutes.POST("/api/analyze-job", analyzeRateLimiter, analysisController.AnalyzeJob)
@@ -380,6 +385,16 @@ func main() {
 	adminRoutes.Use(middleware.RequireAdmin())
 	{
 		adminRoutes.GET("/api/admin/dashboard", adminDashboardController.GetAdminDashboard)
+		adminRoutes.GET("/api/admin/sites/health", siteHealthController.GetSitesHealth)
+		adminRoutes.GET("/api/admin/sites/:id/runs", siteHealthController.GetSiteRuns)
+		adminRoutes.POST("/api/admin/sites/:id/revalidate", siteCareerController.RevalidateSite)
+		adminRoutes.PUT("/api/admin/sites/:id", siteCareerController.UpdateSiteCareer)
+		adminRoutes.DELETE("/api/admin/sites/:id", siteCareerController.DeleteSite)
+		adminRoutes.POST("/api/admin/sites/:id/activate", siteCareerController.ActivateSite)
+		adminRoutes.POST("/api/admin/sites/:id/deactivate", siteCareerController.DeactivateSite)
+		adminRoutes.GET("/api/admin/sites/:id/versions", siteCareerController.GetSiteVersions)
+		adminRoutes.GET("/api/admin/sites/:id/versions/diff", siteCareerController.DiffSiteVersions)
+		adminRoutes.POST("/api/admin/sites/:id/versions/:version/rollback", siteCareerController.RollbackSite)
 		adminRoutes.GET("/api/admin/email-config", emailConfigController.GetEmailConfig)
 		adminRoutes.PUT("/api/admin/email-config", emailConfigController.UpdateEmailConfig)
 		adminRoutes.POST("/siteCareer", siteCareerController.InsertNewSiteCareer)
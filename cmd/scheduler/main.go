@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"web-scrapper/model"
 	"web-scrapper/repository"
 	"web-scrapper/tasks"
+	"web-scrapper/usecase"
 	"web-scrapper/utils"
 
 	"github.com/hibiken/asynq"
@@ -86,11 +89,18 @@ func main() {
     }
     defer dbConnection.Close()
     siteRepo := repository.NewSiteCareerRepository(dbConnection)
+    siteSchedule := usecase.NewSiteScheduleUsecase(siteRepo)
     jobRepo := repository.NewJobRepository(dbConnection)
     userSiteRepo := repository.NewUserSiteRepository(dbConnection)
     notificationRepo := repository.NewNotificationRepository(dbConnection)
     resetRepo := repository.NewPasswordResetRepository(dbConnection)
 
+    lifecyclePolicy := model.JobLifecyclePolicy{
+        CloseAfter:   time.Duration(envInt("JOB_CLOSE_GRACE_HOURS", 72)) * time.Hour,
+        ArchiveAfter: time.Duration(envInt("JOB_ARCHIVE_AFTER_DAYS", 30)) * 24 * time.Hour,
+        PurgeAfter:   time.Duration(envInt("JOB_RETENTION_DAYS", 365)) * 24 * time.Hour,
+    }
+
     ctx, cancel := context.WithCancel(context.Background())
     defer cancel()
 
@@ -104,9 +114,15 @@ func main() {
 
 	c := cron.New(cron.WithLocation(loc))
 
-	// Scraping: 7h, 9h, 11h, 13h, 15h, 17h (every 2h from 7-17)
-	c.AddFunc("0 7,9,11,13,15,17 * * *", func() {
-		enqueueScrapingTasks(ctx, siteRepo, client)
+	// Scraping: every minute, enqueue the sites whose own schedule is due. A slow
+	// tick is skipped rather than overlapped, so no site is enqueued twice.
+	c.AddJob("* * * * *", cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(func() {
+		enqueueScrapingTasks(ctx, siteSchedule, client)
+	})))
+
+	// Adaptive schedules: hourly, re-pick the interval of adaptive sites from their posting rate
+	c.AddFunc("5 * * * *", func() {
+		adaptScrapeIntervals(siteSchedule)
 	})
 
 	// Match: 8h and 16h
@@ -125,8 +141,8 @@ func main() {
 		wg.Add(2)
 		go func() {
 			defer wg.Done()
-			if err := jobRepo.DeleteOldJobs(); err != nil {
-				logging.Logger.Error().Err(err).Msg("ERROR: failed to delete old jobs")
+			if _, err := jobRepo.UpdateJobLifecycle(lifecyclePolicy); err != nil {
+				logging.Logger.Error().Err(err).Msg("ERROR: failed to update job lifecycle")
 			}
 		}()
 		go func() {
@@ -141,13 +157,16 @@ func main() {
 		wg.Wait()
 	})
 
+	// Bounds changed while the scheduler was down take effect right away
+	adaptScrapeIntervals(siteSchedule)
+
 	c.Start()
 	defer c.Stop()
 
 	for _, entry := range c.Entries() {
 		logging.Logger.Info().Time("next_run", entry.Next).Msg("Scheduled cron entry")
 	}
-	logging.Logger.Info().Msg("Scheduler started with cron expressions (America/Sao_Paulo)")
+	logging.Logger.Info().Msg("Scheduler started with cron expressions (America/Sao_Paulo) and per-site scrape schedules")
 
 	// Block until signal
 	<-sigCh
@@ -155,21 +174,26 @@ func main() {
 	cancel()
 }
 
-func enqueueScrapingTasks(ctx context.Context, siteRepo *repository.SiteCareerRepository, client *asynq.Client) {
-    sites, err := siteRepo.GetAllSites()
+func enqueueScrapingTasks(ctx context.Context, siteSchedule *usecase.SiteScheduleUsecase, client *asynq.Client) {
+    now := time.Now()
+    sites, err := siteSchedule.DueSites(now)
     if err != nil {
         logging.Logger.Error().Err(err).Msg("Scheduler can't get sites from database")
         return
     }
-    logging.Logger.Info().Int("count", len(sites)).Msg("Sites coletados")
+    if len(sites) == 0 {
+        return
+    }
+    logging.Logger.Info().Int("count", len(sites)).Msg("Sites com scraping agendado")
     sem := make(chan struct{}, 10)
     var wg sync.WaitGroup
     for _, site := range sites {
         wg.Add(1)
         sem <- struct{}{}
-        go func(s model.SiteScrapingConfig){
+        go func(due usecase.DueSite){
             defer wg.Done()
             defer func() { <-sem }()
+            s := due.Config
             payload, err := json.Marshal(tasks.ScrapeSitePayload{
                 SiteID: s.ID,
                 SiteScrapingConfig: s,
@@ -179,18 +203,36 @@ func enqueueScrapingTasks(ctx context.Context, siteRepo *repository.SiteCareerRe
                 return
             }
 
-            task := asynq.NewTask(tasks.TypeScrapSite, payload, asynq.MaxRetry(3))
+            // A scrape still queued or running until the next slot is not duplicated
+            task := asynq.NewTask(tasks.TypeScrapSite, payload, asynq.MaxRetry(3), asynq.Unique(due.UniqueFor))
             info, err := client.EnqueueContext(ctx, task)
-            if err != nil {
+            if errors.Is(err, asynq.ErrDuplicateTask) {
+                logging.Logger.Info().Str("site_name", s.SiteName).Msg("Previous scrape of site still pending, skipping")
+            } else if err != nil {
                 logging.Logger.Error().Err(err).Str("site_name", s.SiteName).Msg("Could not enqueue task for site")
+                return
             } else {
                 logging.Logger.Info().Str("site_name", s.SiteName).Str("task_id", info.ID).Msg("Task enqueued for site")
             }
+            if err := siteSchedule.MarkScheduled(s.ID, now); err != nil {
+                logging.Logger.Error().Err(err).Str("site_name", s.SiteName).Msg("Could not mark site as scheduled")
+            }
         }(site)
     }
     wg.Wait()
 }
 
+func adaptScrapeIntervals(siteSchedule *usecase.SiteScheduleUsecase) {
+	changed, err := siteSchedule.AdaptIntervals(time.Now())
+	if err != nil {
+		logging.Logger.Error().Err(err).Msg("ERROR: failed to adapt scrape intervals")
+		return
+	}
+	if changed > 0 {
+		logging.Logger.Info().Int("count", changed).Msg("Adaptive scrape intervals updated")
+	}
+}
+
 func enqueueMatchTasks(ctx context.Context, userSiteRepo *repository.UserSiteRepository, client *asynq.Client) {
 	userIDs, err := userSiteRepo.GetActiveUserIDs()
 	if err != nil {
@@ -262,3 +304,11 @@ func enqueueDigestTasks(ctx context.Context, notificationRepo *repository.Notifi
 	}
 	wg.Wait()
 }
+
+// envInt reads a positive integer from the environment, falling back to def.
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"database/sql"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 	"web-scrapper/gateway"
 	"web-scrapper/infra/db"
+	"web-scrapper/infra/metrics"
 	redispkg "web-scrapper/infra/redis"
 	"web-scrapper/infra/resend"
 	"web-scrapper/infra/ses"
@@ -14,12 +19,15 @@ import (
 	"web-scrapper/model"
 	"web-scrapper/processor"
 	"web-scrapper/repository"
+	"web-scrapper/scrapper"
 	"web-scrapper/tasks"
 	"web-scrapper/usecase"
 	"web-scrapper/utils"
 
 	"github.com/hibiken/asynq"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
@@ -131,20 +139,67 @@ func main() {
 	userRepository := repository.NewUserRepository(dbConnection)
 	planRepository := repository.NewPlanRepository(dbConnection)
 	dashboardRepository := repository.NewDashboardRepository(dbConnection)
+	scrapeRunRepository := repository.NewScrapeRunRepository(dbConnection)
+	siteCareerRepository := repository.NewSiteCareerRepository(dbConnection)
+
+	// Headless browsers are shared by all scrape tasks of this worker
+	browserPool := scrapper.NewBrowserPool(scrapper.BrowserPoolConfig{
+		Size:    envInt("HEADLESS_BROWSER_POOL_SIZE", 2),
+		MaxUses: envInt("HEADLESS_BROWSER_MAX_USES", 25),
+		MaxAge:  time.Duration(envInt("HEADLESS_BROWSER_MAX_AGE_MINUTES", 30)) * time.Minute,
+	})
+	defer browserPool.Close(30 * time.Second)
+
+	metrics.RegisterDBCollector(dbConnection)
+	registerBrowserPoolGauges(browserPool.Stats)
+	// Prometheus metrics endpoint — local only by default; protected by bearer token, like the API's
+	metricsAddr := os.Getenv("WORKER_METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = "127.0.0.1:9091"
+	}
+	metricsToken := os.Getenv("METRICS_TOKEN")
+	if metricsToken == "" && os.Getenv("GIN_MODE") == "release" && !isLoopbackAddr(metricsAddr) {
+		logging.Logger.Fatal().Str("addr", metricsAddr).Msg("METRICS_TOKEN is required to expose worker metrics outside localhost")
+	}
+	go func() {
+		metricsMux := http.NewServeMux()
+		metricsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			if metricsToken != "" && r.Header.Get("Authorization") != "Bearer "+metricsToken {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			promhttp.Handler().ServeHTTP(w, r)
+		})
+		if err := http.ListenAndServe(metricsAddr, metricsMux); err != nil {
+			logging.Logger.Error().Err(err).Str("addr", metricsAddr).Msg("Worker metrics server stopped")
+		}
+	}()
+
+	workerRedisClient, err := redispkg.NewRedisClient(redisAddr)
+	if err != nil {
+		logging.Logger.Fatal().Err(err).Msg("Could not connect to Redis")
+	}
+	defer workerRedisClient.Close()
+
+	// robots.txt and per-domain request spacing, shared with the other workers through Redis
+	politeness := scrapper.NewPoliteness(scrapper.PolitenessConfig{
+		MinInterval: time.Duration(envInt("SCRAPE_DOMAIN_INTERVAL_MS", 250)) * time.Millisecond,
+		MaxWait:     time.Duration(envInt("SCRAPE_DOMAIN_MAX_WAIT_SECONDS", 120)) * time.Second,
+	}, redispkg.NewDomainBudget(workerRedisClient))
 
 	// Services & Usecases
-	jobUsecase := usecase.NewJobUseCase(jobRepository)
+	jobUsecase := usecase.NewJobUseCase(jobRepository, scrapper.WithBrowserPool(browserPool), scrapper.WithPoliteness(politeness))
+	jobUsecase.DetailTTL = time.Duration(envInt("SCRAPE_DETAIL_TTL_HOURS", 168)) * time.Hour
+
+	siteHealthUsecase := usecase.NewSiteHealthUsecase(scrapeRunRepository)
+	siteAnomalyUsecase := usecase.NewSiteAnomalyUsecase(scrapeRunRepository, siteCareerRepository, emailService, os.Getenv("ADMIN_EMAIL"))
+	siteAnomalyUsecase.AutoDisable = os.Getenv("SITE_AUTO_DISABLE") == "true"
 
 	notificationUsecase := usecase.NewNotificationUsecase(userSiteRepository, nil, emailService, notificationRepository, planRepository, userRepository)
 
 	// PaymentUsecase (necessário para HandleCompleteRegistrationTask)
 	abacatepayGateway := gateway.NewAbacatePayGateway()
 	userUsecase := usecase.NewUserUsercase(userRepository)
-	workerRedisClient, err := redispkg.NewRedisClient(redisAddr)
-	if err != nil {
-		logging.Logger.Fatal().Err(err).Msg("Could not connect to Redis for PaymentUsecase")
-	}
-	defer workerRedisClient.Close()
 	paymentUsecase := usecase.NewPaymentUsecase(abacatepayGateway, workerRedisClient, userUsecase, planRepository)
 
 	// TaskProcessor
@@ -155,6 +210,8 @@ func main() {
 		emailService,
 		dashboardRepository,
 		userRepository,
+		siteHealthUsecase,
+		siteAnomalyUsecase,
 	)
 
 	// Mapeamento das Tarefas para os Handlers
@@ -169,3 +226,51 @@ func main() {
 		logging.Logger.Fatal().Err(err).Msg("Could not run asynq server")
 	}
 }
+
+// envInt reads a positive integer from the environment, falling back to def.
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+// isLoopbackAddr reports whether a listen address only accepts local connections.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// registerBrowserPoolGauges registers gauges that track the headless browser pool.
+func registerBrowserPoolGauges(stats func() scrapper.BrowserPoolStats) {
+	gauge := func(name, help string, value func(scrapper.BrowserPoolStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return value(stats())
+		})
+	}
+
+	// The cumulative counts are read from snapshots, so they are gauges named without _total.
+	prometheus.MustRegister(
+		gauge("headless_browser_pool_size", "Maximum number of headless browsers in the pool",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.Size) }),
+		gauge("headless_browser_pool_in_use", "Number of headless browsers currently leased to a scrape",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.InUse) }),
+		gauge("headless_browser_pool_alive", "Number of running Chrome processes owned by the pool",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.Alive) }),
+		gauge("headless_browser_pool_launched", "Number of Chrome processes started by the pool (cumulative)",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.Launched) }),
+		gauge("headless_browser_pool_recycled", "Number of browsers restarted after reaching max uses or max age (cumulative)",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.Recycled) }),
+		gauge("headless_browser_pool_crashed", "Number of browsers restarted after crashing or leaking tabs (cumulative)",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.Crashed) }),
+		gauge("headless_browser_pool_waits", "Number of scrapes that waited for a free browser (cumulative)",
+			func(s scrapper.BrowserPoolStats) float64 { return float64(s.Waits) }),
+	)
+}
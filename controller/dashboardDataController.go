@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"web-scrapper/model"
 	"web-scrapper/repository"
 
@@ -60,7 +63,17 @@ func (repo *DashboardDataController) GetDashboardDataByUserId (ctx *gin.Context)
 // @Param limit query int false "Limite por pagina (max 50)" default(10)
 // @Param days query int false "Filtrar por dias" default(0)
 // @Param search query string false "Buscar por titulo"
+// @Param work_model query string false "Modelo de trabalho" Enums(remote, hybrid, onsite)
+// @Param state query string false "UF da vaga, ex.: SP"
+// @Param city query string false "Cidade da vaga, ex.: Campinas"
+// @Param seniority query string false "Senioridade" Enums(intern, junior, mid, senior, specialist, lead)
+// @Param role_family query string false "Área" Enums(backend, frontend, fullstack, mobile, data, qa, devops, security, design, product)
+// @Param contract_type query string false "Tipo de contrato" Enums(clt, pj, internship, temporary)
+// @Param salary_min query number false "Salário mínimo mensal, comparado ao teto da faixa (exige salary_currency)"
+// @Param salary_currency query string false "Moeda do salário, ex.: BRL; obrigatório com salary_min ou ordenação por salário"
+// @Param sort query string false "Ordenação" Enums(recent, salary_desc, salary_asc) default(recent)
 // @Success 200 {object} model.PaginatedJobs
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security CookieAuth
@@ -79,10 +92,52 @@ func (repo *DashboardDataController) GetLatestJobs(ctx *gin.Context) {
 	}
 
 	days, _ := strconv.Atoi(ctx.DefaultQuery("days", "0"))
-	search := ctx.Query("search")
-	matchedOnly := ctx.DefaultQuery("matched_only", "true") != "false"
+	filters := model.JobFilters{
+		Days:           days,
+		Search:         ctx.Query("search"),
+		MatchedOnly:    ctx.DefaultQuery("matched_only", "true") != "false",
+		WorkModel:      ctx.Query("work_model"),
+		State:          ctx.Query("state"),
+		City:           ctx.Query("city"),
+		Seniority:      ctx.Query("seniority"),
+		RoleFamily:     ctx.Query("role_family"),
+		ContractType:   ctx.Query("contract_type"),
+		SalaryCurrency: ctx.Query("salary_currency"),
+		Sort:           ctx.Query("sort"),
+	}
+	if raw := ctx.Query("salary_min"); raw != "" {
+		salaryMin, err := strconv.ParseFloat(raw, 64)
+		if err != nil || salaryMin < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "salary_min deve ser um número positivo"})
+			return
+		}
+		filters.SalaryMin = salaryMin
+	}
+	for _, param := range []struct {
+		name    string
+		value   string
+		allowed []string
+	}{
+		{"work_model", filters.WorkModel, model.WorkModels},
+		{"seniority", filters.Seniority, model.Seniorities},
+		{"role_family", filters.RoleFamily, model.RoleFamilies},
+		{"contract_type", filters.ContractType, model.ContractTypes},
+		{"sort", filters.Sort, model.JobSorts},
+	} {
+		if param.value != "" && !slices.Contains(param.allowed, param.value) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s deve ser um de: %s", param.name, strings.Join(param.allowed, ", "))})
+			return
+		}
+	}
+
+	// Amounts in different currencies cannot be compared
+	salarySort := filters.Sort == model.JobSortSalaryDesc || filters.Sort == model.JobSortSalaryAsc
+	if (filters.SalaryMin > 0 || salarySort) && strings.TrimSpace(filters.SalaryCurrency) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "salary_currency é obrigatório para filtrar ou ordenar por salário"})
+		return
+	}
 
-	data, err := repo.repo.GetAllJobs(user.Id, days, search, matchedOnly)
+	data, err := repo.repo.GetAllJobs(user.Id, filters)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"web-scrapper/interfaces"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	repo interfaces.JobRepositoryInterface
+}
+
+func NewJobController(repo interfaces.JobRepositoryInterface) *JobController {
+	return &JobController{repo: repo}
+}
+
+// GetJobRevisions godoc
+// @Summary Histórico de edições da vaga
+// @Description Retorna as versões de título, localização e descrição da vaga, da mais recente para a mais antiga. Vagas sem revisões retornam uma lista vazia
+// @Tags Jobs
+// @Produce json
+// @Param id path int true "ID da vaga"
+// @Success 200 {array} model.JobRevision
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/jobs/{id}/revisions [get]
+func (c *JobController) GetJobRevisions(ctx *gin.Context) {
+	jobID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || jobID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da vaga inválido"})
+		return
+	}
+
+	job, err := c.repo.GetJobByID(jobID)
+	if err != nil {
+		logging.Logger.Error().Err(err).Int("job_id", jobID).Msg("Erro ao buscar vaga")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if job == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Vaga não encontrada"})
+		return
+	}
+
+	revisions, err := c.repo.GetJobRevisions(jobID)
+	if err != nil {
+		logging.Logger.Error().Err(err).Int("job_id", jobID).Msg("Erro ao buscar revisões da vaga")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if revisions == nil {
+		revisions = []model.JobRevision{}
+	}
+
+	ctx.JSON(http.StatusOK, revisions)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web-scrapper/model"
+	"web-scrapper/repository/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobController_GetJobRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("should return revisions newest first", func(t *testing.T) {
+		mockJob := new(mocks.MockJobRepository)
+		ctrl := NewJobController(mockJob)
+		mockJob.On("GetJobByID", 42).Return(&model.Job{ID: 42}, nil).Once()
+		mockJob.On("GetJobRevisions", 42).Return([]model.JobRevision{
+			{ID: 2, JobID: 42, Title: "Go Dev Sênior"},
+			{ID: 1, JobID: 42, Title: "Go Dev"},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+		router.GET("/api/jobs/:id/revisions", ctrl.GetJobRevisions)
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/jobs/42/revisions", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var revisions []model.JobRevision
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+		assert.Len(t, revisions, 2)
+		assert.Equal(t, "Go Dev Sênior", revisions[0].Title)
+		mockJob.AssertExpectations(t)
+	})
+
+	t.Run("should return an empty list when job has no revisions", func(t *testing.T) {
+		mockJob := new(mocks.MockJobRepository)
+		ctrl := NewJobController(mockJob)
+		mockJob.On("GetJobByID", 7).Return(&model.Job{ID: 7}, nil).Once()
+		mockJob.On("GetJobRevisions", 7).Return(nil, nil).Once()
+
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+		router.GET("/api/jobs/:id/revisions", ctrl.GetJobRevisions)
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/jobs/7/revisions", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+		mockJob.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 when job does not exist", func(t *testing.T) {
+		mockJob := new(mocks.MockJobRepository)
+		ctrl := NewJobController(mockJob)
+		mockJob.On("GetJobByID", 9).Return(nil, nil).Once()
+
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+		router.GET("/api/jobs/:id/revisions", ctrl.GetJobRevisions)
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/jobs/9/revisions", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockJob.AssertNotCalled(t, "GetJobRevisions", 9)
+	})
+
+	t.Run("should return 400 for invalid id", func(t *testing.T) {
+		ctrl := NewJobController(new(mocks.MockJobRepository))
+
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+		router.GET("/api/jobs/:id/revisions", ctrl.GetJobRevisions)
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/jobs/abc/revisions", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
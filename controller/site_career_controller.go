@@ -2,8 +2,12 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"web-scrapper/logging"
 	"web-scrapper/model"
 	"web-scrapper/repository"
 	"web-scrapper/usecase"
@@ -99,53 +103,289 @@ func (usecase *SiteCareerController) GetAllSites(ctx *gin.Context){
 // @Security CookieAuth
 // @Router /siteCareer [post]
 func (usecase *SiteCareerController) InsertNewSiteCareer(ctx *gin.Context){
+	body, file, ok := bindSiteForm(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := usecase.usecase.InsertNewSiteCareer(ctx, body, file, configAuthor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error" : fmt.Errorf("ERROR to insert new site career:  %w", err).Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// bindSiteForm reads the multipart form of a site config (siteData JSON plus an
+// optional logo), answering the request itself when the form is invalid.
+func bindSiteForm(ctx *gin.Context) (model.SiteScrapingConfig, *multipart.FileHeader, bool) {
 	err := ctx.Request.ParseMultipartForm(2 << 20)
-    if err != nil {
-        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar o formulário"})
-        return
-    }
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar o formulário"})
+		return model.SiteScrapingConfig{}, nil, false
+	}
 
-    file, err := ctx.FormFile("logo")
-    if err != nil && err != http.ErrMissingFile {
-        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar o arquivo de logo"})
-        return
-    }
+	file, err := ctx.FormFile("logo")
+	if err != nil && err != http.ErrMissingFile {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar o arquivo de logo"})
+		return model.SiteScrapingConfig{}, nil, false
+	}
 
 	siteJSON := ctx.Request.FormValue("siteData")
 	var body model.SiteScrapingConfig
 	if err := json.Unmarshal([]byte(siteJSON), &body); err != nil {
-        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados do site em formato JSON inválido"})
-        return
-    }
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados do site em formato JSON inválido"})
+		return model.SiteScrapingConfig{}, nil, false
+	}
 
-	if body.APIHeadersJSON != nil {
-		var unescapedHeaders string
-		if json.Unmarshal([]byte(*body.APIHeadersJSON), &unescapedHeaders) == nil {
-			*body.APIHeadersJSON = unescapedHeaders
+	// JSON columns may arrive as JSON-encoded strings
+	for _, field := range []*string{body.APIHeadersJSON, body.JSONDataMappings, body.HeadlessActions} {
+		if field == nil {
+			continue
+		}
+		var unescaped string
+		if json.Unmarshal([]byte(*field), &unescaped) == nil {
+			*field = unescaped
 		}
 	}
 
-	if body.JSONDataMappings != nil {
-		var unescapedMappings string
-		if json.Unmarshal([]byte(*body.JSONDataMappings), &unescapedMappings) == nil {
-			*body.JSONDataMappings = unescapedMappings
+	return body, file, true
+}
+
+// configAuthor is the admin making the request, recorded with each config version.
+func configAuthor(ctx *gin.Context) model.SiteConfigAuthor {
+	user, _ := ctx.Get("user")
+	if u, ok := user.(model.User); ok {
+		return model.SiteConfigAuthor{UserID: u.Id, Email: u.Email}
+	}
+	return model.SiteConfigAuthor{}
+}
+
+// siteIDParam parses the :id path parameter, answering the request when it is invalid.
+func siteIDParam(ctx *gin.Context) (int, bool) {
+	siteID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || siteID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do site inválido"})
+		return 0, false
+	}
+	return siteID, true
+}
+
+// respondSiteError maps site errors to a status code.
+func respondSiteError(ctx *gin.Context, err error) {
+	if errors.Is(err, model.ErrSiteNotFound) || errors.Is(err, model.ErrSiteVersionNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	logging.Logger.Error().Err(err).Str("path", ctx.FullPath()).Msg("Erro ao alterar configuração do site")
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+}
+
+// UpdateSiteCareer godoc
+// @Summary Atualizar site de carreiras
+// @Description Substitui a configuracao de scraping do site e registra uma nova versao (admin)
+// @Tags Sites
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID do site"
+// @Param siteData formData string true "JSON da configuracao do site"
+// @Param logo formData file false "Logo da empresa"
+// @Success 200 {object} model.SiteScrapingConfig
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id} [put]
+func (usecase *SiteCareerController) UpdateSiteCareer(ctx *gin.Context) {
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
+	}
+	body, file, ok := bindSiteForm(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := usecase.usecase.UpdateSiteCareer(ctx, siteID, body, file, configAuthor(ctx))
+	if err != nil {
+		if errors.Is(err, model.ErrSiteNotFound) {
+			respondSiteError(ctx, err)
+			return
 		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ActivateSite godoc
+// @Summary Ativar site
+// @Description Volta a raspar o site e limpa o motivo de desativacao automatica (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Success 200 {object} model.SiteScrapingConfig
+// @Failure 404 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/activate [post]
+func (usecase *SiteCareerController) ActivateSite(ctx *gin.Context) {
+	usecase.setSiteActive(ctx, true)
+}
+
+// DeactivateSite godoc
+// @Summary Desativar site
+// @Description Para de raspar o site (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Success 200 {object} model.SiteScrapingConfig
+// @Failure 404 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/deactivate [post]
+func (usecase *SiteCareerController) DeactivateSite(ctx *gin.Context) {
+	usecase.setSiteActive(ctx, false)
+}
+
+func (usecase *SiteCareerController) setSiteActive(ctx *gin.Context, active bool) {
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
 	}
 
-	res, err := usecase.usecase.InsertNewSiteCareer(ctx, body, file)
+	res, err := usecase.usecase.SetSiteActive(siteID, active, configAuthor(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error" : fmt.Errorf("ERROR to insert new site career:  %w", err).Error(),
-		})
+		respondSiteError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, res)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteSite godoc
+// @Summary Remover site
+// @Description Remove o site; o historico de versoes e as vagas ja coletadas sao mantidos (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Success 200 {object} model.MessageResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id} [delete]
+func (usecase *SiteCareerController) DeleteSite(ctx *gin.Context) {
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := usecase.usecase.DeleteSite(siteID, configAuthor(ctx)); err != nil {
+		respondSiteError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Site removido com sucesso"})
+}
+
+// GetSiteVersions godoc
+// @Summary Historico de versoes do site
+// @Description Lista as versoes da configuracao do site, da mais recente para a mais antiga (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Success 200 {array} model.SiteConfigVersion
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/versions [get]
+func (usecase *SiteCareerController) GetSiteVersions(ctx *gin.Context) {
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	versions, err := usecase.usecase.GetSiteVersions(siteID)
+	if err != nil {
+		respondSiteError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, versions)
+}
+
+// DiffSiteVersions godoc
+// @Summary Comparar versoes do site
+// @Description Lista os campos da configuracao que mudaram entre duas versoes (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Param from query int true "Versao de origem"
+// @Param to query int false "Versao de destino (padrao: a mais recente)"
+// @Success 200 {object} model.SiteConfigDiff
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/versions/diff [get]
+func (usecase *SiteCareerController) DiffSiteVersions(ctx *gin.Context) {
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
+	}
+	from, err := strconv.Atoi(ctx.Query("from"))
+	if err != nil || from <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Versão de origem inválida"})
+		return
+	}
+	to, err := strconv.Atoi(ctx.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Versão de destino inválida"})
+		return
+	}
+
+	diff, err := usecase.usecase.DiffSiteVersions(siteID, from, to)
+	if err != nil {
+		respondSiteError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// RollbackSite godoc
+// @Summary Restaurar versao do site
+// @Description Restaura a configuracao de uma versao anterior como uma nova versao (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Param version path int true "Versao a restaurar"
+// @Success 200 {object} model.SiteScrapingConfig
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/versions/{version}/rollback [post]
+func (usecase *SiteCareerController) RollbackSite(ctx *gin.Context) {
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Versão inválida"})
+		return
+	}
+
+	res, err := usecase.usecase.RollbackSite(siteID, version, configAuthor(ctx))
+	if err != nil {
+		respondSiteError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
 }
 
 // SandboxScrape godoc
 // @Summary Testar scraping (sandbox)
-// @Description Executa scraping de teste com a configuracao fornecida (admin)
+// @Description Executa scraping de teste com a configuracao fornecida e retorna um diagnostico (requisicoes, seletores, preenchimento dos campos) (admin)
 // @Tags Sites
 // @Accept json
 // @Produce json
@@ -162,28 +402,75 @@ func (usecase *SiteCareerController) SandboxScrape(ctx *gin.Context){
 		return
 	}
 
-	scrapedJobs, err := usecase.usecase.SandboxScrape(ctx, config)
+	scrapedJobs, diagnostics, err := usecase.usecase.SandboxScrape(ctx, config)
 	if err != nil {
         ctx.JSON(http.StatusInternalServerError, gin.H{
-            "success": false,
-            "error":   err.Error(),
-            "message": "Falha ao executar o scraping com a configuração fornecida.",
+            "success":     false,
+            "error":       err.Error(),
+            "message":     "Falha ao executar o scraping com a configuração fornecida.",
+            "diagnostics": diagnostics,
         })
         return
     }
 
 	if len(scrapedJobs) == 0 {
         ctx.JSON(http.StatusOK, gin.H{
-            "success": true,
-            "message": "A configuração funcionou, mas nenhuma vaga foi encontrada na primeira página.",
-            "data":    []model.Job{},
+            "success":     true,
+            "message":     "A configuração funcionou, mas nenhuma vaga foi encontrada na primeira página.",
+            "data":        []model.Job{},
+            "diagnostics": diagnostics,
         })
         return
     }
 
     ctx.JSON(http.StatusOK, gin.H{
-        "success": true,
-        "message": fmt.Sprintf("%d vagas encontradas com sucesso.", len(scrapedJobs)),
-        "data":    scrapedJobs,
+        "success":     true,
+        "message":     fmt.Sprintf("%d vagas encontradas com sucesso.", len(scrapedJobs)),
+        "data":        scrapedJobs,
+        "diagnostics": diagnostics,
     })
-}
\ No newline at end of file
+}
+
+// RevalidateSite godoc
+// @Summary Revalidar site (sandbox)
+// @Description Executa a configuracao salva do site no sandbox e, se vagas forem encontradas, reativa o site desativado automaticamente (admin)
+// @Tags Sites
+// @Produce json
+// @Param id path int true "ID do site"
+// @Success 200 {object} model.SandboxScrapeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.SandboxScrapeErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/revalidate [post]
+func (usecase *SiteCareerController) RevalidateSite(ctx *gin.Context){
+	siteID, ok := siteIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	scrapedJobs, reactivated, err := usecase.usecase.RevalidateSite(ctx, siteID, configAuthor(ctx))
+	if errors.Is(err, model.ErrSiteNotFound) {
+		respondSiteError(ctx, err)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Falha ao revalidar a configuração do site.",
+		})
+		return
+	}
+
+	message := fmt.Sprintf("%d vagas encontradas; site reativado.", len(scrapedJobs))
+	if !reactivated {
+		message = "Site não reativado: a configuração não encontrou vagas válidas ou o site não foi desativado automaticamente."
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"reactivated": reactivated,
+		"message":     message,
+		"data":        scrapedJobs,
+	})
+}
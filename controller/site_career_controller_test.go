@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web-scrapper/model"
+	"web-scrapper/repository/mocks"
+	"web-scrapper/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteCareerController_RevalidateSite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("should return 404 for an unknown site", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		mockRepo.On("GetSiteByID", 99).Return(model.SiteScrapingConfig{}, fmt.Errorf("site 99: %w", model.ErrSiteNotFound)).Once()
+		ctrl := NewSiteCareerController(usecase.NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader)), nil)
+
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+		router.POST("/api/admin/sites/:id/revalidate", ctrl.RevalidateSite)
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/admin/sites/99/revalidate", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+}
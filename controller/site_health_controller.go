@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+	"web-scrapper/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SiteHealthController struct {
+	usecase *usecase.SiteHealthUsecase
+}
+
+func NewSiteHealthController(usecase *usecase.SiteHealthUsecase) *SiteHealthController {
+	return &SiteHealthController{
+		usecase: usecase,
+	}
+}
+
+// GetSitesHealth godoc
+// @Summary Saúde dos sites
+// @Description Retorna o status de saúde de cada site, derivado das últimas execuções de scraping (somente admin)
+// @Tags Admin
+// @Produce json
+// @Param status query string false "Filtra por status (healthy, degraded, failing, unknown)"
+// @Success 200 {array} model.SiteHealth
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/health [get]
+func (c *SiteHealthController) GetSitesHealth(ctx *gin.Context) {
+	status := ctx.Query("status")
+	switch status {
+	case "", model.SiteHealthHealthy, model.SiteHealthDegraded, model.SiteHealthFailing, model.SiteHealthUnknown:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+		return
+	}
+
+	sites, err := c.usecase.GetSitesHealth(status)
+	if err != nil {
+		logging.Logger.Error().Err(err).Msg("Erro ao buscar saúde dos sites")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sites)
+}
+
+// GetSiteRuns godoc
+// @Summary Histórico de execuções de um site
+// @Description Lista as últimas execuções de scraping do site, da mais recente para a mais antiga (somente admin)
+// @Tags Admin
+// @Produce json
+// @Param id path int true "ID do site"
+// @Param limit query int false "Quantidade de execuções (padrão 50, máximo 500)"
+// @Success 200 {array} model.ScrapeRun
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security CookieAuth
+// @Router /api/admin/sites/{id}/runs [get]
+func (c *SiteHealthController) GetSiteRuns(ctx *gin.Context) {
+	siteID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || siteID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do site inválido"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Limite inválido"})
+		return
+	}
+
+	runs, err := c.usecase.GetSiteRuns(siteID, limit)
+	if err != nil {
+		logging.Logger.Error().Err(err).Int("site_id", siteID).Msg("Erro ao buscar execuções de scraping do site")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"web-scrapper/model"
@@ -52,7 +53,11 @@ func (usecase *UserSiteController) InsertUserSite(ctx *gin.Context) {
 		return
 	}
 
-	err := usecase.usecase.InsertUserSite(user.Id, body.SiteId, body.TargetWords)
+	err := usecase.usecase.InsertUserSite(user.Id, body.SiteId, body.TargetWords, body.JobClassFilters)
+	if errors.Is(err, model.ErrInvalidJobClassFilter) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao inscrever usuário no site"})
 		return
@@ -104,12 +109,12 @@ func (usc *UserSiteController) DeleteUserSite(ctx *gin.Context) {
 
 // UpdateUserSiteFilters godoc
 // @Summary Atualizar filtros do site
-// @Description Atualiza as palavras-chave de filtragem para um site inscrito
+// @Description Atualiza as palavras-chave e os filtros de senioridade, área e contrato para um site inscrito
 // @Tags UserSite
 // @Accept json
 // @Produce json
 // @Param siteId path string true "ID do site"
-// @Param body body model.UpdateUserSiteFiltersRequest true "Palavras-chave"
+// @Param body body model.UpdateUserSiteFiltersRequest true "Palavras-chave e filtros de classificação"
 // @Success 200 {object} model.MessageResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
@@ -141,16 +146,18 @@ func (usc *UserSiteController) UpdateUserSiteFilters(ctx *gin.Context) {
 		return
 	}
 
-	var body struct {
-		TargetWords []string `json:"target_words"`
-	}
+	var body model.UpdateUserSiteFiltersRequest
 	if err := ctx.ShouldBindJSON(&body); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido: " + err.Error()})
 		return
 	}
 
-	if err := usc.usecase.UpdateUserSiteFilters(user.Id, siteId, body.TargetWords); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := usc.usecase.UpdateUserSiteFilters(user.Id, siteId, body.TargetWords, body.JobClassFilters); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, model.ErrInvalidJobClassFilter) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
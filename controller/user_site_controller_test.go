@@ -31,7 +31,7 @@ func TestUserSiteController_InsertUserSite(t *testing.T) {
 		plan := &model.Plan{ID: 1, MaxSites: 10}
 		mockPlanRepo.On("GetPlanByUserID", 1).Return(plan, nil).Once()
 		mockUserSiteRepo.On("GetUserSiteCount", 1).Return(2, nil).Once()
-		mockUserSiteRepo.On("InsertNewUserSite", 1, 5, []string{"golang"}).Return(nil).Once()
+		mockUserSiteRepo.On("InsertNewUserSite", 1, 5, []string{"golang"}, model.JobClassFilters{}).Return(nil).Once()
 
 		body, _ := json.Marshal(model.UserSiteRequest{SiteId: 5, TargetWords: []string{"golang"}})
 		w := httptest.NewRecorder()
@@ -107,7 +107,7 @@ func TestUserSiteController_UpdateUserSiteFilters(t *testing.T) {
 	t.Run("should update filters successfully", func(t *testing.T) {
 		ctrl, mockUserSiteRepo, _ := setupUserSiteController()
 
-		mockUserSiteRepo.On("UpdateUserSiteFilters", 1, 10, []string{"go", "backend"}).Return(nil).Once()
+		mockUserSiteRepo.On("UpdateUserSiteFilters", 1, 10, []string{"go", "backend"}, model.JobClassFilters{}).Return(nil).Once()
 
 		body, _ := json.Marshal(map[string]interface{}{"target_words": []string{"go", "backend"}})
 		w := httptest.NewRecorder()
@@ -125,4 +125,47 @@ func TestUserSiteController_UpdateUserSiteFilters(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		mockUserSiteRepo.AssertExpectations(t)
 	})
+
+	t.Run("should update classification filters", func(t *testing.T) {
+		ctrl, mockUserSiteRepo, _ := setupUserSiteController()
+
+		classFilters := model.JobClassFilters{Seniorities: []string{"senior"}, RoleFamilies: []string{"backend"}}
+		mockUserSiteRepo.On("UpdateUserSiteFilters", 1, 10, []string{"go"}, classFilters).Return(nil).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{"target_words": []string{"go"}, "seniorities": []string{"senior"}, "role_families": []string{"backend"}})
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+
+		router.PATCH("/userSite/:siteId", func(c *gin.Context) {
+			setUserContext(c, user)
+			ctrl.UpdateUserSiteFilters(c)
+		})
+
+		req := httptest.NewRequest("PATCH", "/userSite/10", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserSiteRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for unknown classification values", func(t *testing.T) {
+		ctrl, mockUserSiteRepo, _ := setupUserSiteController()
+
+		body, _ := json.Marshal(map[string]interface{}{"target_words": []string{"go"}, "contract_types": []string{"cooperado"}})
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+
+		router.PATCH("/userSite/:siteId", func(c *gin.Context) {
+			setUserContext(c, user)
+			ctrl.UpdateUserSiteFilters(c)
+		})
+
+		req := httptest.NewRequest("PATCH", "/userSite/10", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserSiteRepo.AssertNotCalled(t, "UpdateUserSiteFilters")
+	})
 }
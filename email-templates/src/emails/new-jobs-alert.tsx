@@ -15,6 +15,9 @@ export default function NewJobsAlertEmail() {
         <Text style={jobTitle}>{'{{.Title}}'}</Text>
         <Text style={jobDetail}>{'{{.Company}} — {{.Location}}'}</Text>
         <Link href="{{.JobLink}}" style={jobLink}>Ver vaga →</Link>
+        <Text style={hidden}>{'{{range .AlternateLinks}}'}</Text>
+        <Link href="{{.}}" style={alternateLink}>Também publicada em outro site →</Link>
+        <Text style={hidden}>{'{{end}}'}</Text>
       </Section>
       <Text style={hidden}>{'{{end}}'}</Text>
 
@@ -69,6 +72,13 @@ const jobLink: React.CSSProperties = {
   fontWeight: 600,
   textDecoration: 'none',
 }
+const alternateLink: React.CSSProperties = {
+  display: 'block',
+  fontSize: '12px',
+  color: '#a1a1aa',
+  textDecoration: 'none',
+  marginTop: '4px',
+}
 const hidden: React.CSSProperties = {
   display: 'none',
   fontSize: 0,
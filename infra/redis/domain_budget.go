@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// luaReserveSlot books the next request slot of a host. The key holds the unix
+// time (ms) of the next free slot; it returns the wait in ms, or -1 without
+// booking when the wait would exceed max_wait.
+var luaReserveSlot = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local max_wait = tonumber(ARGV[3])
+local slot = tonumber(redis.call("GET", key) or "0")
+if slot < now then
+    slot = now
+end
+local wait = slot - now
+if wait > max_wait then
+    return -1
+end
+redis.call("SET", key, slot + interval, "PX", wait + interval + 1000)
+return wait
+`)
+
+// DomainBudget spaces requests to each host across every worker sharing the Redis.
+type DomainBudget struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewDomainBudget(client *redis.Client) *DomainBudget {
+	return &DomainBudget{client: client, prefix: "scrape_budget:"}
+}
+
+func (b *DomainBudget) Reserve(ctx context.Context, host string, interval time.Duration, maxWait time.Duration) (time.Duration, bool, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	wait, err := luaReserveSlot.Run(ctx, b.client, []string{b.prefix + host}, now, interval.Milliseconds(), maxWait.Milliseconds()).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	if wait < 0 {
+		return 0, false, nil
+	}
+	return time.Duration(wait) * time.Millisecond, true, nil
+}
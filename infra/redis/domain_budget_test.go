@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainBudget_Reserve(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	// Two workers share the budget of acme.com
+	workerA := NewDomainBudget(client)
+	workerB := NewDomainBudget(client)
+	ctx := context.Background()
+
+	wait, ok, err := workerA.Reserve(ctx, "acme.com", time.Second, 5*time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, wait)
+
+	wait, ok, err = workerB.Reserve(ctx, "acme.com", time.Second, 5*time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, time.Second, wait, float64(100*time.Millisecond))
+
+	// Other hosts have their own budget
+	wait, ok, err = workerB.Reserve(ctx, "globex.com", time.Second, 5*time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, wait)
+}
+
+func TestDomainBudget_ReserveBeyondMaxWait(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	budget := NewDomainBudget(client)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, ok, err := budget.Reserve(ctx, "acme.com", time.Second, 2*time.Second)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	_, ok, err := budget.Reserve(ctx, "acme.com", time.Second, 2*time.Second)
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
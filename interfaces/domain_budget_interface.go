@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// DomainBudgetInterface spaces requests to the same host across all workers.
+type DomainBudgetInterface interface {
+	// Reserve books the next request to host, interval after the previous one, and
+	// returns how long to wait before sending it. ok is false (and nothing is
+	// booked) when that wait would exceed maxWait.
+	Reserve(ctx context.Context, host string, interval time.Duration, maxWait time.Duration) (wait time.Duration, ok bool, err error)
+}
@@ -10,4 +10,5 @@ type EmailService interface {
 	SendWelcomeEmail(ctx context.Context, userEmail, userName, dashboardLink string) error
 	SendNewJobsEmail(ctx context.Context, userEmail string, userName string, jobs []*model.Job) error
 	SendPasswordResetEmail(ctx context.Context, email, userName, resetLink string) error
+	SendSiteAnomalyEmail(ctx context.Context, adminEmail string, anomaly model.SiteAnomaly) error
 }
@@ -6,9 +6,16 @@ import (
 
 type JobRepositoryInterface interface {
 	CreateJob(job model.Job) (int, error)
-	FindJobByRequisitionID(requisition_ID string) (bool, error)
-	FindJobsByRequisitionIDs(requisition_IDs []string) (map[string]bool, error)
-	UpdateLastSeen(requisition_ID string) (int, error)
-	DeleteOldJobs() error
+	FindJobByRequisitionID(siteID int, requisition_ID string) (bool, error)
+	FindJobsByRequisitionIDs(siteID int, requisition_IDs []string) (map[string]string, error)
+	FindKnownJobs(siteID int) ([]model.KnownJob, error)
+	CountMissingJobs(siteID int, seenIDs []string) (int, error)
+	UpdateLastSeen(siteID int, requisition_ID string) (int, error)
+	MarkDetailsFetched(jobIDs []int) error
+	UpdateJobContent(jobID int, job model.Job) error
+	GetJobRevisions(jobID int) ([]model.JobRevision, error)
+	UpdateJobLifecycle(policy model.JobLifecyclePolicy) (model.JobLifecycleResult, error)
 	GetJobByID(jobID int) (*model.Job, error)
+	FindDuplicateCandidates(siteID int, companyKey string) ([]model.Job, error)
+	SetCanonicalJob(jobID int, canonicalJobID int) error
 }
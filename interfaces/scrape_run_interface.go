@@ -0,0 +1,9 @@
+package interfaces
+
+import "web-scrapper/model"
+
+type ScrapeRunRepositoryInterface interface {
+	RecordRun(run model.ScrapeRun) error
+	GetSiteRuns(siteID int, limit int) ([]model.ScrapeRun, error)
+	GetRecentRunsBySite(limit int) ([]model.SiteRunHistory, error)
+}
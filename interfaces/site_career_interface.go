@@ -1,8 +1,22 @@
 package interfaces
 
-import "web-scrapper/model"
+import (
+	"time"
+	"web-scrapper/model"
+)
 
 type SiteCareerRepositoryInterface interface {
-	InsertNewSiteCareer(site model.SiteScrapingConfig) (model.SiteScrapingConfig, error)
+	InsertNewSiteCareer(site model.SiteScrapingConfig, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error)
 	GetAllSites() ([]model.SiteScrapingConfig, error)
-}
\ No newline at end of file
+	GetSiteByID(siteID int) (model.SiteScrapingConfig, error)
+	UpdateSiteCareer(site model.SiteScrapingConfig, changeType string, note string, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error)
+	SetSiteActive(siteID int, active bool, note string, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error)
+	DisableSite(siteID int, reason string) (bool, error)
+	DeleteSite(siteID int, author model.SiteConfigAuthor) error
+	GetSiteVersions(siteID int) ([]model.SiteConfigVersion, error)
+	GetSiteVersion(siteID int, version int) (model.SiteConfigVersion, error)
+	GetScheduledSites() ([]model.ScheduledSite, error)
+	MarkSiteScheduled(siteID int, at time.Time) error
+	GetSitePostingStats(since time.Time) ([]model.SitePostingStats, error)
+	SetAdaptiveInterval(siteID int, minutes int) error
+}
@@ -4,10 +4,10 @@ import "web-scrapper/model"
 
 type UserSiteRepositoryInterface interface {
 	GetUsersBySiteId(siteId int) ([]model.UserSiteCurriculum, error)
-	InsertNewUserSite(userId int, siteId int, filters []string) error
+	InsertNewUserSite(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error
 	GetSubscribedSiteIDs(userId int) (map[int]bool, error)
 	DeleteUserSite(userId int, siteId string) error
-	UpdateUserSiteFilters(userId int, siteId int, filters []string) error
+	UpdateUserSiteFilters(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error
 	GetUserSiteCount(userID int) (int, error)
 	GetActiveUserIDs() ([]int, error)
 }
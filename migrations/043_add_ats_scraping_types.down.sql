@@ -0,0 +1,2 @@
+-- Postgres cannot drop enum values; the ATS values stay in scraping_strategy.
+ALTER TABLE site_scraping_config DROP COLUMN IF EXISTS ats_slug;
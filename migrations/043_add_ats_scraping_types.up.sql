@@ -0,0 +1,7 @@
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'GREENHOUSE';
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'LEVER';
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'GUPY';
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'WORKDAY';
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'EIGHTFOLD';
+
+ALTER TABLE site_scraping_config ADD COLUMN IF NOT EXISTS ats_slug TEXT;
@@ -0,0 +1,8 @@
+-- Postgres cannot drop enum values; 'JSONLD' stays in scraping_strategy.
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS employment_type,
+    DROP COLUMN IF EXISTS date_posted,
+    DROP COLUMN IF EXISTS salary_min,
+    DROP COLUMN IF EXISTS salary_max,
+    DROP COLUMN IF EXISTS salary_currency,
+    DROP COLUMN IF EXISTS salary_period;
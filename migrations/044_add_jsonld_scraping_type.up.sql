@@ -0,0 +1,9 @@
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'JSONLD';
+
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS employment_type TEXT,
+    ADD COLUMN IF NOT EXISTS date_posted TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS salary_min NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS salary_max NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS salary_currency TEXT,
+    ADD COLUMN IF NOT EXISTS salary_period TEXT;
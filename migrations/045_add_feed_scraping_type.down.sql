@@ -0,0 +1,4 @@
+-- Postgres cannot drop enum values; 'FEED' stays in scraping_strategy.
+ALTER TABLE site_scraping_config
+    DROP COLUMN IF EXISTS url_pattern,
+    DROP COLUMN IF EXISTS max_age_days;
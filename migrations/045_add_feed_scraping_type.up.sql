@@ -0,0 +1,5 @@
+ALTER TYPE scraping_strategy ADD VALUE IF NOT EXISTS 'FEED';
+
+ALTER TABLE site_scraping_config
+    ADD COLUMN IF NOT EXISTS url_pattern TEXT,
+    ADD COLUMN IF NOT EXISTS max_age_days INT;
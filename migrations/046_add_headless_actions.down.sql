@@ -0,0 +1 @@
+ALTER TABLE site_scraping_config DROP COLUMN IF EXISTS headless_actions;
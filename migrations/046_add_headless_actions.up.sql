@@ -0,0 +1 @@
+ALTER TABLE site_scraping_config ADD COLUMN IF NOT EXISTS headless_actions JSONB;
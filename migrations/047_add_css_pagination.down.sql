@@ -0,0 +1,3 @@
+ALTER TABLE site_scraping_config
+    DROP COLUMN IF EXISTS max_pages,
+    DROP COLUMN IF EXISTS page_url_template;
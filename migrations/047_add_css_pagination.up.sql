@@ -0,0 +1,3 @@
+ALTER TABLE site_scraping_config
+    ADD COLUMN IF NOT EXISTS max_pages INT CHECK (max_pages IS NULL OR max_pages BETWEEN 1 AND 200),
+    ADD COLUMN IF NOT EXISTS page_url_template TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scraping_policy_skips_created_at;
+DROP TABLE IF EXISTS scraping_policy_skips;
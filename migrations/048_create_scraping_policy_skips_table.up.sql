@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS scraping_policy_skips (
+    id SERIAL PRIMARY KEY,
+    site_id INTEGER REFERENCES site_scraping_config(id) ON DELETE SET NULL,
+    site_name VARCHAR(255) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    url TEXT NOT NULL,
+    task_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_scraping_policy_skips_created_at ON scraping_policy_skips(created_at);
@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS requisition_id_synthetic BOOLEAN NOT NULL DEFAULT FALSE;
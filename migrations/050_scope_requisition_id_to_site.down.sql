@@ -0,0 +1,23 @@
+-- Jobs of different sites may now share a requisition ID. Deleting them would
+-- cascade to their applications and analyses, so stop here and let them be
+-- resolved by hand first.
+DO $$
+DECLARE
+    shared TEXT;
+BEGIN
+    SELECT string_agg(requisition_id, ', ') INTO shared
+    FROM (
+        SELECT requisition_id FROM jobs
+        WHERE requisition_id IS NOT NULL AND requisition_id != ''
+        GROUP BY requisition_id
+        HAVING COUNT(*) > 1
+        ORDER BY requisition_id
+        LIMIT 20
+    ) collisions;
+    IF shared IS NOT NULL THEN
+        RAISE EXCEPTION 'requisition_id shared across site_ids, resolve these jobs before rolling back: %', shared;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS uq_jobs_site_requisition_id;
+CREATE UNIQUE INDEX uq_jobs_requisition_id ON jobs (requisition_id) WHERE requisition_id IS NOT NULL AND requisition_id != '';
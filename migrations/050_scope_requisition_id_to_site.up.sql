@@ -0,0 +1,13 @@
+-- Jobs created before 021 have no site_id: recover it from the company name
+UPDATE jobs j SET site_id = s.id
+FROM site_scraping_config s
+WHERE j.site_id IS NULL AND j.company = s.site_name;
+
+-- Identity is now (site, requisition ID). The global index never let a second
+-- site store a job whose ID was taken, so no rows collide yet: a colliding ID
+-- only shows up as the first site's row being refreshed by the other, and from
+-- now on each site gets its own row on its next scrape.
+-- 040 made the global uniqueness a partial index, not a constraint
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS uq_jobs_requisition_id;
+DROP INDEX IF EXISTS uq_jobs_requisition_id;
+CREATE UNIQUE INDEX uq_jobs_site_requisition_id ON jobs (site_id, requisition_id) WHERE requisition_id IS NOT NULL AND requisition_id != '';
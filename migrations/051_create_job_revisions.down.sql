@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS job_revisions;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS content_updated_at;
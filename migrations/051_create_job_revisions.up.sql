@@ -0,0 +1,31 @@
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS content_hash TEXT,
+    ADD COLUMN IF NOT EXISTS content_updated_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS job_revisions (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    location TEXT,
+    description TEXT,
+    content_hash TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_revisions_job_id ON job_revisions(job_id, created_at DESC);
+
+-- Hash the stored content the way the scraper does (usecase contentHash: each field
+-- with whitespace runs collapsed to one space, joined by newlines), so the first
+-- scrape after deploy only rewrites jobs whose content really changed.
+UPDATE jobs SET content_hash = encode(sha256(convert_to(
+    btrim(regexp_replace(COALESCE(title, ''), '\s+', ' ', 'g'), ' ') || E'\n' ||
+    btrim(regexp_replace(COALESCE(location, ''), '\s+', ' ', 'g'), ' ') || E'\n' ||
+    btrim(regexp_replace(COALESCE(description, ''), '\s+', ' ', 'g'), ' '),
+    'UTF8')), 'hex')
+WHERE content_hash IS NULL;
+
+-- Baseline revision: the content each job had when history started
+INSERT INTO job_revisions (job_id, title, location, description, content_hash, created_at)
+SELECT j.id, j.title, j.location, j.description, j.content_hash, COALESCE(j.created_at, NOW())
+FROM jobs j
+WHERE NOT EXISTS (SELECT 1 FROM job_revisions r WHERE r.job_id = j.id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_jobs_status_last_seen;
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS chk_jobs_status;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS first_seen_at,
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS archived_at;
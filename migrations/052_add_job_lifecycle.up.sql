@@ -0,0 +1,14 @@
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'open',
+    ADD COLUMN IF NOT EXISTS first_seen_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+ALTER TABLE jobs ADD CONSTRAINT chk_jobs_status CHECK (status IN ('open', 'closed', 'archived'));
+
+UPDATE jobs SET first_seen_at = COALESCE(created_at, last_seen_at, NOW()) WHERE first_seen_at IS NULL;
+ALTER TABLE jobs ALTER COLUMN first_seen_at SET DEFAULT NOW();
+ALTER TABLE jobs ALTER COLUMN first_seen_at SET NOT NULL;
+
+-- Lifecycle sweeps look for open jobs not seen lately and closed/archived jobs past their age
+CREATE INDEX IF NOT EXISTS idx_jobs_status_last_seen ON jobs(status, last_seen_at);
@@ -0,0 +1,5 @@
+-- When the job's detail page was last fetched; listing-only runs skip the fetch while this is fresh
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS details_fetched_at TIMESTAMPTZ;
+
+-- Until now every run fetched every detail page
+UPDATE jobs SET details_fetched_at = last_seen_at WHERE details_fetched_at IS NULL;
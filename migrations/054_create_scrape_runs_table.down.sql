@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scrape_runs_site_started;
+DROP TABLE IF EXISTS scrape_runs;
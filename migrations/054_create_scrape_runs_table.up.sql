@@ -0,0 +1,20 @@
+-- One row per scrape of a site, successful or not, so result counts can be followed over time
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id SERIAL PRIMARY KEY,
+    site_id INTEGER REFERENCES site_scraping_config(id) ON DELETE CASCADE,
+    site_name VARCHAR(255) NOT NULL,
+    task_id VARCHAR(255),
+    strategy VARCHAR(20) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    listed_count INTEGER NOT NULL DEFAULT 0,
+    new_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    missing_count INTEGER NOT NULL DEFAULT 0,
+    detail_failures INTEGER NOT NULL DEFAULT 0,
+    error_class VARCHAR(20),
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_scrape_runs_site_started ON scrape_runs(site_id, started_at DESC);
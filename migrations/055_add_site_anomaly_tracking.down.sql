@@ -0,0 +1,5 @@
+ALTER TABLE site_scraping_config
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS disabled_reason;
+
+ALTER TABLE scrape_runs DROP COLUMN IF EXISTS empty_title_count;
@@ -0,0 +1,7 @@
+-- Jobs listed without a title, a sign the title selector no longer matches
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS empty_title_count INTEGER NOT NULL DEFAULT 0;
+
+-- Why and when a site was switched off automatically; cleared when an admin re-validates it
+ALTER TABLE site_scraping_config
+    ADD COLUMN IF NOT EXISTS disabled_reason TEXT,
+    ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
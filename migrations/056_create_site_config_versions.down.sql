@@ -0,0 +1,4 @@
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_site_id_fkey;
+ALTER TABLE jobs ADD CONSTRAINT jobs_site_id_fkey FOREIGN KEY (site_id) REFERENCES site_scraping_config(id);
+
+DROP TABLE IF EXISTS site_config_versions;
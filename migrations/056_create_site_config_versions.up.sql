@@ -0,0 +1,30 @@
+-- Every change to a site's scraping config, as an immutable snapshot. No foreign key on
+-- site_id so the history outlives the site.
+CREATE TABLE IF NOT EXISTS site_config_versions (
+    id SERIAL PRIMARY KEY,
+    site_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    change_type VARCHAR(20) NOT NULL,
+    config JSONB NOT NULL,
+    changed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    changed_by_email VARCHAR(255),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_site_config_versions_site_version UNIQUE (site_id, version)
+);
+
+-- Sites created before versioning start at version 1. JSONB columns are kept as text,
+-- like the application stores them.
+INSERT INTO site_config_versions (site_id, version, change_type, config, note)
+SELECT sc.id, 1, 'create',
+       to_jsonb(sc) || jsonb_build_object(
+           'api_headers_json', sc.api_headers_json::text,
+           'json_data_mappings', sc.json_data_mappings::text,
+           'headless_actions', sc.headless_actions::text),
+       'snapshot when versioning was enabled'
+FROM site_scraping_config sc
+ON CONFLICT DO NOTHING;
+
+-- Deleting a site keeps its jobs (users may have applied to them)
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_site_id_fkey;
+ALTER TABLE jobs ADD CONSTRAINT jobs_site_id_fkey FOREIGN KEY (site_id) REFERENCES site_scraping_config(id) ON DELETE SET NULL;
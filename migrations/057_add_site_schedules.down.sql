@@ -0,0 +1,8 @@
+ALTER TABLE site_scraping_config
+    DROP CONSTRAINT IF EXISTS site_scraping_config_one_schedule,
+    DROP COLUMN IF EXISTS last_scheduled_at,
+    DROP COLUMN IF EXISTS schedule_timezone,
+    DROP COLUMN IF EXISTS schedule_window_end,
+    DROP COLUMN IF EXISTS schedule_window_start,
+    DROP COLUMN IF EXISTS schedule_interval_minutes,
+    DROP COLUMN IF EXISTS schedule_cron;
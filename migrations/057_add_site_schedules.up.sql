@@ -0,0 +1,11 @@
+-- Per-site scrape schedule: a cron expression or an interval, optionally limited to a
+-- daily window, in the site's timezone. Sites with neither use the default cron.
+ALTER TABLE site_scraping_config
+    ADD COLUMN IF NOT EXISTS schedule_cron TEXT,
+    ADD COLUMN IF NOT EXISTS schedule_interval_minutes INT CHECK (schedule_interval_minutes IS NULL OR schedule_interval_minutes BETWEEN 5 AND 1440),
+    ADD COLUMN IF NOT EXISTS schedule_window_start TEXT,
+    ADD COLUMN IF NOT EXISTS schedule_window_end TEXT,
+    ADD COLUMN IF NOT EXISTS schedule_timezone TEXT,
+    -- When the scheduler last enqueued the site; the next run is computed from it
+    ADD COLUMN IF NOT EXISTS last_scheduled_at TIMESTAMPTZ,
+    ADD CONSTRAINT site_scraping_config_one_schedule CHECK (schedule_cron IS NULL OR schedule_interval_minutes IS NULL);
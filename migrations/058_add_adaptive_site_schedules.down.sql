@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_jobs_site_created_at;
+
+ALTER TABLE site_scraping_config
+    DROP CONSTRAINT IF EXISTS site_scraping_config_adaptive_bounds,
+    DROP COLUMN IF EXISTS adaptive_interval_updated_at,
+    DROP COLUMN IF EXISTS adaptive_interval_minutes,
+    DROP COLUMN IF EXISTS schedule_max_interval_minutes,
+    DROP COLUMN IF EXISTS schedule_min_interval_minutes;
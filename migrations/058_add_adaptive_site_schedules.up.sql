@@ -0,0 +1,11 @@
+-- Adaptive schedule: the scheduler picks the site's interval between these bounds
+-- from how often it posts jobs and how many users follow it
+ALTER TABLE site_scraping_config
+    ADD COLUMN IF NOT EXISTS schedule_min_interval_minutes INT CHECK (schedule_min_interval_minutes IS NULL OR schedule_min_interval_minutes BETWEEN 5 AND 1440),
+    ADD COLUMN IF NOT EXISTS schedule_max_interval_minutes INT CHECK (schedule_max_interval_minutes IS NULL OR schedule_max_interval_minutes BETWEEN 5 AND 1440),
+    -- The interval last picked, and when
+    ADD COLUMN IF NOT EXISTS adaptive_interval_minutes INT,
+    ADD COLUMN IF NOT EXISTS adaptive_interval_updated_at TIMESTAMPTZ,
+    ADD CONSTRAINT site_scraping_config_adaptive_bounds CHECK (schedule_min_interval_minutes IS NULL OR schedule_max_interval_minutes IS NULL OR schedule_min_interval_minutes <= schedule_max_interval_minutes);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_site_created_at ON jobs(site_id, created_at DESC);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_jobs_work_model;
+DROP INDEX IF EXISTS idx_jobs_location_state_city;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS work_model,
+    DROP COLUMN IF EXISTS location_country,
+    DROP COLUMN IF EXISTS location_state,
+    DROP COLUMN IF EXISTS location_city;
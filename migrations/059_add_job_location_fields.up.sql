@@ -0,0 +1,9 @@
+-- Structured location and work model, parsed from the scraped location and description
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS location_city VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS location_state CHAR(2),
+    ADD COLUMN IF NOT EXISTS location_country CHAR(2),
+    ADD COLUMN IF NOT EXISTS work_model VARCHAR(10) CHECK (work_model IS NULL OR work_model IN ('remote', 'hybrid', 'onsite'));
+
+CREATE INDEX IF NOT EXISTS idx_jobs_location_state_city ON jobs(location_state, location_city);
+CREATE INDEX IF NOT EXISTS idx_jobs_work_model ON jobs(work_model);
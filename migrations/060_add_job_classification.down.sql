@@ -0,0 +1,13 @@
+ALTER TABLE user_sites
+    DROP COLUMN IF EXISTS contract_types,
+    DROP COLUMN IF EXISTS role_families,
+    DROP COLUMN IF EXISTS seniorities;
+
+DROP INDEX IF EXISTS idx_jobs_contract_type;
+DROP INDEX IF EXISTS idx_jobs_role_family;
+DROP INDEX IF EXISTS idx_jobs_seniority;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS contract_type,
+    DROP COLUMN IF EXISTS role_family,
+    DROP COLUMN IF EXISTS seniority;
@@ -0,0 +1,15 @@
+-- Seniority, role family and contract type classified from the title and description
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS seniority VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS role_family VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS contract_type VARCHAR(20);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_seniority ON jobs(seniority);
+CREATE INDEX IF NOT EXISTS idx_jobs_role_family ON jobs(role_family);
+CREATE INDEX IF NOT EXISTS idx_jobs_contract_type ON jobs(contract_type);
+
+-- Notification filters on the classification; an empty list does not filter
+ALTER TABLE user_sites
+    ADD COLUMN IF NOT EXISTS seniorities TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS role_families TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS contract_types TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS benefits,
+    DROP COLUMN IF EXISTS salary_basis;
@@ -0,0 +1,4 @@
+-- Whether the salary is gross or net, and the benefit keys read from the posting
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS salary_basis VARCHAR(10) CHECK (salary_basis IS NULL OR salary_basis IN ('gross', 'net')),
+    ADD COLUMN IF NOT EXISTS benefits TEXT[] NOT NULL DEFAULT '{}';
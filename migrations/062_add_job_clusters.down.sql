@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_jobs_canonical_job_id;
+DROP INDEX IF EXISTS idx_jobs_company_key_open;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS canonical_job_id,
+    DROP COLUMN IF EXISTS company_key;
@@ -0,0 +1,8 @@
+-- Cross-site duplicates: a job also posted on another site of the same company
+-- points to the first one found, which stands for the cluster
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS company_key VARCHAR(150),
+    ADD COLUMN IF NOT EXISTS canonical_job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_company_key_open ON jobs(company_key) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_jobs_canonical_job_id ON jobs(canonical_job_id);
@@ -23,8 +23,37 @@ type JobWithMatch struct {
 	ApplicationID     *int    `json:"application_id,omitempty"`
 	ApplicationStatus *string `json:"application_status,omitempty"`
 	InterviewRound    *int    `json:"interview_round,omitempty"`
+	// SalaryMonthly is the top of the salary range on a monthly scale, used to sort
+	SalaryMonthly *float64 `json:"salary_monthly,omitempty"`
 }
 
+// JobFilters are the filters of the dashboard job list. Empty fields do not filter.
+type JobFilters struct {
+	Days         int
+	Search       string
+	MatchedOnly  bool
+	WorkModel    string // remote, hybrid or onsite
+	State        string // UF, e.g. "SP"
+	City         string
+	Seniority    string // one of Seniorities
+	RoleFamily   string // one of RoleFamilies
+	ContractType string // one of ContractTypes
+	// Salary filters compare the top of the range on a monthly scale, within
+	// SalaryCurrency; salary filters and sorts require it
+	SalaryMin      float64
+	SalaryCurrency string // ISO 4217, e.g. "BRL"
+	Sort           string // one of JobSorts, JobSortRecent when empty
+}
+
+// Orders of the dashboard job list. Salary orders put jobs without a salary last.
+const (
+	JobSortRecent     = "recent"
+	JobSortSalaryDesc = "salary_desc"
+	JobSortSalaryAsc  = "salary_asc"
+)
+
+var JobSorts = []string{JobSortRecent, JobSortSalaryDesc, JobSortSalaryAsc}
+
 type PaginatedJobs struct {
 	Jobs       []JobWithMatch `json:"jobs"`
 	TotalCount int            `json:"total_count"`
@@ -38,11 +67,12 @@ type JobsResponse struct {
 }
 
 type AdminDashboardData struct {
-	TotalRevenue   float64          `json:"total_revenue"`
-	ActiveUsers    int              `json:"active_users"`
-	MonitoredSites int              `json:"monitored_sites"`
-	ScrapingErrors int              `json:"scraping_errors"`
-	RecentErrors   []ScrapingError  `json:"recent_errors"`
+	TotalRevenue   float64         `json:"total_revenue"`
+	ActiveUsers    int             `json:"active_users"`
+	MonitoredSites int             `json:"monitored_sites"`
+	ScrapingErrors int             `json:"scraping_errors"`
+	PolicySkips    int             `json:"policy_skips"`
+	RecentErrors   []ScrapingError `json:"recent_errors"`
 }
 
 type ScrapingError struct {
@@ -50,4 +80,4 @@ type ScrapingError struct {
 	SiteName     string `json:"site_name"`
 	ErrorMessage string `json:"error_message"`
 	CreatedAt    string `json:"created_at"`
-}
\ No newline at end of file
+}
@@ -63,9 +63,10 @@ type ValidateCheckoutResponse struct {
 
 // --- UserSite ---
 
-// UpdateUserSiteFiltersRequest represents target words update.
+// UpdateUserSiteFiltersRequest represents target words and classification filters update.
 type UpdateUserSiteFiltersRequest struct {
 	TargetWords []string `json:"target_words"`
+	JobClassFilters
 }
 
 // --- Site Career ---
@@ -81,16 +82,18 @@ type SiteDTO struct {
 
 // SandboxScrapeResponse represents sandbox scrape result.
 type SandboxScrapeResponse struct {
-	Success bool   `json:"success" example:"true"`
-	Message string `json:"message" example:"Scraping concluído com sucesso"`
-	Data    []Job  `json:"data"`
+	Success     bool                `json:"success" example:"true"`
+	Message     string              `json:"message" example:"Scraping concluído com sucesso"`
+	Data        []Job               `json:"data"`
+	Diagnostics *SandboxDiagnostics `json:"diagnostics,omitempty"`
 }
 
 // SandboxScrapeErrorResponse represents sandbox scrape error.
 type SandboxScrapeErrorResponse struct {
-	Success bool   `json:"success" example:"false"`
-	Error   string `json:"error" example:"falha ao executar scraping"`
-	Message string `json:"message" example:"erro no scraping"`
+	Success     bool                `json:"success" example:"false"`
+	Error       string              `json:"error" example:"falha ao executar scraping"`
+	Message     string              `json:"message" example:"erro no scraping"`
+	Diagnostics *SandboxDiagnostics `json:"diagnostics,omitempty"`
 }
 
 // --- Payment ---
@@ -1,12 +1,102 @@
 package model
 
+import "time"
+
+const (
+	WorkModelRemote = "remote"
+	WorkModelHybrid = "hybrid"
+	WorkModelOnsite = "onsite"
+)
+
+const (
+	SeniorityIntern     = "intern"
+	SeniorityJunior     = "junior"
+	SeniorityMid        = "mid" // pleno
+	SenioritySenior     = "senior"
+	SenioritySpecialist = "specialist"
+	SeniorityLead       = "lead"
+)
+
+const (
+	RoleFamilyBackend   = "backend"
+	RoleFamilyFrontend  = "frontend"
+	RoleFamilyFullstack = "fullstack"
+	RoleFamilyMobile    = "mobile"
+	RoleFamilyData      = "data"
+	RoleFamilyQA        = "qa"
+	RoleFamilyDevOps    = "devops"
+	RoleFamilySecurity  = "security"
+	RoleFamilyDesign    = "design"
+	RoleFamilyProduct   = "product"
+)
+
+const (
+	ContractCLT        = "clt"
+	ContractPJ         = "pj"
+	ContractInternship = "internship"
+	ContractTemporary  = "temporary"
+)
+
+const (
+	SalaryBasisGross = "gross" // bruto
+	SalaryBasisNet   = "net"   // líquido
+)
+
+// The values each classification can take, in display order.
+var (
+	WorkModels    = []string{WorkModelRemote, WorkModelHybrid, WorkModelOnsite}
+	Seniorities   = []string{SeniorityIntern, SeniorityJunior, SeniorityMid, SenioritySenior, SenioritySpecialist, SeniorityLead}
+	RoleFamilies  = []string{RoleFamilyBackend, RoleFamilyFrontend, RoleFamilyFullstack, RoleFamilyMobile, RoleFamilyData, RoleFamilyQA, RoleFamilyDevOps, RoleFamilySecurity, RoleFamilyDesign, RoleFamilyProduct}
+	ContractTypes = []string{ContractCLT, ContractPJ, ContractInternship, ContractTemporary}
+)
+
 type Job struct {
-	ID            int    `json:"id" db:"id"`
-	SiteID        int    `json:"site_id" db:"site_id"`
-	Title         string `json:"title" db:"title"`
-	Location      string `json:"location" db:"location"`
-	Company       string `json:"company" db:"company"`
-	JobLink       string `json:"job_link" db:"job_link"`
-	RequisitionID string `json:"job_id" db:"requisition_id"`
-	Description   string `json:"description" db:"description"`
+	ID             int        `json:"id" db:"id"`
+	SiteID         int        `json:"site_id" db:"site_id"`
+	Title          string     `json:"title" db:"title"`
+	Location       string     `json:"location" db:"location"`
+	Company        string     `json:"company" db:"company"`
+	JobLink        string     `json:"job_link" db:"job_link"`
+	RequisitionID  string     `json:"job_id" db:"requisition_id"`
+	SyntheticID    bool       `json:"synthetic_id,omitempty" db:"requisition_id_synthetic"` // RequisitionID was generated from the link or title+location
+	Description    string     `json:"description" db:"description"`
+	ContentHash    string     `json:"-" db:"content_hash"`                            // hash of title, location and description
+	EditedAt       *time.Time `json:"edited_at,omitempty" db:"content_updated_at"`    // last time the posting's content changed
+	EmploymentType string     `json:"employment_type,omitempty" db:"employment_type"` // e.g. "FULL_TIME", "CONTRACTOR"
+	DatePosted     *time.Time `json:"date_posted,omitempty" db:"date_posted"`
+	SalaryMin      *float64   `json:"salary_min,omitempty" db:"salary_min"`
+	SalaryMax      *float64   `json:"salary_max,omitempty" db:"salary_max"`
+	SalaryCurrency string     `json:"salary_currency,omitempty" db:"salary_currency"`
+	SalaryPeriod   string     `json:"salary_period,omitempty" db:"salary_period"` // hour, day, week, month or year
+	SalaryBasis    string     `json:"salary_basis,omitempty" db:"salary_basis"`   // gross or net, when the posting says
+	Benefits       []string   `json:"benefits,omitempty" db:"benefits"`           // benefit keys such as "meal_voucher", raw names until normalized
+	Status         string     `json:"status,omitempty" db:"status"`               // open, closed or archived
+	FirstSeenAt    *time.Time `json:"first_seen_at,omitempty" db:"first_seen_at"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+
+	// Derived from the text above by the normalizer, empty when not recognized
+	LocationCity    string `json:"location_city,omitempty" db:"location_city"`
+	LocationState   string `json:"location_state,omitempty" db:"location_state"`     // UF, e.g. "SP"
+	LocationCountry string `json:"location_country,omitempty" db:"location_country"` // ISO code, e.g. "BR"
+	WorkModel       string `json:"work_model,omitempty" db:"work_model"`             // remote, hybrid or onsite
+	Seniority       string `json:"seniority,omitempty" db:"seniority"`               // one of Seniorities
+	RoleFamily      string `json:"role_family,omitempty" db:"role_family"`           // one of RoleFamilies
+	ContractType    string `json:"contract_type,omitempty" db:"contract_type"`       // one of ContractTypes
+
+	// The same opening found on another site of the company, e.g. its own careers
+	// page and its Gupy board, is stored once per site and clustered under the first one
+	CompanyKey     string   `json:"-" db:"company_key"`                               // employer the site stands for, e.g. "nubank"
+	CanonicalJobID *int     `json:"canonical_job_id,omitempty" db:"canonical_job_id"` // nil for the canonical job of a cluster
+	AlternateLinks []string `json:"alternate_links,omitempty" db:"-"`                 // links of the other jobs in the cluster
+}
+
+// KnownJob is what a scrape needs to know about a job already stored for the site
+// to decide whether to fetch its detail page again.
+type KnownJob struct {
+	ID               int
+	RequisitionID    string
+	SyntheticID      bool
+	Title            string // for listings that only give the link, such as sitemaps
+	JobLink          string
+	DetailsFetchedAt *time.Time
 }
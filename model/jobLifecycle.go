@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	JobStatusOpen     = "open"
+	JobStatusClosed   = "closed"
+	JobStatusArchived = "archived"
+)
+
+// JobLifecyclePolicy drives the daily lifecycle sweep: open jobs missed by a
+// successful scrape more than CloseAfter after they were last seen are closed, closed jobs are archived after ArchiveAfter, and archived
+// jobs are deleted after PurgeAfter unless a user applied to or analyzed them.
+type JobLifecyclePolicy struct {
+	CloseAfter   time.Duration
+	ArchiveAfter time.Duration
+	PurgeAfter   time.Duration
+}
+
+type JobLifecycleResult struct {
+	Closed   int64 `json:"closed"`
+	Archived int64 `json:"archived"`
+	Purged   int64 `json:"purged"`
+}
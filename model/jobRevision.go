@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// JobRevision is a version of a job's content, recorded when it is first scraped
+// and every time the posting is edited.
+type JobRevision struct {
+	ID          int       `json:"id" db:"id"`
+	JobID       int       `json:"job_id" db:"job_id"`
+	Title       string    `json:"title" db:"title"`
+	Location    string    `json:"location" db:"location"`
+	Description string    `json:"description" db:"description"`
+	ContentHash string    `json:"-" db:"content_hash"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
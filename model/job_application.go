@@ -19,10 +19,12 @@ type JobApplicationWithJob struct {
 }
 
 type JobApplicationJob struct {
-	Title    string `json:"title"`
-	Company  string `json:"company"`
-	Location string `json:"location"`
-	JobLink  string `json:"job_link"`
+	Title    string     `json:"title"`
+	Company  string     `json:"company"`
+	Location string     `json:"location"`
+	JobLink  string     `json:"job_link"`
+	Status   string     `json:"status"` // open, closed or archived
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
 }
 
 type CreateApplicationRequest struct {
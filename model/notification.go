@@ -12,6 +12,9 @@ type NotificationWithJob struct {
 	JobCompany  string    `json:"job_company"`
 	JobLocation string    `json:"job_location"`
 	JobLink     string    `json:"job_link"`
+
+	ClusterID      int      `json:"-"`                         // canonical job of the job's cluster, or the job itself
+	AlternateLinks []string `json:"alternate_links,omitempty"` // links of the same opening on other sites
 }
 
 // JobWithFilters representa uma vaga com os filtros do usuário associados
@@ -22,4 +25,10 @@ type JobWithFilters struct {
 	Company  string   `json:"company"`
 	JobLink  string   `json:"job_link"`
 	Filters  []string `json:"-"`
+
+	Seniority    string          `json:"seniority,omitempty"`
+	RoleFamily   string          `json:"role_family,omitempty"`
+	ContractType string          `json:"contract_type,omitempty"`
+	ClassFilters JobClassFilters `json:"-"`
+	ClusterID    int             `json:"-"` // canonical job of the job's cluster, or the job itself
 }
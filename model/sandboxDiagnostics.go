@@ -0,0 +1,64 @@
+package model
+
+// Kinds of request in a sandbox report.
+const (
+	SandboxRequestPage   = "page"
+	SandboxRequestDetail = "detail"
+)
+
+// SandboxDiagnostics explains the result of a sandbox scrape to whoever is writing
+// the config.
+type SandboxDiagnostics struct {
+	DurationMs    int64                  `json:"duration_ms" example:"1830"`
+	JobsFound     int                    `json:"jobs_found" example:"25"`
+	PagesFollowed int                    `json:"pages_followed" example:"2"`
+	FillRates     []SandboxFieldFill     `json:"fill_rates"`
+	Requests      []SandboxRequest       `json:"requests"`
+	Samples       []SandboxSample        `json:"samples"`
+	DuplicateIDs  []SandboxDuplicateID   `json:"duplicate_ids"`
+	Selectors     []SandboxSelectorMatch `json:"selectors"`
+	Warnings      []string               `json:"warnings"`
+}
+
+// SandboxFieldFill is how many of the scraped jobs have a field filled.
+type SandboxFieldFill struct {
+	Field  string  `json:"field" example:"description"`
+	Filled int     `json:"filled" example:"20"`
+	Total  int     `json:"total" example:"25"`
+	Rate   float64 `json:"rate" example:"0.8"`
+}
+
+// SandboxRequest is one HTTP request, or page rendered by the browser, of a sandbox scrape.
+type SandboxRequest struct {
+	Kind       string `json:"kind" example:"page"`
+	Method     string `json:"method" example:"GET"`
+	URL        string `json:"url" example:"https://acme.com/jobs"`
+	Status     int    `json:"status,omitempty" example:"200"`
+	DurationMs int64  `json:"duration_ms" example:"412"`
+	Bytes      int    `json:"bytes" example:"48213"`
+	Rendered   bool   `json:"rendered,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SandboxSample is raw content the jobs were extracted from: the HTML of a listed
+// item, or the start of a JSON/XML response.
+type SandboxSample struct {
+	URL       string `json:"url" example:"https://acme.com/jobs"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// SandboxDuplicateID is a requisition ID shared by more than one scraped job.
+type SandboxDuplicateID struct {
+	RequisitionID string `json:"requisition_id" example:"R-123"`
+	Count         int    `json:"count" example:"2"`
+}
+
+// SandboxSelectorMatch is how many elements a configured selector matched.
+type SandboxSelectorMatch struct {
+	Field    string `json:"field" example:"title_selector"`
+	Selector string `json:"selector" example:"h3 a"`
+	Matches  int    `json:"matches" example:"25"`
+	// URL is the page the selector was checked against.
+	URL string `json:"url"`
+}
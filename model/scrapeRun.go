@@ -0,0 +1,91 @@
+package model
+
+import "time"
+
+// Error classes of a scrape run; an empty class means the run succeeded.
+const (
+	ScrapeErrorPolicySkip = "policy_skip"
+	ScrapeErrorTimeout    = "timeout"
+	ScrapeErrorNetwork    = "network"
+	ScrapeErrorHTTPStatus = "http_status"
+	ScrapeErrorParse      = "parse"
+	ScrapeErrorOther      = "other"
+)
+
+// Site health statuses derived from the latest scrape runs.
+const (
+	SiteHealthHealthy  = "healthy"
+	SiteHealthDegraded = "degraded"
+	SiteHealthFailing  = "failing"
+	SiteHealthUnknown  = "unknown"
+)
+
+// ScrapeCounts is what a scrape found compared with the jobs already stored.
+type ScrapeCounts struct {
+	Listed         int `json:"listed_count"`
+	New            int `json:"new_count"`
+	Updated        int `json:"updated_count"`
+	Missing        int `json:"missing_count"`
+	DetailFailures int `json:"detail_failures"`
+	EmptyTitles    int `json:"empty_title_count"`
+}
+
+type ScrapeRun struct {
+	ID         int       `json:"id"`
+	SiteID     int       `json:"site_id"`
+	SiteName   string    `json:"site_name"`
+	TaskID     string    `json:"task_id,omitempty"`
+	Strategy   string    `json:"strategy"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	ScrapeCounts
+	ErrorClass   string `json:"error_class,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// Failed reports whether the run broke; runs skipped by the politeness policy did not.
+func (r ScrapeRun) Failed() bool {
+	return r.ErrorClass != "" && r.ErrorClass != ScrapeErrorPolicySkip
+}
+
+// SiteRunHistory is a site with its latest runs, newest first.
+type SiteRunHistory struct {
+	SiteID         int
+	SiteName       string
+	IsActive       bool
+	DisabledReason string
+	Runs           []ScrapeRun
+}
+
+type SiteHealth struct {
+	SiteID              int        `json:"site_id"`
+	SiteName            string     `json:"site_name"`
+	IsActive            bool       `json:"is_active"`
+	Status              string     `json:"status"`
+	Reason              string     `json:"reason,omitempty"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastListed          int        `json:"last_listed_count"`
+	AvgListed           float64    `json:"avg_listed_count"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastErrorClass      string     `json:"last_error_class,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+}
+
+// Kinds of anomaly detected in a site's scrape runs.
+const (
+	AnomalyConsecutiveFailures = "consecutive_failures"
+	AnomalyZeroResults         = "zero_results"
+	AnomalySuddenDrop          = "sudden_drop"
+	AnomalyEmptyTitles         = "empty_titles"
+)
+
+// SiteAnomaly is a sign that a site's scraping config broke.
+type SiteAnomaly struct {
+	SiteID   int    `json:"site_id"`
+	SiteName string `json:"site_name"`
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+	// Disabled is set when the site was switched off because of the anomaly.
+	Disabled bool `json:"disabled"`
+}
@@ -1,12 +1,18 @@
 package model
 
+// Schedule used by sites without their own.
+const (
+	DefaultScheduleCron     = "0 7,9,11,13,15,17 * * *"
+	DefaultScheduleTimezone = "America/Sao_Paulo"
+)
+
 type SiteScrapingConfig struct {
 	ID                       int     `db:"id" json:"id"`
 	SiteName                 string  `db:"site_name" json:"site_name"`
 	BaseURL                  string  `db:"base_url" json:"base_url"`
 	LogoURL                  *string  `db:"logo_url" json:"logo_url,omitempty"`
 	IsActive                 bool    `db:"is_active" json:"is_active"`
-	ScrapingType             string  `db:"scraping_type" json:"scraping_type"` // 'HTML', 'API', 'HEADLESS'
+	ScrapingType             string  `db:"scraping_type" json:"scraping_type"` // 'CSS', 'API', 'HEADLESS', 'JSONLD', 'FEED' or an ATS: 'GREENHOUSE', 'LEVER', 'GUPY', 'WORKDAY', 'EIGHTFOLD'
 	JobListItemSelector      *string `db:"job_list_item_selector" json:"job_list_item_selector,omitempty"`
 	TitleSelector            *string `db:"title_selector" json:"title_selector,omitempty"`
 	LinkSelector             *string `db:"link_selector" json:"link_selector,omitempty"`
@@ -20,4 +26,18 @@ type SiteScrapingConfig struct {
 	APIHeadersJSON           *string `db:"api_headers_json" json:"api_headers_json,omitempty"`
 	APIPayloadTemplate       *string `db:"api_payload_template" json:"api_payload_template,omitempty"`
 	JSONDataMappings         *string `db:"json_data_mappings" json:"json_data_mappings,omitempty"`
+	ATSSlug                  *string `db:"ats_slug" json:"ats_slug,omitempty"` // company identifier on the ATS, used by the ATS scraping types
+	URLPattern               *string `db:"url_pattern" json:"url_pattern,omitempty"`   // FEED: regexp a posting URL must match
+	MaxAgeDays               *int    `db:"max_age_days" json:"max_age_days,omitempty"` // FEED: skip entries whose lastmod/pubDate is older
+	HeadlessActions          *string `db:"headless_actions" json:"headless_actions,omitempty"` // HEADLESS: JSON array of steps run before extraction
+	MaxPages                 *int    `db:"max_pages" json:"max_pages,omitempty"`                 // CSS/JSONLD: listing pages to crawl (default 20, max 200)
+	PageURLTemplate          *string `db:"page_url_template" json:"page_url_template,omitempty"` // CSS: numbered pages, e.g. "https://acme.com/jobs?page={n}"
+	ScheduleCron             *string `db:"schedule_cron" json:"schedule_cron,omitempty"`                         // cron expression, e.g. "0 */2 * * *"; default DefaultScheduleCron
+	ScheduleIntervalMinutes  *int    `db:"schedule_interval_minutes" json:"schedule_interval_minutes,omitempty"` // instead of a cron: every N minutes (5 to 1440)
+	ScheduleWindowStart      *string `db:"schedule_window_start" json:"schedule_window_start,omitempty"`         // "HH:MM": only scrape from this time...
+	ScheduleWindowEnd        *string `db:"schedule_window_end" json:"schedule_window_end,omitempty"`             // ...until this time
+	ScheduleTimezone         *string `db:"schedule_timezone" json:"schedule_timezone,omitempty"`                 // IANA name; default DefaultScheduleTimezone
+	ScheduleMinIntervalMinutes *int  `db:"schedule_min_interval_minutes" json:"schedule_min_interval_minutes,omitempty"` // adaptive: the scheduler picks the interval between min...
+	ScheduleMaxIntervalMinutes *int  `db:"schedule_max_interval_minutes" json:"schedule_max_interval_minutes,omitempty"` // ...and max, from the site's posting rate and subscribers
+	DisabledReason           string  `db:"disabled_reason" json:"-"` // set when the site was switched off automatically; read-only
 }
\ No newline at end of file
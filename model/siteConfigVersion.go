@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSiteNotFound        = errors.New("site não encontrado")
+	ErrSiteVersionNotFound = errors.New("versão da configuração não encontrada")
+)
+
+// Kinds of change recorded in a site's config history.
+const (
+	SiteConfigCreated     = "create"
+	SiteConfigUpdated     = "update"
+	SiteConfigActivated   = "activate"
+	SiteConfigDeactivated = "deactivate"
+	SiteConfigAutoDisable = "auto_disable"
+	SiteConfigRolledBack  = "rollback"
+	SiteConfigDeleted     = "delete"
+)
+
+// SiteConfigAuthor is who changed a config; the zero value is the system itself.
+type SiteConfigAuthor struct {
+	UserID int
+	Email  string
+}
+
+// SiteConfigVersion is an immutable snapshot of a site's config after a change.
+type SiteConfigVersion struct {
+	ID             int                `json:"id"`
+	SiteID         int                `json:"site_id"`
+	Version        int                `json:"version"`
+	ChangeType     string             `json:"change_type"`
+	Config         SiteScrapingConfig `json:"config"`
+	ChangedBy      *int               `json:"changed_by,omitempty"`
+	ChangedByEmail string             `json:"changed_by_email,omitempty"`
+	Note           string             `json:"note,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type SiteConfigFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type SiteConfigDiff struct {
+	SiteID      int                     `json:"site_id"`
+	FromVersion int                     `json:"from_version"`
+	ToVersion   int                     `json:"to_version"`
+	Changes     []SiteConfigFieldChange `json:"changes"`
+}
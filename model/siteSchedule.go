@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ScheduledSite is an active site as the scheduler sees it.
+type ScheduledSite struct {
+	Config SiteScrapingConfig
+	// LastScheduledAt is when the site was last enqueued; nil if never.
+	LastScheduledAt *time.Time
+	// AdaptiveIntervalMinutes is the interval last picked for an adaptive site.
+	AdaptiveIntervalMinutes *int
+}
+
+// SitePostingStats is what the interval of an adaptive site is derived from.
+type SitePostingStats struct {
+	SiteID int
+	// NewJobs is how many jobs the site posted in the counted period.
+	NewJobs     int
+	Subscribers int
+}
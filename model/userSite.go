@@ -1,4 +1,19 @@
 package model
+
+import "errors"
+
+// ErrInvalidJobClassFilter is returned when a notification filter has a value the
+// classifier never produces.
+var ErrInvalidJobClassFilter = errors.New("filtro de vaga inválido")
+
+// JobClassFilters restrict a user's notifications for a site to jobs classified
+// with one of the listed values. An empty list does not filter, and jobs the
+// classifier could not place are never filtered out.
+type JobClassFilters struct {
+	Seniorities   []string `json:"seniorities,omitempty" example:"mid,senior"`
+	RoleFamilies  []string `json:"role_families,omitempty" example:"backend"`
+	ContractTypes []string `json:"contract_types,omitempty" example:"clt"`
+}
 type UserSite struct{
 	UserId int `json:"user_id"`
 	Name string `json:"user_name"`
@@ -18,4 +33,5 @@ type UserSiteCurriculum struct{
 type UserSiteRequest struct{
 	SiteId int `json:"site_id"`
 	TargetWords []string `json:"target_words" db:"target_words"`
+	JobClassFilters
 }
\ No newline at end of file
@@ -0,0 +1,73 @@
+package normalizer
+
+import (
+	"regexp"
+	"slices"
+)
+
+// Benefit keys stored on jobs, in display order.
+const (
+	BenefitHealthInsurance     = "health_insurance"
+	BenefitDentalInsurance     = "dental_insurance"
+	BenefitLifeInsurance       = "life_insurance"
+	BenefitMealVoucher         = "meal_voucher"
+	BenefitFoodVoucher         = "food_voucher"
+	BenefitFlexibleBenefits    = "flexible_benefits"
+	BenefitTransportVoucher    = "transport_voucher"
+	BenefitHomeOfficeAllowance = "home_office_allowance"
+	BenefitGym                 = "gym"
+	BenefitMentalHealth        = "mental_health"
+	BenefitProfitSharing       = "profit_sharing"
+	BenefitBonus               = "bonus"
+	BenefitStockOptions        = "stock_options"
+	BenefitPrivatePension      = "private_pension"
+	BenefitEducation           = "education"
+	BenefitLanguageCourses     = "language_courses"
+	BenefitChildcare           = "childcare"
+	BenefitExtendedLeave       = "extended_parental_leave"
+	BenefitBirthdayDayOff      = "birthday_day_off"
+	BenefitFlexibleHours       = "flexible_hours"
+)
+
+// benefitPatterns are matched against folded text. A key also matches itself, so
+// normalizing stored benefits again keeps them.
+var benefitPatterns = []label{
+	{BenefitHealthInsurance, regexp.MustCompile(`\b(plano de saude|assistencia medica|convenio medico|seguro saude|health insurance|medical insurance)\b`)},
+	{BenefitDentalInsurance, regexp.MustCompile(`\b(odontologic[oa]|plano dental|dental)\b`)},
+	{BenefitLifeInsurance, regexp.MustCompile(`\b(seguro de vida|life insurance)\b`)},
+	{BenefitMealVoucher, regexp.MustCompile(`\b(vale[- ]refeicao|vr|ticket refeicao|auxilio refeicao|meal voucher|meal allowance)\b`)},
+	{BenefitFoodVoucher, regexp.MustCompile(`\b(vale[- ]alimentacao|va|ticket alimentacao|auxilio alimentacao|food voucher)\b`)},
+	{BenefitFlexibleBenefits, regexp.MustCompile(`\b(beneficios? flexive(l|is)|cartao flexivel|caju|swile|vr multi|flash (beneficios|cartao)|cartao flash)\b`)},
+	{BenefitTransportVoucher, regexp.MustCompile(`\b(vale[- ]transporte|vt|auxilio (transporte|mobilidade)|transport allowance)\b`)},
+	{BenefitHomeOfficeAllowance, regexp.MustCompile(`\b(auxilio (home[ -]office|internet|trabalho remoto)|home office allowance|ajuda de custo home office)\b`)},
+	{BenefitGym, regexp.MustCompile(`\b(gympass|wellhub|totalpass|(auxilio|desconto (em|de)) academia|gym)\b`)},
+	{BenefitMentalHealth, regexp.MustCompile(`\b(terapia|apoio psicologico|saude mental|zenklub|mental health)\b`)},
+	{BenefitProfitSharing, regexp.MustCompile(`\b(plr|ppr|participacao nos lucros|profit sharing)\b`)},
+	{BenefitBonus, regexp.MustCompile(`\b(bonus|bonificacao)\b`)},
+	{BenefitStockOptions, regexp.MustCompile(`\b(stock options?|rsus?|opcao de compra de acoes)\b`)},
+	{BenefitPrivatePension, regexp.MustCompile(`\b(previdencia privada|plano de previdencia|pension plan)\b`)},
+	{BenefitEducation, regexp.MustCompile(`\b(auxilio educacao|bolsa de estudos?|subsidio educacional|reembolso (de )?(cursos|educacao)|education allowance)\b`)},
+	{BenefitLanguageCourses, regexp.MustCompile(`\b(curso de (ingles|idiomas)|aulas de (ingles|idiomas)|language (courses|classes))\b`)},
+	{BenefitChildcare, regexp.MustCompile(`\b(auxilio creche|auxilio baba|childcare)\b`)},
+	{BenefitExtendedLeave, regexp.MustCompile(`\b(licenca (maternidade|paternidade|parental) (estendida|ampliada)|extended parental leave)\b`)},
+	{BenefitBirthdayDayOff, regexp.MustCompile(`\b(day[ -]off|folga) (no|de) (seu )?aniversario\b|\bbirthday (day )?off\b`)},
+	{BenefitFlexibleHours, regexp.MustCompile(`\b(horario flexivel|jornada flexivel|flexible hours)\b`)},
+}
+
+// ExtractBenefits returns the benefit keys mentioned in the texts, in display
+// order and without repeats. Texts may be a description or single benefit names
+// read from an API, such as "Vale Refeição".
+func ExtractBenefits(texts ...string) []string {
+	folded := make([]string, len(texts))
+	for i, text := range texts {
+		folded[i] = fold(text)
+	}
+
+	var benefits []string
+	for _, b := range benefitPatterns {
+		if slices.ContainsFunc(folded, func(text string) bool { return text == b.value || b.pattern.MatchString(text) }) {
+			benefits = append(benefits, b.value)
+		}
+	}
+	return benefits
+}
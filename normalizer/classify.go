@@ -0,0 +1,116 @@
+package normalizer
+
+import (
+	"regexp"
+	"web-scrapper/model"
+)
+
+type label struct {
+	value   string
+	pattern *regexp.Regexp
+}
+
+// Patterns are matched against folded text, in order, and the first match wins.
+var (
+	seniorityLabels = []label{
+		{model.SeniorityIntern, regexp.MustCompile(`\b(estagio|estagiari[oa]s?|intern|internship|aprendiz)\b`)},
+		{model.SeniorityLead, regexp.MustCompile(`\b(lead|lider|head|coordenador[a]?)\b`)},
+		{model.SenioritySpecialist, regexp.MustCompile(`\b(especialista|specialist|staff|principal)\b`)},
+		{model.SenioritySenior, regexp.MustCompile(`\b(senior|sr)\b|\b(iii|iv)\s*($|[-|,/(])`)},
+		{model.SeniorityMid, regexp.MustCompile(`\b(pleno|mid|mid-level)\b|\bpl\b([^/]|$)|\bii\s*($|[-|,/(])`)},
+		{model.SeniorityJunior, regexp.MustCompile(`\b(junior|jr|trainee|entry[ -]level)\b|\bi\s*($|[-|,/(])`)},
+	}
+	// Descriptions mention other levels ("você vai mentorar juniores"), so only
+	// explicit statements of the job's level are read from them.
+	descriptionSeniority = regexp.MustCompile(`\b(?:nivel|vaga|posicao|perfil|cargo)\s*:?\s*(junior|jr|pleno|senior|sr|especialista)\b`)
+
+	roleFamilyLabels = []label{
+		{model.RoleFamilyFullstack, regexp.MustCompile(`\bfull[ -]?stack\b`)},
+		{model.RoleFamilyMobile, regexp.MustCompile(`\b(mobile|android|ios|flutter|react native|swift)\b`)},
+		{model.RoleFamilyData, regexp.MustCompile(`\b(dados|data|machine learning|ml|analytics|bi|cientista|scientist|ia|ai)\b`)},
+		{model.RoleFamilyQA, regexp.MustCompile(`\b(qa|quality assurance|qualidade|testes?|tester|test|sdet)\b`)},
+		{model.RoleFamilyDevOps, regexp.MustCompile(`\b(devops|sre|site reliability|infraestrutura|infra|cloud|plataforma|platform)\b`)},
+		{model.RoleFamilySecurity, regexp.MustCompile(`\b(seguranca|security|cyber|ciberseguranca|appsec)\b`)},
+		{model.RoleFamilyFrontend, regexp.MustCompile(`\b(front[ -]?end|react|angular|vue)\b`)},
+		{model.RoleFamilyBackend, regexp.MustCompile(`\b(back[ -]?end|java|golang|go|python|node|nodejs|php|ruby|kotlin|scala|elixir|c#)(\b|\s|$)|(^|\s)\.net\b`)},
+		{model.RoleFamilyDesign, regexp.MustCompile(`\b(designer|design|ux|ui)\b`)},
+		{model.RoleFamilyProduct, regexp.MustCompile(`\b(product manager|product owner|produto|po|pm)\b`)},
+	}
+
+	contractLabels = []label{
+		{model.ContractInternship, regexp.MustCompile(`\b(estagio|estagiari[oa]s?|internship|intern)\b`)},
+		{model.ContractTemporary, regexp.MustCompile(`\b(temporari[oa]|temporary|prazo determinado)\b`)},
+		{model.ContractCLT, regexp.MustCompile(`\b(clt|efetiv[oa])\b`)},
+		{model.ContractPJ, regexp.MustCompile(`\b(pj|pessoa juridica|contractor|freelancer?)\b`)},
+	}
+
+	// employmentTypeContracts maps schema.org employmentType values to contract types.
+	employmentTypeContracts = map[string]string{
+		"INTERN":     model.ContractInternship,
+		"TEMPORARY":  model.ContractTemporary,
+		"CONTRACTOR": model.ContractPJ,
+	}
+)
+
+// ClassifySeniority tells the job's level from its title, or from an explicit
+// statement in the description ("nível pleno"). It returns "" when neither says.
+func ClassifySeniority(title string, description string) string {
+	if seniority := firstLabel(seniorityLabels, fold(title)); seniority != "" {
+		return seniority
+	}
+	if m := descriptionSeniority.FindStringSubmatch(fold(description)); m != nil {
+		return firstLabel(seniorityLabels, m[1])
+	}
+	return ""
+}
+
+// SeniorityOf returns the level a single search word stands for, such as "pleno"
+// or "Sr.", or "" when the word is not a level.
+func SeniorityOf(word string) string {
+	folded := wordsOnly(fold(word))
+	for _, l := range seniorityLabels {
+		if loc := l.pattern.FindStringIndex(folded); loc != nil && loc[0] == 0 && loc[1] == len(folded) {
+			return l.value
+		}
+	}
+	return ""
+}
+
+// ClassifyRoleFamily tells the job's area from its title. Generic titles such as
+// "Desenvolvedor de Software" fall back to the technologies the description
+// mentions most.
+func ClassifyRoleFamily(title string, description string) string {
+	if family := firstLabel(roleFamilyLabels, fold(title)); family != "" {
+		return family
+	}
+	folded := fold(description)
+	best, bestCount := "", 1
+	for _, l := range roleFamilyLabels {
+		// A description listing the stack once is not enough to pick a family
+		if count := len(l.pattern.FindAllStringIndex(folded, -1)); count > bestCount {
+			best, bestCount = l.value, count
+		}
+	}
+	return best
+}
+
+// ClassifyContractType tells the hiring regime from the title, the schema.org
+// employmentType, or the description, in that order.
+func ClassifyContractType(title string, description string, employmentType string) string {
+	if contract := firstLabel(contractLabels, fold(title)); contract != "" {
+		return contract
+	}
+	if contract := employmentTypeContracts[employmentType]; contract != "" {
+		return contract
+	}
+	return firstLabel(contractLabels, fold(description))
+}
+
+func firstLabel(labels []label, folded string) string {
+	for _, l := range labels {
+		if l.pattern.MatchString(folded) {
+			return l.value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package normalizer
+
+import (
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifySeniority(t *testing.T) {
+	tests := []struct {
+		title       string
+		description string
+		want        string
+	}{
+		{"Desenvolvedor(a) III", "", model.SenioritySenior},
+		{"Sr. Software Engineer", "", model.SenioritySenior},
+		{"Desenvolvedor Backend Pleno", "", model.SeniorityMid},
+		{"Software Engineer II - Payments", "", model.SeniorityMid},
+		{"Analista de Dados Jr", "", model.SeniorityJunior},
+		{"Estágio em Desenvolvimento", "", model.SeniorityIntern},
+		{"Tech Lead Sênior", "", model.SeniorityLead},
+		{"Especialista em Cloud", "", model.SenioritySpecialist},
+		{"Desenvolvedor PL/SQL", "", ""},
+		{"Desenvolvedor Java", "Buscamos profissional de nível pleno", model.SeniorityMid},
+		{"Desenvolvedor Java", "Você vai mentorar desenvolvedores juniores", ""},
+		{"iOS Developer", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifySeniority(tt.title, tt.description))
+		})
+	}
+}
+
+func TestSeniorityOf(t *testing.T) {
+	assert.Equal(t, model.SeniorityMid, SeniorityOf("pleno"))
+	assert.Equal(t, model.SenioritySenior, SeniorityOf("Sr."))
+	assert.Equal(t, model.SenioritySenior, SeniorityOf("Sênior"))
+	assert.Equal(t, model.SeniorityIntern, SeniorityOf("estágio"))
+	assert.Equal(t, "", SeniorityOf("golang"))
+	assert.Equal(t, "", SeniorityOf("senior developer"))
+}
+
+func TestClassifyRoleFamily(t *testing.T) {
+	tests := []struct {
+		title       string
+		description string
+		want        string
+	}{
+		{"Desenvolvedor Backend Go", "", model.RoleFamilyBackend},
+		{"Engenheiro de Software Java", "", model.RoleFamilyBackend},
+		{"Desenvolvedor .NET", "", model.RoleFamilyBackend},
+		{"Front-end Engineer (React)", "", model.RoleFamilyFrontend},
+		{"Fullstack Developer React/Node", "", model.RoleFamilyFullstack},
+		{"Desenvolvedor React Native", "", model.RoleFamilyMobile},
+		{"Engenheiro de Dados", "", model.RoleFamilyData},
+		{"Analista de QA", "", model.RoleFamilyQA},
+		{"SRE", "", model.RoleFamilyDevOps},
+		{"Product Designer", "", model.RoleFamilyDesign},
+		{"Product Manager", "", model.RoleFamilyProduct},
+		{"Desenvolvedor de Software", "Stack: Kotlin no backend, Java e PostgreSQL", model.RoleFamilyBackend},
+		{"Desenvolvedor de Software", "Conhecimento em React", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyRoleFamily(tt.title, tt.description))
+		})
+	}
+}
+
+func TestClassifyContractType(t *testing.T) {
+	tests := []struct {
+		name           string
+		title          string
+		description    string
+		employmentType string
+		want           string
+	}{
+		{"internship title", "Estágio em TI", "", "", model.ContractInternship},
+		{"pj in title", "Desenvolvedor Go (PJ)", "Regime CLT para líderes", "", model.ContractPJ},
+		{"employment type", "Desenvolvedor Go", "", "CONTRACTOR", model.ContractPJ},
+		{"clt description", "Desenvolvedor Go", "Contratação CLT com benefícios", "FULL_TIME", model.ContractCLT},
+		{"temporary description", "Analista", "Contrato temporário de 6 meses", "", model.ContractTemporary},
+		{"unknown", "Analista", "Venha fazer parte do time", "FULL_TIME", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyContractType(tt.title, tt.description, tt.employmentType))
+		})
+	}
+}
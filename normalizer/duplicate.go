@@ -0,0 +1,134 @@
+package normalizer
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"unicode"
+	"web-scrapper/model"
+)
+
+const (
+	// duplicateTitleSimilarity is the share of title words two postings must have in common.
+	duplicateTitleSimilarity = 0.8
+	// duplicateDescriptionSimilarity is the share of description shingles they must have in common.
+	duplicateDescriptionSimilarity = 0.5
+	// shingleSize is the number of words in each description shingle.
+	shingleSize = 3
+)
+
+var (
+	parenthetical = regexp.MustCompile(`\([^)]*\)`)
+
+	// companyNoise are words that name a board or a legal form rather than the company:
+	// "Nubank Carreiras", "Itaú Unibanco S.A." and "Stone (Gupy)" are the same employer.
+	companyNoise = map[string]bool{
+		"ltda": true, "sa": true, "inc": true, "llc": true, "ltd": true, "eireli": true, "me": true,
+		"gupy": true, "greenhouse": true, "workday": true, "eightfold": true, "inhire": true, "lever": true,
+		"careers": true, "career": true, "carreiras": true, "carreira": true, "vagas": true, "jobs": true,
+		"trabalhe": true, "conosco": true,
+	}
+
+	// titleNoise are words that do not tell two openings apart.
+	titleNoise = map[string]bool{
+		"de": true, "da": true, "do": true, "das": true, "dos": true, "em": true, "para": true, "com": true, "e": true,
+		"of": true, "for": true, "the": true, "and": true, "with": true, "in": true,
+		"vaga": true, "oportunidade": true, "remoto": true, "remote": true, "hibrido": true, "hybrid": true, "presencial": true,
+	}
+)
+
+// CompanyKey reduces a company or site name to the employer it stands for, so that
+// postings from its own careers page and from its job board can be compared.
+func CompanyKey(company string) string {
+	folded := parenthetical.ReplaceAllString(fold(company), " ")
+	var words []string
+	for _, word := range splitWords(strings.ReplaceAll(folded, "s.a.", "sa")) {
+		if len(word) > 1 && !companyNoise[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// IsDuplicate tells whether two postings from different sites are the same opening:
+// same employer, compatible locations, nearly the same title and, when both have
+// one, mostly the same description.
+func IsDuplicate(a, b model.Job) bool {
+	if a.CompanyKey == "" || a.CompanyKey != b.CompanyKey || !sameLocation(a, b) {
+		return false
+	}
+	titleSimilarity := jaccard(titleWords(a.Title), titleWords(b.Title))
+	if titleSimilarity < duplicateTitleSimilarity {
+		return false
+	}
+	// Listings without details only have the title to go by
+	if strings.TrimSpace(a.Description) == "" || strings.TrimSpace(b.Description) == "" {
+		return titleSimilarity == 1
+	}
+	return jaccard(shingles(a.Description), shingles(b.Description)) >= duplicateDescriptionSimilarity
+}
+
+// sameLocation compares the most precise place both postings state. A posting
+// without a location fits any other.
+func sameLocation(a, b model.Job) bool {
+	if a.LocationCity != "" && b.LocationCity != "" {
+		return a.LocationCity == b.LocationCity && a.LocationState == b.LocationState
+	}
+	if a.LocationState != "" && b.LocationState != "" {
+		return a.LocationState == b.LocationState
+	}
+	return true
+}
+
+// titleWords are the words of a title, with levels written the same way ("Sr." and
+// "Sênior") and without the gender marks of "Desenvolvedor(a)".
+func titleWords(title string) map[uint64]bool {
+	words := map[uint64]bool{}
+	for _, word := range splitWords(fold(title)) {
+		if len(word) < 2 || titleNoise[word] {
+			continue
+		}
+		if seniority := SeniorityOf(word); seniority != "" {
+			word = "level:" + seniority
+		}
+		words[hashWords(word)] = true
+	}
+	return words
+}
+
+// shingles are the runs of shingleSize consecutive words of a text.
+func shingles(text string) map[uint64]bool {
+	words := splitWords(fold(text))
+	set := map[uint64]bool{}
+	if len(words) < shingleSize {
+		set[hashWords(words...)] = true
+		return set
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		set[hashWords(words[i:i+shingleSize]...)] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[uint64]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func splitWords(folded string) []string {
+	return strings.FieldsFunc(folded, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+func hashWords(words ...string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(words, " ")))
+	return h.Sum64()
+}
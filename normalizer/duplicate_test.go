@@ -0,0 +1,49 @@
+package normalizer
+
+import (
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyKey(t *testing.T) {
+	assert.Equal(t, "nubank", CompanyKey("Nubank"))
+	assert.Equal(t, "nubank", CompanyKey("Nubank Carreiras"))
+	assert.Equal(t, "stone", CompanyKey("Stone (Gupy — Stone Tech)"))
+	assert.Equal(t, "itau unibanco", CompanyKey("Itaú Unibanco S.A."))
+	assert.Equal(t, "grupo boticario", CompanyKey("Grupo Boticário - Gupy"))
+	assert.Equal(t, "", CompanyKey("Vagas"))
+}
+
+func TestIsDuplicate(t *testing.T) {
+	description := "Buscamos uma pessoa desenvolvedora para construir APIs em Go, com foco em pagamentos, " +
+		"observabilidade e alta disponibilidade. Você vai trabalhar com Kubernetes, PostgreSQL e Kafka."
+	base := model.Job{Title: "Desenvolvedor(a) Backend Sênior", Description: description,
+		LocationCity: "São Paulo", LocationState: "SP", CompanyKey: "acme"}
+
+	tests := []struct {
+		name  string
+		other model.Job
+		want  bool
+	}{
+		{"same opening on the job board", model.Job{Title: "Desenvolvedor Backend Sr", Description: "Sobre a vaga: " + description + " Benefícios: VR.",
+			LocationCity: "São Paulo", LocationState: "SP", CompanyKey: "acme"}, true},
+		{"board without location", model.Job{Title: "Desenvolvedor Backend Sênior", Description: description, CompanyKey: "acme"}, true},
+		{"listing without description needs the same title", model.Job{Title: "Desenvolvedor Backend Sênior", CompanyKey: "acme"}, true},
+		{"listing without description and another level", model.Job{Title: "Desenvolvedor Backend Pleno", CompanyKey: "acme"}, false},
+		{"another city", model.Job{Title: "Desenvolvedor Backend Sênior", Description: description,
+			LocationCity: "Curitiba", LocationState: "PR", CompanyKey: "acme"}, false},
+		{"another company", model.Job{Title: "Desenvolvedor Backend Sênior", Description: description, CompanyKey: "beta"}, false},
+		{"another role", model.Job{Title: "Desenvolvedor Frontend Sênior", Description: description, CompanyKey: "acme"}, false},
+		{"same title, different text", model.Job{Title: "Desenvolvedor Backend Sênior", CompanyKey: "acme",
+			Description: "Time de crédito procura pessoa para manter sistemas legados em Java e migrar serviços para a nuvem."}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDuplicate(base, tt.other))
+			assert.Equal(t, tt.want, IsDuplicate(tt.other, base))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package normalizer
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// municipiosCSV lists Brazilian municipalities as "municipio,uf", generated from
+// the IBGE localities API by tools/ibgemunicipios.
+//
+//go:generate go run ../tools/ibgemunicipios -out data/municipios.csv
+//go:embed data/municipios.csv
+var municipiosCSV string
+
+// CountryBrazil is the ISO 3166-1 alpha-2 code of Brazil.
+const CountryBrazil = "BR"
+
+// minFallbackCityLength keeps short city names from matching inside free text.
+const minFallbackCityLength = 4
+
+// Location is the structured form of a job's location text. Fields not found are empty.
+type Location struct {
+	City    string
+	State   string // UF, e.g. "SP"
+	Country string // ISO 3166-1 alpha-2, e.g. "BR"
+}
+
+var states = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AP": "Amapá", "AM": "Amazonas", "BA": "Bahia",
+	"CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo", "GO": "Goiás",
+	"MA": "Maranhão", "MT": "Mato Grosso", "MS": "Mato Grosso do Sul", "MG": "Minas Gerais",
+	"PA": "Pará", "PB": "Paraíba", "PR": "Paraná", "PE": "Pernambuco", "PI": "Piauí",
+	"RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte", "RS": "Rio Grande do Sul",
+	"RO": "Rondônia", "RR": "Roraima", "SC": "Santa Catarina", "SP": "São Paulo",
+	"SE": "Sergipe", "TO": "Tocantins",
+}
+
+// countries maps folded country names seen in postings to their ISO code.
+var countries = map[string]string{
+	"brasil": "BR", "brazil": "BR", "br": "BR",
+	"estados unidos": "US", "united states": "US", "usa": "US", "eua": "US",
+	"portugal": "PT", "argentina": "AR", "mexico": "MX", "colombia": "CO", "chile": "CL",
+	"uruguai": "UY", "uruguay": "UY", "peru": "PE", "canada": "CA",
+	"espanha": "ES", "spain": "ES", "reino unido": "GB", "united kingdom": "GB", "uk": "GB",
+	"alemanha": "DE", "germany": "DE", "irlanda": "IE", "ireland": "IE",
+	"franca": "FR", "france": "FR",
+}
+
+type city struct {
+	name  string
+	state string
+}
+
+type gazetteer struct {
+	// cities and stateNames are keyed by folded name; a name may be shared by
+	// cities of different states.
+	cities     map[string][]city
+	stateNames map[string]string
+	// byLength holds the folded city names, longest first, for the free-text search.
+	byLength []string
+}
+
+// newGazetteer indexes a "municipio,uf" CSV with a header row.
+func newGazetteer(municipios string) (*gazetteer, error) {
+	g := &gazetteer{
+		cities:     make(map[string][]city),
+		stateNames: make(map[string]string, len(states)),
+	}
+	for uf, name := range states {
+		g.stateNames[fold(name)] = uf
+	}
+
+	records, err := csv.NewReader(strings.NewReader(municipios)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records[1:] {
+		name, uf := strings.TrimSpace(record[0]), strings.ToUpper(strings.TrimSpace(record[1]))
+		key := fold(name)
+		if _, ok := g.cities[key]; !ok {
+			g.byLength = append(g.byLength, key)
+		}
+		g.cities[key] = append(g.cities[key], city{name: name, state: uf})
+	}
+	sort.Slice(g.byLength, func(i, j int) bool { return len(g.byLength[i]) > len(g.byLength[j]) })
+	return g, nil
+}
+
+var (
+	loadGazetteer = sync.OnceValue(func() *gazetteer {
+		g, err := newGazetteer(municipiosCSV)
+		if err != nil {
+			panic("normalizer: invalid municipios.csv: " + err.Error())
+		}
+		return g
+	})
+
+	locationSeparators = regexp.MustCompile(`[,|/;()\[\]]|\s[-–—]\s`)
+	// ufSuffix splits a UF glued to the city by a dash, as in "Curitiba-PR"
+	ufSuffix = regexp.MustCompile(`^(.+?)\s*-\s*([a-z]{2})$`)
+)
+
+// ParseLocation extracts the city, state and country from a job's location text,
+// e.g. "São Paulo, SP, Brasil" or "Híbrido | Curitiba". Cities and states found are
+// Brazilian, so they also set Country.
+func ParseLocation(raw string) Location {
+	return loadGazetteer().parse(raw)
+}
+
+func (g *gazetteer) parse(raw string) Location {
+	var loc Location
+	var cityNames, stateNames []string
+	var countryCity string
+
+	for _, part := range splitLocation(fold(raw)) {
+		switch {
+		case len(part) == 2 && states[strings.ToUpper(part)] != "":
+			loc.State = strings.ToUpper(part)
+		case countries[part] != "":
+			loc.Country = countries[part]
+			if _, ok := g.cities[part]; ok {
+				countryCity = part
+			}
+		default:
+			// "São Paulo" and "Rio de Janeiro" are both a city and a state: decided below
+			if _, ok := g.cities[part]; ok {
+				cityNames = append(cityNames, part)
+			}
+			if _, ok := g.stateNames[part]; ok {
+				stateNames = append(stateNames, part)
+			}
+		}
+	}
+
+	// A state name is the city's state when another city was given ("Campinas, São Paulo")
+	var cityName string
+	for _, name := range cityNames {
+		if _, isState := g.stateNames[name]; !isState || len(cityNames) == 1 {
+			cityName = name
+			break
+		}
+	}
+	for _, name := range stateNames {
+		if name != cityName && loc.State == "" {
+			loc.State = g.stateNames[name]
+		}
+	}
+	// "Franca, SP" is the city; "França" alone is the country
+	if cityName == "" && countryCity != "" && loc.State != "" {
+		if _, ok := g.pick(countryCity, loc.State); ok {
+			cityName = countryCity
+			loc.Country = ""
+		}
+	}
+	if cityName == "" {
+		cityName = g.findCity(fold(raw), loc.State)
+	}
+	if cityName != "" {
+		if c, ok := g.pick(cityName, loc.State); ok {
+			loc.City = c.name
+			loc.State = c.state
+		}
+	}
+
+	if loc.Country == "" && (loc.City != "" || loc.State != "") {
+		loc.Country = CountryBrazil
+	}
+	return loc
+}
+
+// pick returns the city of the given folded name in state, or the only city with
+// that name when the state is unknown.
+func (g *gazetteer) pick(name string, state string) (city, bool) {
+	candidates := g.cities[name]
+	if state == "" {
+		if len(candidates) == 1 {
+			return candidates[0], true
+		}
+		return city{}, false
+	}
+	for _, c := range candidates {
+		if c.state == state {
+			return c, true
+		}
+	}
+	return city{}, false
+}
+
+// findCity looks for the longest city name inside free text such as
+// "vaga hibrida em curitiba". When the state is known the city must be in it.
+// Cities named like a country ("Franca", "Colômbia") are skipped, as free text
+// naming them almost always means the country.
+func (g *gazetteer) findCity(text string, state string) string {
+	padded := " " + wordsOnly(text) + " "
+	for _, name := range g.byLength {
+		if len(name) < minFallbackCityLength || countries[name] != "" || !strings.Contains(padded, " "+name+" ") {
+			continue
+		}
+		if _, ok := g.pick(name, state); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func splitLocation(folded string) []string {
+	var parts []string
+	for _, part := range locationSeparators.Split(folded, -1) {
+		part = strings.TrimSpace(part)
+		if m := ufSuffix.FindStringSubmatch(part); m != nil && states[strings.ToUpper(m[2])] != "" {
+			parts = append(parts, strings.TrimSpace(m[1]), m[2])
+			continue
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// fold lowercases s, strips the accents and collapses spaces, so "SÃO  Paulo"
+// and "sao paulo" compare equal.
+func fold(s string) string {
+	return strings.Join(strings.Fields(accents.Replace(strings.ToLower(s))), " ")
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9'-]+`)
+
+// wordsOnly replaces punctuation in folded text with spaces.
+func wordsOnly(folded string) string {
+	return strings.Join(strings.Fields(nonWord.ReplaceAllString(folded, " ")), " ")
+}
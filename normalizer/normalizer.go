@@ -0,0 +1,31 @@
+// Package normalizer turns the free text scraped from job postings into structured
+// fields used by the dashboard filters and the matching.
+package normalizer
+
+import (
+	"slices"
+	"web-scrapper/model"
+)
+
+// Normalize fills the structured fields of a scraped job from its text. It runs
+// after scraping and before the job is stored.
+func Normalize(job *model.Job) {
+	loc := ParseLocation(job.Location)
+	job.LocationCity = loc.City
+	job.LocationState = loc.State
+	job.LocationCountry = loc.Country
+	job.WorkModel = DetectWorkModel(job.Location, job.Description)
+	job.Seniority = ClassifySeniority(job.Title, job.Description)
+	job.RoleFamily = ClassifyRoleFamily(job.Title, job.Description)
+	job.ContractType = ClassifyContractType(job.Title, job.Description, job.EmploymentType)
+
+	// A salary read from JSON-LD or an API mapping wins over the description
+	if job.SalaryMin == nil && job.SalaryMax == nil {
+		if salary := ParseSalary(job.Description); salary != nil {
+			job.SalaryMin, job.SalaryMax = salary.Min, salary.Max
+			job.SalaryCurrency, job.SalaryPeriod = salary.Currency, salary.Period
+		}
+	}
+	job.SalaryBasis = SalaryBasis(job.Description)
+	job.Benefits = ExtractBenefits(append(slices.Clone(job.Benefits), job.Description)...)
+}
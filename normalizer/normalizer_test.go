@@ -0,0 +1,158 @@
+package normalizer
+
+import (
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Location
+	}{
+		{"São Paulo, SP, Brasil", Location{City: "São Paulo", State: "SP", Country: "BR"}},
+		{"Híbrido | Curitiba", Location{City: "Curitiba", State: "PR", Country: "BR"}},
+		{"Remote - LATAM", Location{}},
+		{"Campinas, São Paulo", Location{City: "Campinas", State: "SP", Country: "BR"}},
+		{"SAO PAULO", Location{City: "São Paulo", State: "SP", Country: "BR"}},
+		{"Belo Horizonte-MG", Location{City: "Belo Horizonte", State: "MG", Country: "BR"}},
+		{"Florianópolis/SC (Remoto)", Location{City: "Florianópolis", State: "SC", Country: "BR"}},
+		{"Vaga presencial em Porto Alegre", Location{City: "Porto Alegre", State: "RS", Country: "BR"}},
+		{"Minas Gerais", Location{State: "MG", Country: "BR"}},
+		{"Remoto - Brasil", Location{Country: "BR"}},
+		{"Lisboa, Portugal", Location{Country: "PT"}},
+		// The city must be in the given state
+		{"Curitiba, SP", Location{State: "SP", Country: "BR"}},
+		{"", Location{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseLocation(tt.raw))
+		})
+	}
+}
+
+// Names shared by municipalities of different states, as in the IBGE list
+const homonymousMunicipios = `municipio,uf
+Bom Jesus,GO
+Bom Jesus,PB
+Bom Jesus,PI
+Bom Jesus,RN
+Bom Jesus,RS
+Bom Jesus,SC
+Colômbia,SP
+Franca,SP
+Santa Helena,MA
+Santa Helena,PB
+Santa Helena,PR
+Santa Helena,SC
+`
+
+func TestGazetteer_HomonymousCities(t *testing.T) {
+	g, err := newGazetteer(homonymousMunicipios)
+	require.NoError(t, err)
+
+	tests := []struct {
+		raw  string
+		want Location
+	}{
+		{"Bom Jesus, RS", Location{City: "Bom Jesus", State: "RS", Country: "BR"}},
+		{"Bom Jesus - PI", Location{City: "Bom Jesus", State: "PI", Country: "BR"}},
+		{"Santa Helena/PR", Location{City: "Santa Helena", State: "PR", Country: "BR"}},
+		{"Vaga presencial em Santa Helena, Santa Catarina", Location{City: "Santa Helena", State: "SC", Country: "BR"}},
+		// Without the state the city is ambiguous
+		{"Bom Jesus", Location{}},
+		{"Vaga presencial em Bom Jesus", Location{}},
+		// No Bom Jesus in São Paulo
+		{"Bom Jesus, SP", Location{State: "SP", Country: "BR"}},
+		{"Franca, SP", Location{City: "Franca", State: "SP", Country: "BR"}},
+		{"Colômbia - SP", Location{City: "Colômbia", State: "SP", Country: "BR"}},
+		{"França", Location{Country: "FR"}},
+		// Free text naming a country is not the homonymous city
+		{"Vaga remota para a França", Location{}},
+		{"Trabalho na Colômbia", Location{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.want, g.parse(tt.raw))
+		})
+	}
+}
+
+func TestGazetteer_FindCity(t *testing.T) {
+	g, err := newGazetteer(homonymousMunicipios)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bom jesus", g.findCity("vaga hibrida em bom jesus", "SC"))
+	assert.Equal(t, "", g.findCity("vaga hibrida em bom jesus", ""))
+	assert.Equal(t, "", g.findCity("vaga hibrida em bom jesus", "SP"))
+	assert.Equal(t, "", g.findCity("escritorio na franca", "SP"))
+}
+
+func TestDetectWorkModel(t *testing.T) {
+	tests := []struct {
+		name        string
+		location    string
+		description string
+		want        string
+	}{
+		{"remote location", "Remote - LATAM", "", model.WorkModelRemote},
+		{"hybrid location", "Híbrido | Curitiba", "", model.WorkModelHybrid},
+		{"onsite location", "Presencial - Recife", "", model.WorkModelOnsite},
+		{"location wins over description", "100% remoto", "Escritório presencial em SP", model.WorkModelRemote},
+		{"hybrid description mentioning home office", "São Paulo, SP", "Modelo híbrido, 2 dias de home office", model.WorkModelHybrid},
+		{"remote description", "Brasil", "Trabalho 100% remoto", model.WorkModelRemote},
+		{"on-site in english", "", "This is an on-site role", model.WorkModelOnsite},
+		{"unknown", "São Paulo, SP", "Desenvolvedor Go", ""},
+		{"word inside another word", "", "Controle remotamente os servidores", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectWorkModel(tt.location, tt.description))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	job := model.Job{Location: "Híbrido | Curitiba", Description: "Vaga para engenheiro"}
+
+	Normalize(&job)
+
+	assert.Equal(t, "Curitiba", job.LocationCity)
+	assert.Equal(t, "PR", job.LocationState)
+	assert.Equal(t, "BR", job.LocationCountry)
+	assert.Equal(t, model.WorkModelHybrid, job.WorkModel)
+}
+
+func TestNormalize_SalaryAndBenefits(t *testing.T) {
+	job := model.Job{
+		Description: "Faixa salarial: R$ 9.000 a R$ 11.000 brutos. Benefícios: vale-alimentação e seguro de vida.",
+		Benefits:    []string{"Plano de Saúde"},
+	}
+
+	Normalize(&job)
+
+	require.NotNil(t, job.SalaryMin)
+	assert.Equal(t, 9000.0, *job.SalaryMin)
+	assert.Equal(t, 11000.0, *job.SalaryMax)
+	assert.Equal(t, "BRL", job.SalaryCurrency)
+	assert.Equal(t, "month", job.SalaryPeriod)
+	assert.Equal(t, model.SalaryBasisGross, job.SalaryBasis)
+	assert.Equal(t, []string{BenefitHealthInsurance, BenefitLifeInsurance, BenefitFoodVoucher}, job.Benefits)
+}
+
+func TestNormalize_KeepsStructuredSalary(t *testing.T) {
+	salary := 15000.0
+	job := model.Job{Description: "Salário: R$ 5.000", SalaryMin: &salary, SalaryMax: &salary, SalaryCurrency: "BRL", SalaryPeriod: "month"}
+
+	Normalize(&job)
+
+	assert.Equal(t, 15000.0, *job.SalaryMin)
+	assert.Equal(t, 15000.0, *job.SalaryMax)
+}
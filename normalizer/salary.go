@@ -0,0 +1,217 @@
+package normalizer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"web-scrapper/model"
+)
+
+// Salary is a pay range found in a posting. Min and Max are equal for a single
+// amount, and one of them is nil for "até R$ 10.000" or "a partir de R$ 5.000".
+type Salary struct {
+	Min      *float64
+	Max      *float64
+	Currency string // ISO 4217, e.g. "BRL"
+	Period   string // hour, day, week, month or year, as read from JSON-LD
+}
+
+const (
+	// salaryContextWindow is how far before an amount a salary keyword is looked for.
+	salaryContextWindow = 80
+	// periodWindow is how far after an amount its period is looked for.
+	periodWindow = 40
+)
+
+var (
+	// An amount with its currency: "r$ 8.000,00", "us$ 120k", "usd 5,000", "€ 3.000", "r$ 12 mil".
+	// The multiplier must end a word, so "r$ 120 milhoes" or "r$ 8.000 kit" is not read as thousands.
+	amountPattern = `(r\$|us\$|usd|brl|eur|€|\$)\s*(\d[\d.,]*)(?:\s*(mil|k)\b)?`
+	// A second amount may repeat the currency or not: "r$ 8.000 a 12.000"
+	salaryRange = regexp.MustCompile(`(?:(a partir de|acima de|from|starting at|ate|up to)\s+)?` + amountPattern +
+		`(?:\s*(?:a|ate|-|–|—|to|e|~)\s*(?:(?:r\$|us\$|usd|brl|eur|€|\$)\s*)?(\d[\d.,]*)(?:\s*(mil|k)\b)?)?`)
+	// The start of another amount, which ends the text read for a period
+	currencyPattern = regexp.MustCompile(`r\$|us\$|\busd\b|\bbrl\b|\beur\b|€|\$`)
+	salaryContext   = regexp.MustCompile(`\b(salario|salarial|remuneracao|faixa|bolsa[- ]auxilio|bolsa|salary|compensation|pay range|pay|ctc)\b`)
+	benefitContext  = regexp.MustCompile(`\b(vale|auxilio|va|vr|vt|ticket|reembolso|beneficio|beneficios|bonus|plr)\b`)
+
+	salaryPeriods = []struct {
+		period  string
+		pattern *regexp.Regexp
+	}{
+		{"hour", regexp.MustCompile(`(/\s*h(ora|our)?\b|por hora|\bhourly\b|per hour|/\s*hr\b)`)},
+		{"day", regexp.MustCompile(`(/\s*(dia|day)\b|por dia|\bdaily\b|per day)`)},
+		{"week", regexp.MustCompile(`(/\s*(semana|week)\b|por semana|\bsemanal\b|\bweekly\b|per week)`)},
+		{"month", regexp.MustCompile(`(/\s*(mes|month)\b|por mes|\bmensa(l|is)\b|\bmonthly\b|per month|/\s*mo\b)`)},
+		{"year", regexp.MustCompile(`(/\s*(ano|year)\b|por ano|\banua(l|is)\b|\bannual(ly)?\b|per year|/\s*y(ea)?r\b|\ba\.a\.)`)},
+	}
+	// periodWords are the single words APIs use that the patterns above miss
+	periodWords = map[string]string{
+		"hora": "hour", "dia": "day", "semana": "week", "mes": "month", "ano": "year", "yearly": "year",
+	}
+	grossPattern = regexp.MustCompile(`\b(bruto|brutos|bruta|gross)\b`)
+	// "net", but not ".NET"
+	netPattern = regexp.MustCompile(`\b(liquido|liquidos|liquida)\b|(^|[^.\w])net\b`)
+
+	currencies = map[string]string{"r$": "BRL", "brl": "BRL", "us$": "USD", "usd": "USD", "$": "USD", "eur": "EUR", "€": "EUR"}
+)
+
+// ParseSalary finds the pay range in a description: the first amount with a
+// currency that follows a salary keyword ("faixa salarial", "remuneração"), or
+// else the first range that is not a benefit ("R$ 8.000 a R$ 12.000"). Amounts
+// in reais without a period are taken as monthly. It returns nil when none is found.
+func ParseSalary(text string) *Salary {
+	return parseSalary(text, false)
+}
+
+// ParseSalaryField reads a field that only holds the pay, such as an API's
+// "salary" string ("R$ 5.000,00"), so any amount found is taken as the salary.
+func ParseSalaryField(text string) *Salary {
+	return parseSalary(text, true)
+}
+
+// SalaryPeriodOf maps a period read from an API ("MONTHLY", "mensal", "hora")
+// to hour, day, week, month or year, or "" when it is not one.
+func SalaryPeriodOf(value string) string {
+	folded := fold(value)
+	for _, p := range salaryPeriods {
+		if folded == p.period || p.pattern.MatchString(folded) {
+			return p.period
+		}
+	}
+	if period, ok := periodWords[folded]; ok {
+		return period
+	}
+	return ""
+}
+
+func parseSalary(text string, labeled bool) *Salary {
+	folded := fold(text)
+	var fallback *Salary
+	for _, m := range salaryRange.FindAllStringSubmatchIndex(folded, -1) {
+		before := folded[max(0, m[0]-salaryContextWindow):m[0]]
+		// A benefit right before the amount wins over an earlier salary keyword
+		if !labeled && lastMatchEnd(benefitContext, before) > lastMatchEnd(salaryContext, before) {
+			continue
+		}
+		salary := salaryFromMatch(folded, m)
+		if salary == nil {
+			continue
+		}
+		if labeled || salaryContext.MatchString(before) {
+			return salary
+		}
+		isRange := salary.Min != nil && salary.Max != nil && *salary.Min < *salary.Max
+		if fallback == nil && isRange {
+			fallback = salary
+		}
+	}
+	return fallback
+}
+
+// SalaryBasis tells whether the text says the pay is gross or net, or "".
+func SalaryBasis(text string) string {
+	folded := fold(text)
+	for _, m := range salaryContext.FindAllStringIndex(folded, -1) {
+		near := folded[m[0]:min(len(folded), m[1]+salaryContextWindow)]
+		switch {
+		case grossPattern.MatchString(near):
+			return model.SalaryBasisGross
+		case netPattern.MatchString(near):
+			return model.SalaryBasisNet
+		}
+	}
+	return ""
+}
+
+func salaryFromMatch(folded string, m []int) *Salary {
+	group := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return folded[m[2*i]:m[2*i+1]]
+	}
+	qualifier, currency := group(1), currencies[group(2)]
+
+	low := parseAmount(group(3), group(4))
+	if low == nil {
+		return nil
+	}
+	salary := &Salary{Currency: currency, Min: low, Max: low}
+	if group(5) != "" {
+		high := parseAmount(group(5), group(6))
+		// "R$ 8 a 12 mil" scales the first amount like the second
+		if high != nil && group(4) == "" && group(6) != "" && *low < 1000 {
+			low = parseAmount(group(3), group(6))
+		}
+		// Otherwise the second number is not a salary ("R$ 5.000 - 30 dias de férias")
+		if high != nil && *high >= *low {
+			salary.Min, salary.Max = low, high
+		}
+	} else {
+		switch qualifier {
+		case "ate", "up to":
+			salary.Min = nil
+		case "a partir de", "acima de", "from", "starting at":
+			salary.Max = nil
+		}
+	}
+
+	after := folded[m[1]:min(len(folded), m[1]+periodWindow)]
+	// The period of a following amount is not this one's ("R$ 8.000 + VR R$ 40/dia")
+	if next := currencyPattern.FindStringIndex(after); next != nil {
+		after = after[:next[0]]
+	}
+	for _, p := range salaryPeriods {
+		if p.pattern.MatchString(after) {
+			salary.Period = p.period
+			break
+		}
+	}
+	if salary.Period == "" && currency == "BRL" {
+		salary.Period = "month"
+	}
+	return salary
+}
+
+// parseAmount reads "8.000", "8.000,50", "5,000.00", "12,5" or "120" followed by
+// an optional "mil"/"k" multiplier, in Brazilian or English notation.
+func parseAmount(number string, multiplier string) *float64 {
+	number = strings.TrimRight(number, ".,")
+	lastDot, lastComma := strings.LastIndex(number, "."), strings.LastIndex(number, ",")
+	decimalSep := ""
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		decimalSep = number[max(lastDot, lastComma) : max(lastDot, lastComma)+1]
+	case lastDot >= 0 && len(number)-lastDot-1 != 3:
+		decimalSep = "."
+	case lastComma >= 0 && len(number)-lastComma-1 != 3:
+		decimalSep = ","
+	}
+
+	var b strings.Builder
+	for i, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case string(r) == decimalSep && (i == lastDot || i == lastComma):
+			b.WriteRune('.')
+		}
+	}
+	value, err := strconv.ParseFloat(b.String(), 64)
+	if err != nil || value <= 0 {
+		return nil
+	}
+	if multiplier == "mil" || multiplier == "k" {
+		value *= 1000
+	}
+	return &value
+}
+
+func lastMatchEnd(pattern *regexp.Regexp, text string) int {
+	matches := pattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return -1
+	}
+	return matches[len(matches)-1][1]
+}
@@ -0,0 +1,78 @@
+package normalizer
+
+import (
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestParseSalary(t *testing.T) {
+	tests := []struct {
+		text string
+		want *Salary
+	}{
+		{"Faixa salarial: R$ 8.000 a R$ 12.000", &Salary{Min: ptr(8000), Max: ptr(12000), Currency: "BRL", Period: "month"}},
+		{"Remuneração: R$ 5.000,00 bruto mensal", &Salary{Min: ptr(5000), Max: ptr(5000), Currency: "BRL", Period: "month"}},
+		{"Salário de até R$ 10.000", &Salary{Max: ptr(10000), Currency: "BRL", Period: "month"}},
+		{"Salário a partir de R$ 6.500,50", &Salary{Min: ptr(6500.5), Currency: "BRL", Period: "month"}},
+		{"Salário: R$ 8 a 12 mil", &Salary{Min: ptr(8000), Max: ptr(12000), Currency: "BRL", Period: "month"}},
+		{"Bolsa: R$ 2 mil, mensal", &Salary{Min: ptr(2000), Max: ptr(2000), Currency: "BRL", Period: "month"}},
+		{"Salário: R$ 8.000 kit home office", &Salary{Min: ptr(8000), Max: ptr(8000), Currency: "BRL", Period: "month"}},
+		{"Pay range: US$ 50 - 70/hour", &Salary{Min: ptr(50), Max: ptr(70), Currency: "USD", Period: "hour"}},
+		{"Compensation: $120k - $150k per year", &Salary{Min: ptr(120000), Max: ptr(150000), Currency: "USD", Period: "year"}},
+		{"Oferecemos R$ 7.000 - R$ 9.000 + benefícios", &Salary{Min: ptr(7000), Max: ptr(9000), Currency: "BRL", Period: "month"}},
+		{"Salário: R$ 8.000 + VR R$ 40/dia", &Salary{Min: ptr(8000), Max: ptr(8000), Currency: "BRL", Period: "month"}},
+		{"Benefícios: VR R$ 40/dia e vale transporte", nil},
+		{"Salário compatível com o mercado. Auxílio home office de R$ 150", nil},
+		{"Experiência com .NET e SQL", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseSalary(tt.text))
+		})
+	}
+}
+
+func TestParseSalaryField(t *testing.T) {
+	salary := ParseSalaryField("R$ 4.500,00")
+
+	require.NotNil(t, salary)
+	assert.Equal(t, 4500.0, *salary.Min)
+	assert.Equal(t, "BRL", salary.Currency)
+	assert.Nil(t, ParseSalary("R$ 4.500,00"))
+
+	// "mil" only multiplies when it is the whole word
+	salary = ParseSalaryField("R$ 120 milhões")
+	require.NotNil(t, salary)
+	assert.Equal(t, 120.0, *salary.Min)
+}
+
+func TestSalaryPeriodOf(t *testing.T) {
+	assert.Equal(t, "month", SalaryPeriodOf("MONTH"))
+	assert.Equal(t, "month", SalaryPeriodOf("Mensal"))
+	assert.Equal(t, "hour", SalaryPeriodOf("hora"))
+	assert.Equal(t, "year", SalaryPeriodOf("yearly"))
+	assert.Equal(t, "", SalaryPeriodOf("CLT"))
+}
+
+func TestSalaryBasis(t *testing.T) {
+	assert.Equal(t, model.SalaryBasisGross, SalaryBasis("Salário: R$ 5.000 (bruto)"))
+	assert.Equal(t, model.SalaryBasisNet, SalaryBasis("Remuneração líquida de R$ 4.000"))
+	assert.Equal(t, "", SalaryBasis("Salário: R$ 5.000. Stack: .NET e SQL"))
+	assert.Equal(t, "", SalaryBasis("Lucro bruto da empresa cresceu"))
+}
+
+func TestExtractBenefits(t *testing.T) {
+	description := "Benefícios: plano de saúde e odontológico, VR, Gympass, PLR e day off no aniversário."
+
+	assert.Equal(t, []string{BenefitHealthInsurance, BenefitDentalInsurance, BenefitMealVoucher, BenefitGym, BenefitProfitSharing, BenefitBirthdayDayOff},
+		ExtractBenefits(description))
+	assert.Equal(t, []string{BenefitMealVoucher, BenefitTransportVoucher}, ExtractBenefits("Vale Transporte", "Vale-Refeição"))
+	assert.Equal(t, []string{BenefitMealVoucher}, ExtractBenefits(BenefitMealVoucher, "Mesa de ping-pong"))
+	assert.Empty(t, ExtractBenefits("Trabalhe com Java e Kubernetes"))
+}
@@ -0,0 +1,33 @@
+package normalizer
+
+import (
+	"regexp"
+	"web-scrapper/model"
+)
+
+// workModelPatterns are matched against folded text, in order: a posting saying
+// "híbrido" often also mentions "home office" days, and a remote one may mention
+// an office.
+var workModelPatterns = []struct {
+	workModel string
+	pattern   *regexp.Regexp
+}{
+	{model.WorkModelHybrid, regexp.MustCompile(`\b(hibrid[oa]s?|hybrid)\b`)},
+	{model.WorkModelRemote, regexp.MustCompile(`\b(remot[oa]s?|remote|home[ -]office|teletrabalho|anywhere|work from home|wfh)\b`)},
+	{model.WorkModelOnsite, regexp.MustCompile(`\b(presencial|on[ -]?site|in[ -]office)\b`)},
+}
+
+// DetectWorkModel tells whether a job is remote, hybrid or on-site. The location
+// text is trusted over the description, which is only read when the location says
+// nothing. It returns "" when neither mentions a work model.
+func DetectWorkModel(location string, description string) string {
+	for _, text := range []string{location, description} {
+		folded := fold(text)
+		for _, p := range workModelPatterns {
+			if p.pattern.MatchString(folded) {
+				return p.workModel
+			}
+		}
+	}
+	return ""
+}
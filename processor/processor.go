@@ -3,12 +3,15 @@ package processor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 	"web-scrapper/interfaces"
 	"web-scrapper/logging"
+	"web-scrapper/model"
 	"web-scrapper/repository"
+	"web-scrapper/scrapper"
 	"web-scrapper/tasks"
 	"web-scrapper/usecase"
 
@@ -22,6 +25,8 @@ type TaskProcessor struct {
 	emailService   interfaces.EmailService
 	dashboardRepo  *repository.DashboardRepository
 	userRepo       *repository.UserRepository
+	siteHealth     *usecase.SiteHealthUsecase
+	siteAnomalies  *usecase.SiteAnomalyUsecase
 }
 
 func NewTaskProcessor(
@@ -31,6 +36,8 @@ func NewTaskProcessor(
 	emailSvc interfaces.EmailService,
 	dashboardRepo *repository.DashboardRepository,
 	userRepo *repository.UserRepository,
+	siteHealth *usecase.SiteHealthUsecase,
+	siteAnomalies *usecase.SiteAnomalyUsecase,
 ) *TaskProcessor {
 	return &TaskProcessor{
 		_scraper:       scraper,
@@ -39,6 +46,8 @@ func NewTaskProcessor(
 		emailService:   emailSvc,
 		dashboardRepo:  dashboardRepo,
 		userRepo:       userRepo,
+		siteHealth:     siteHealth,
+		siteAnomalies:  siteAnomalies,
 	}
 }
 
@@ -52,7 +61,20 @@ func (p *TaskProcessor) HandleScrapeSiteTask(ctx context.Context, t *asynq.Task)
 
 	logging.Logger.Info().Int("site_id", payload.SiteID).Msg("Processing task to scrap site")
 
-	_, err := p._scraper.ScrapeAndStoreJobs(ctx, payload.SiteScrapingConfig)
+	startedAt := time.Now()
+	_, counts, err := p._scraper.ScrapeAndStoreJobs(ctx, payload.SiteScrapingConfig)
+	p.recordScrapeRun(ctx, payload, t.ResultWriter().TaskID(), startedAt, counts, err)
+	var skip *scrapper.PolicySkipError
+	if errors.As(err, &skip) {
+		logging.Logger.Info().Int("site_id", payload.SiteID).Str("reason", skip.Reason).Str("url", skip.URL).Msg("Site skipped by politeness policy")
+		if p.dashboardRepo != nil {
+			recErr := p.dashboardRepo.RecordPolicySkip(payload.SiteID, payload.SiteScrapingConfig.SiteName, skip.Reason, skip.URL, t.ResultWriter().TaskID())
+			if recErr != nil {
+				logging.Logger.Error().Err(recErr).Msg("Failed to record policy skip")
+			}
+		}
+		return nil
+	}
 	if err != nil {
 		logging.Logger.Warn().Err(err).Int("site_id", payload.SiteID).Msg("ScrapeAndStoreJobs failed but task will not be retried")
 		if p.dashboardRepo != nil {
@@ -63,10 +85,42 @@ func (p *TaskProcessor) HandleScrapeSiteTask(ctx context.Context, t *asynq.Task)
 		}
 	}
 
-	logging.Logger.Info().Int("site_id", payload.SiteID).Msg("Scraping task completed")
+	logging.Logger.Info().Int("site_id", payload.SiteID).Int("listed", counts.Listed).Int("new", counts.New).Int("missing", counts.Missing).Msg("Scraping task completed")
 	return nil
 }
 
+// recordScrapeRun writes the run to the site's history, whatever its outcome, and
+// checks the updated history for anomalies.
+func (p *TaskProcessor) recordScrapeRun(ctx context.Context, payload tasks.ScrapeSitePayload, taskID string, startedAt time.Time, counts model.ScrapeCounts, err error) {
+	if p.siteHealth == nil {
+		return
+	}
+	run := model.ScrapeRun{
+		SiteID:       payload.SiteID,
+		SiteName:     payload.SiteScrapingConfig.SiteName,
+		TaskID:       taskID,
+		Strategy:     payload.SiteScrapingConfig.ScrapingType,
+		StartedAt:    startedAt,
+		DurationMs:   time.Since(startedAt).Milliseconds(),
+		ScrapeCounts: counts,
+		ErrorClass:   scrapper.ClassifyError(err),
+	}
+	if err != nil {
+		run.ErrorMessage = err.Error()
+	}
+	if recErr := p.siteHealth.RecordRun(run); recErr != nil {
+		logging.Logger.Error().Err(recErr).Int("site_id", payload.SiteID).Msg("Failed to record scrape run")
+		return
+	}
+
+	if p.siteAnomalies == nil || run.ErrorClass == model.ScrapeErrorPolicySkip {
+		return
+	}
+	if _, checkErr := p.siteAnomalies.CheckSite(ctx, payload.SiteID, run.SiteName); checkErr != nil {
+		logging.Logger.Error().Err(checkErr).Int("site_id", payload.SiteID).Msg("Failed to check site for scrape anomalies")
+	}
+}
+
 func (p *TaskProcessor) HandleMatchUserTask(ctx context.Context, t *asynq.Task) error {
 	var payload tasks.MatchUserPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"web-scrapper/logging"
 	"web-scrapper/model"
+
+	"github.com/lib/pq"
 )
 
 type DashboardRepository struct{
@@ -44,7 +46,7 @@ func (dr *DashboardRepository) GetDashboardData(userID int) (model.DashboardData
                     SELECT j.id, j.title, j.location, j.company, j.job_link, j.requisition_id, j.last_seen_at
                     FROM jobs j
                     JOIN user_sites us ON j.site_id = us.site_id
-                    WHERE us.user_id = $1
+                    WHERE us.user_id = $1 AND j.status = 'open'
                     ORDER BY j.last_seen_at DESC
                     LIMIT 5
                 ) j
@@ -93,7 +95,12 @@ func (dr *DashboardRepository) GetDashboardData(userID int) (model.DashboardData
 	return dashboardData, nil
 }
 
-func (dr *DashboardRepository) GetAllJobs(userID, days int, search string, matchedOnly bool) (model.JobsResponse, error) {
+// salaryMonthlyExpr puts a job's salary on a monthly scale, using the top of the
+// range, so that hourly, monthly and yearly salaries compare.
+const salaryMonthlyExpr = `COALESCE(j.salary_max, j.salary_min) * CASE j.salary_period
+		WHEN 'hour' THEN 220 WHEN 'day' THEN 22 WHEN 'week' THEN 4.33 WHEN 'year' THEN 1.0 / 12 ELSE 1 END`
+
+func (dr *DashboardRepository) GetAllJobs(userID int, filters model.JobFilters) (model.JobsResponse, error) {
 	var result model.JobsResponse
 
 	matchedExpr := `
@@ -111,24 +118,73 @@ func (dr *DashboardRepository) GetAllJobs(userID, days int, search string, match
 		FROM jobs j
 		JOIN user_sites us ON j.site_id = us.site_id AND us.user_id = $1`
 
-	whereClause := ` WHERE 1=1`
+	// Closed and archived jobs only show up when the user applied to them
+	whereClause := ` WHERE (j.status = 'open' OR ja.id IS NOT NULL)`
 
 	args := []interface{}{userID}
 	argIdx := 2
 
-	if days > 0 {
+	if filters.Days > 0 {
 		whereClause += fmt.Sprintf(" AND j.created_at >= NOW() - INTERVAL '1 day' * $%d", argIdx)
-		args = append(args, days)
+		args = append(args, filters.Days)
 		argIdx++
 	}
 
-	if search != "" {
+	if filters.Search != "" {
 		whereClause += fmt.Sprintf(" AND LOWER(j.title) LIKE '%%' || LOWER($%d) || '%%'", argIdx)
-		args = append(args, search)
+		args = append(args, filters.Search)
+		argIdx++
+	}
+
+	if filters.WorkModel != "" {
+		whereClause += fmt.Sprintf(" AND j.work_model = $%d", argIdx)
+		args = append(args, filters.WorkModel)
+		argIdx++
+	}
+
+	if filters.State != "" {
+		whereClause += fmt.Sprintf(" AND j.location_state = UPPER($%d)", argIdx)
+		args = append(args, filters.State)
+		argIdx++
+	}
+
+	if filters.City != "" {
+		whereClause += fmt.Sprintf(" AND LOWER(j.location_city) = LOWER($%d)", argIdx)
+		args = append(args, filters.City)
+		argIdx++
+	}
+
+	if filters.Seniority != "" {
+		whereClause += fmt.Sprintf(" AND j.seniority = $%d", argIdx)
+		args = append(args, filters.Seniority)
+		argIdx++
+	}
+
+	if filters.RoleFamily != "" {
+		whereClause += fmt.Sprintf(" AND j.role_family = $%d", argIdx)
+		args = append(args, filters.RoleFamily)
 		argIdx++
 	}
 
-	if matchedOnly {
+	if filters.ContractType != "" {
+		whereClause += fmt.Sprintf(" AND j.contract_type = $%d", argIdx)
+		args = append(args, filters.ContractType)
+		argIdx++
+	}
+
+	if filters.SalaryMin > 0 {
+		whereClause += fmt.Sprintf(" AND %s >= $%d", salaryMonthlyExpr, argIdx)
+		args = append(args, filters.SalaryMin)
+		argIdx++
+	}
+
+	if filters.SalaryCurrency != "" {
+		whereClause += fmt.Sprintf(" AND j.salary_currency = UPPER($%d)", argIdx)
+		args = append(args, filters.SalaryCurrency)
+		argIdx++
+	}
+
+	if filters.MatchedOnly {
 		whereClause += fmt.Sprintf(` AND (%s)`, matchedExpr)
 	}
 
@@ -145,12 +201,24 @@ func (dr *DashboardRepository) GetAllJobs(userID, days int, search string, match
 	)
 	args = append(args, userID)
 
+	orderBy := "j.created_at DESC"
+	switch filters.Sort {
+	case model.JobSortSalaryDesc:
+		orderBy = "salary_monthly DESC NULLS LAST, j.created_at DESC"
+	case model.JobSortSalaryAsc:
+		orderBy = "salary_monthly ASC NULLS LAST, j.created_at DESC"
+	}
+
 	dataQuery := fmt.Sprintf(
-		`SELECT DISTINCT j.id, j.site_id, j.title, j.location, j.company, j.job_link, j.requisition_id, COALESCE(j.description, '') AS description, (%s) AS matched, (%s) AS has_analysis, j.created_at, ja.id, ja.status, ja.interview_round
+		`SELECT DISTINCT j.id, j.site_id, j.title, j.location, j.company, j.job_link, j.requisition_id, COALESCE(j.description, '') AS description, j.content_updated_at, j.status, j.closed_at,
+			COALESCE(j.location_city, ''), COALESCE(j.location_state, ''), COALESCE(j.location_country, ''), COALESCE(j.work_model, ''),
+			COALESCE(j.seniority, ''), COALESCE(j.role_family, ''), COALESCE(j.contract_type, ''),
+			j.salary_min, j.salary_max, COALESCE(j.salary_currency, ''), COALESCE(j.salary_period, ''), COALESCE(j.salary_basis, ''), j.benefits, (%s) AS salary_monthly,
+			(%s) AS matched, (%s) AS has_analysis, j.created_at, ja.id, ja.status, ja.interview_round
 		%s%s%s
-		ORDER BY j.created_at DESC
+		ORDER BY %s
 		LIMIT 2000`,
-		matchedExpr, hasAnalysisExpr, fromClause, applicationJoin, whereClause,
+		salaryMonthlyExpr, matchedExpr, hasAnalysisExpr, fromClause, applicationJoin, whereClause, orderBy,
 	)
 
 	rows, err := dr.connection.Query(dataQuery, args...)
@@ -161,7 +229,7 @@ func (dr *DashboardRepository) GetAllJobs(userID, days int, search string, match
 
 	for rows.Next() {
 		var job model.JobWithMatch
-		if err := rows.Scan(&job.ID, &job.SiteID, &job.Title, &job.Location, &job.Company, &job.JobLink, &job.RequisitionID, &job.Description, &job.Matched, &job.HasAnalysis, &job.CreatedAt, &job.ApplicationID, &job.ApplicationStatus, &job.InterviewRound); err != nil {
+		if err := rows.Scan(&job.ID, &job.SiteID, &job.Title, &job.Location, &job.Company, &job.JobLink, &job.RequisitionID, &job.Description, &job.EditedAt, &job.Status, &job.ClosedAt, &job.LocationCity, &job.LocationState, &job.LocationCountry, &job.WorkModel, &job.Seniority, &job.RoleFamily, &job.ContractType, &job.SalaryMin, &job.SalaryMax, &job.SalaryCurrency, &job.SalaryPeriod, &job.SalaryBasis, (*pq.StringArray)(&job.Benefits), &job.SalaryMonthly, &job.Matched, &job.HasAnalysis, &job.CreatedAt, &job.ApplicationID, &job.ApplicationStatus, &job.InterviewRound); err != nil {
 			return result, fmt.Errorf("erro ao ler vaga: %w", err)
 		}
 		result.Jobs = append(result.Jobs, job)
@@ -184,7 +252,8 @@ func (dr *DashboardRepository) GetAdminDashboardData() (model.AdminDashboardData
             COALESCE((SELECT SUM(p.price) FROM users u JOIN plans p ON u.plan_id = p.id WHERE p.price > 0), 0) AS total_revenue,
             (SELECT COUNT(*) FROM users) AS active_users,
             (SELECT COUNT(*) FROM site_scraping_config WHERE is_active = TRUE) AS monitored_sites,
-            (SELECT COUNT(*) FROM scraping_errors WHERE created_at >= NOW() - INTERVAL '24 hours') AS scraping_errors
+            (SELECT COUNT(*) FROM scraping_errors WHERE created_at >= NOW() - INTERVAL '24 hours') AS scraping_errors,
+            (SELECT COUNT(*) FROM scraping_policy_skips WHERE created_at >= NOW() - INTERVAL '24 hours') AS policy_skips
     `
 
 	err := dr.connection.QueryRow(query).Scan(
@@ -192,6 +261,7 @@ func (dr *DashboardRepository) GetAdminDashboardData() (model.AdminDashboardData
 		&data.ActiveUsers,
 		&data.MonitoredSites,
 		&data.ScrapingErrors,
+		&data.PolicySkips,
 	)
 	if err != nil {
 		return model.AdminDashboardData{}, fmt.Errorf("erro ao buscar dados do admin dashboard: %w", err)
@@ -226,6 +296,17 @@ func (dr *DashboardRepository) RecordScrapingError(siteID int, siteName string,
 	return nil
 }
 
+// RecordPolicySkip records a scrape that was not run because robots.txt or the
+// per-domain request budget did not allow it.
+func (dr *DashboardRepository) RecordPolicySkip(siteID int, siteName string, reason string, url string, taskID string) error {
+	query := `INSERT INTO scraping_policy_skips (site_id, site_name, reason, url, task_id) VALUES ($1, $2, $3, $4, $5)`
+	_, err := dr.connection.Exec(query, siteID, siteName, reason, url, taskID)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar scraping ignorado por política: %w", err)
+	}
+	return nil
+}
+
 type PublicStats struct {
 	MonitoredSites int `json:"monitored_sites"`
 	TotalJobs      int `json:"total_jobs"`
@@ -92,7 +92,7 @@ func (r *JobApplicationRepository) Delete(id, userID int) error {
 func (r *JobApplicationRepository) GetAllByUser(userID int) ([]model.JobApplicationWithJob, error) {
 	query := `
 		SELECT ja.id, ja.user_id, ja.job_id, ja.status, ja.interview_round, ja.notes, ja.applied_at, ja.updated_at,
-		       j.title, j.company, j.location, j.job_link
+		       j.title, j.company, j.location, j.job_link, j.status, j.closed_at
 		FROM job_applications ja
 		JOIN jobs j ON j.id = ja.job_id
 		WHERE ja.user_id = $1
@@ -109,7 +109,7 @@ func (r *JobApplicationRepository) GetAllByUser(userID int) ([]model.JobApplicat
 		var a model.JobApplicationWithJob
 		if err := rows.Scan(
 			&a.ID, &a.UserID, &a.JobID, &a.Status, &a.InterviewRound, &a.Notes, &a.AppliedAt, &a.UpdatedAt,
-			&a.Job.Title, &a.Job.Company, &a.Job.Location, &a.Job.JobLink,
+			&a.Job.Title, &a.Job.Company, &a.Job.Location, &a.Job.JobLink, &a.Job.Status, &a.Job.ClosedAt,
 		); err != nil {
 			return nil, fmt.Errorf("erro ao ler candidatura: %w", err)
 		}
@@ -19,15 +19,30 @@ func NewJobRepository(db *sql.DB) *JobRepository {
 	}
 }
 
+// CreateJob stores the job and its first revision.
 func (usr *JobRepository) CreateJob(job model.Job) (int, error) {
-	query := `INSERT INTO jobs (title, location, company, job_link, requisition_ID, description, site_id) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	query := `WITH inserted AS (
+			INSERT INTO jobs (title, location, company, job_link, requisition_ID, description, site_id,
+				employment_type, date_posted, salary_min, salary_max, salary_currency, salary_period, requisition_id_synthetic,
+				content_hash, content_updated_at, details_fetched_at, location_city, location_state, location_country, work_model,
+				seniority, role_family, contract_type, salary_basis, benefits, company_key)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''), $14, NULLIF($15, ''), NOW(), NOW(),
+				NULLIF($16, ''), NULLIF($17, ''), NULLIF($18, ''), NULLIF($19, ''), NULLIF($20, ''), NULLIF($21, ''), NULLIF($22, ''), NULLIF($23, ''), $24, NULLIF($25, ''))
+			RETURNING id, title, location, description, content_hash
+		)
+		INSERT INTO job_revisions (job_id, title, location, description, content_hash)
+		SELECT id, title, location, description, content_hash FROM inserted
+		RETURNING job_id`
 	queryPrepare, err := usr.connection.Prepare(query)
 	if err != nil {
 		return 0, err
 	}
 	defer queryPrepare.Close()
 
-	err = queryPrepare.QueryRow(job.Title, job.Location, job.Company, job.JobLink, job.RequisitionID, job.Description, job.SiteID).Scan(&job.ID)
+	err = queryPrepare.QueryRow(job.Title, job.Location, job.Company, job.JobLink, job.RequisitionID, job.Description, job.SiteID,
+		job.EmploymentType, job.DatePosted, job.SalaryMin, job.SalaryMax, job.SalaryCurrency, job.SalaryPeriod, job.SyntheticID, job.ContentHash,
+		job.LocationCity, job.LocationState, job.LocationCountry, job.WorkModel, job.Seniority, job.RoleFamily, job.ContractType,
+		job.SalaryBasis, textArray(job.Benefits), job.CompanyKey).Scan(&job.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -35,8 +50,8 @@ func (usr *JobRepository) CreateJob(job model.Job) (int, error) {
 	return job.ID, nil
 }
 
-func (usr *JobRepository) FindJobByRequisitionID(requisition_ID string) (bool, error) {
-	query := `SELECT COUNT(*) FROM jobs WHERE requisition_ID = $1`
+func (usr *JobRepository) FindJobByRequisitionID(siteID int, requisition_ID string) (bool, error) {
+	query := `SELECT COUNT(*) FROM jobs WHERE site_id = $1 AND requisition_ID = $2`
 	queryPrepare, err := usr.connection.Prepare(query)
 	if err != nil {
 		return false, err
@@ -44,7 +59,7 @@ func (usr *JobRepository) FindJobByRequisitionID(requisition_ID string) (bool, e
 	defer queryPrepare.Close()
 
 	var count int
-	err = queryPrepare.QueryRow(requisition_ID).Scan(&count)
+	err = queryPrepare.QueryRow(siteID, requisition_ID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -52,15 +67,17 @@ func (usr *JobRepository) FindJobByRequisitionID(requisition_ID string) (bool, e
 	return count > 0, nil
 }
 
-func (usr *JobRepository) FindJobsByRequisitionIDs(requisition_IDs []string) (map[string]bool, error){
-	query := `SELECT requisition_ID FROM jobs WHERE requisition_ID = ANY($1)`
+// FindJobsByRequisitionIDs maps each requisition ID already stored for the site to
+// its content hash ("" for jobs stored before hashes existed).
+func (usr *JobRepository) FindJobsByRequisitionIDs(siteID int, requisition_IDs []string) (map[string]string, error){
+	query := `SELECT requisition_ID, COALESCE(content_hash, '') FROM jobs WHERE site_id = $1 AND requisition_ID = ANY($2)`
 
-	Exists := make(map[string]bool)
+	Exists := make(map[string]string)
     if len(requisition_IDs) == 0 {
         return Exists, nil
     }
 
-	rows, err := usr.connection.Query(query, pq.Array(requisition_IDs) )
+	rows, err := usr.connection.Query(query, siteID, pq.Array(requisition_IDs) )
 	if err != nil {
 		return Exists, fmt.Errorf("error fetching jobs %v: %w", requisition_IDs, err)
     }
@@ -68,38 +85,152 @@ func (usr *JobRepository) FindJobsByRequisitionIDs(requisition_IDs []string) (ma
 	defer rows.Close()
 
 	for rows.Next(){
-		var requisitionID string
-		if err := rows.Scan(&requisitionID); err != nil {
+		var requisitionID, contentHash string
+		if err := rows.Scan(&requisitionID, &contentHash); err != nil {
 			return nil, fmt.Errorf("error scanning notified job requisition ID: %w", err)
 		}
-		Exists[requisitionID] = true
+		Exists[requisitionID] = contentHash
 	}
 
 	return Exists, rows.Err()
 }
 
-func (usr *JobRepository) UpdateLastSeen(requisition_ID string) (int, error) {
+// CountMissingJobs counts the site's open jobs whose requisition ID is not in
+// seenIDs, i.e. the ones a run no longer listed.
+func (usr *JobRepository) CountMissingJobs(siteID int, seenIDs []string) (int, error) {
+	query := `SELECT COUNT(*) FROM jobs WHERE site_id = $1 AND status = 'open' AND NOT (requisition_id = ANY($2))`
+
+	if seenIDs == nil {
+		seenIDs = []string{}
+	}
+	var missing int
+	if err := usr.connection.QueryRow(query, siteID, pq.Array(seenIDs)).Scan(&missing); err != nil {
+		return 0, fmt.Errorf("error counting missing jobs of site %d: %w", siteID, err)
+	}
+	return missing, nil
+}
+
+// FindKnownJobs lists the site's stored jobs with a link, for deciding which
+// detail pages a scrape can skip.
+func (usr *JobRepository) FindKnownJobs(siteID int) ([]model.KnownJob, error) {
+	query := `SELECT id, requisition_id, requisition_id_synthetic, title, job_link, details_fetched_at
+		FROM jobs WHERE site_id = $1 AND job_link <> ''`
+
+	rows, err := usr.connection.Query(query, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching known jobs of site %d: %w", siteID, err)
+	}
+	defer rows.Close()
+
+	var known []model.KnownJob
+	for rows.Next() {
+		var job model.KnownJob
+		if err := rows.Scan(&job.ID, &job.RequisitionID, &job.SyntheticID, &job.Title, &job.JobLink, &job.DetailsFetchedAt); err != nil {
+			return nil, fmt.Errorf("error scanning known job: %w", err)
+		}
+		known = append(known, job)
+	}
+	return known, rows.Err()
+}
+
+// MarkDetailsFetched records that the jobs' detail pages were just fetched.
+func (usr *JobRepository) MarkDetailsFetched(jobIDs []int) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+
+	_, err := usr.connection.Exec(`UPDATE jobs SET details_fetched_at = NOW() WHERE id = ANY($1)`, pq.Array(jobIDs))
+	if err != nil {
+		return fmt.Errorf("error marking details fetched: %w", err)
+	}
+	return nil
+}
+
+// UpdateLastSeen marks the job as seen in the current scrape, reopening it when it
+// had been closed or archived.
+func (usr *JobRepository) UpdateLastSeen(siteID int, requisition_ID string) (int, error) {
 	var id int
-	query := `UPDATE jobs SET last_seen_at = CURRENT_TIMESTAMP WHERE requisition_ID = $1 RETURNING id`
+	var previousStatus string
+	query := `WITH previous AS (
+			SELECT id, status FROM jobs WHERE site_id = $1 AND requisition_ID = $2 FOR UPDATE
+		)
+		UPDATE jobs j SET last_seen_at = CURRENT_TIMESTAMP, status = 'open', closed_at = NULL, archived_at = NULL
+		FROM previous
+		WHERE j.id = previous.id
+		RETURNING j.id, previous.status`
 	queryPrepare, err := usr.connection.Prepare(query)
 	if err != nil {
-		logging.Logger.Error().Err(err).Str("requisition_id", requisition_ID).Msg("error preparing query to update last seen")
+		logging.Logger.Error().Err(err).Int("site_id", siteID).Str("requisition_id", requisition_ID).Msg("error preparing query to update last seen")
 		return id, err
 	}
 	defer queryPrepare.Close()
 
-	err = queryPrepare.QueryRow(requisition_ID).Scan(&id)
+	err = queryPrepare.QueryRow(siteID, requisition_ID).Scan(&id, &previousStatus)
 	if err != nil {
-		logging.Logger.Error().Err(err).Str("requisition_id", requisition_ID).Msg("error updating last seen")
+		logging.Logger.Error().Err(err).Int("site_id", siteID).Str("requisition_id", requisition_ID).Msg("error updating last seen")
 		return id, err
 	}
 
-	logging.Logger.Info().Str("requisition_id", requisition_ID).Msg("last seen updated")
+	if previousStatus != model.JobStatusOpen {
+		logging.Logger.Info().Int("job_id", id).Str("previous_status", previousStatus).Msg("job reopened")
+	}
+	logging.Logger.Info().Int("site_id", siteID).Str("requisition_id", requisition_ID).Msg("last seen updated")
 	return id, nil
 }
 
+// UpdateJobContent replaces the job's title, location and description, and the
+// fields parsed from them, with the edited posting and records it as a new revision.
+func (usr *JobRepository) UpdateJobContent(jobID int, job model.Job) error {
+	query := `WITH updated AS (
+			UPDATE jobs SET title = $2, location = $3, description = $4, content_hash = $5, content_updated_at = NOW(),
+				location_city = NULLIF($6, ''), location_state = NULLIF($7, ''), location_country = NULLIF($8, ''), work_model = NULLIF($9, ''),
+				seniority = NULLIF($10, ''), role_family = NULLIF($11, ''), contract_type = NULLIF($12, ''),
+				salary_min = $13, salary_max = $14, salary_currency = NULLIF($15, ''), salary_period = NULLIF($16, ''),
+				salary_basis = NULLIF($17, ''), benefits = $18
+			WHERE id = $1
+			RETURNING id, title, location, description, content_hash
+		)
+		INSERT INTO job_revisions (job_id, title, location, description, content_hash)
+		SELECT id, title, location, description, content_hash FROM updated`
+
+	_, err := usr.connection.Exec(query, jobID, job.Title, job.Location, job.Description, job.ContentHash,
+		job.LocationCity, job.LocationState, job.LocationCountry, job.WorkModel, job.Seniority, job.RoleFamily, job.ContractType,
+		job.SalaryMin, job.SalaryMax, job.SalaryCurrency, job.SalaryPeriod, job.SalaryBasis, textArray(job.Benefits))
+	if err != nil {
+		return fmt.Errorf("error updating content of job %d: %w", jobID, err)
+	}
+	return nil
+}
+
+// GetJobRevisions returns the job's revisions, newest first.
+func (usr *JobRepository) GetJobRevisions(jobID int) ([]model.JobRevision, error) {
+	query := `SELECT id, job_id, title, location, COALESCE(description, ''), COALESCE(content_hash, ''), created_at
+		FROM job_revisions WHERE job_id = $1 ORDER BY created_at DESC, id DESC`
+
+	rows, err := usr.connection.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching revisions of job %d: %w", jobID, err)
+	}
+	defer rows.Close()
+
+	var revisions []model.JobRevision
+	for rows.Next() {
+		var rev model.JobRevision
+		if err := rows.Scan(&rev.ID, &rev.JobID, &rev.Title, &rev.Location, &rev.Description, &rev.ContentHash, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning job revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
 func (usr *JobRepository) GetJobByID(jobID int) (*model.Job, error) {
-	query := `SELECT id, site_id, title, location, company, job_link, requisition_id, description FROM jobs WHERE id = $1`
+	query := `SELECT id, site_id, title, location, company, job_link, requisition_id, requisition_id_synthetic, COALESCE(description, ''), COALESCE(content_hash, ''), content_updated_at, status, first_seen_at, closed_at,
+		COALESCE(employment_type, ''), date_posted, salary_min, salary_max, COALESCE(salary_currency, ''), COALESCE(salary_period, ''),
+		COALESCE(location_city, ''), COALESCE(location_state, ''), COALESCE(location_country, ''), COALESCE(work_model, ''),
+		COALESCE(seniority, ''), COALESCE(role_family, ''), COALESCE(contract_type, ''), COALESCE(salary_basis, ''), benefits,
+		COALESCE(company_key, ''), canonical_job_id
+		FROM jobs WHERE id = $1`
 
 	var job model.Job
 	err := usr.connection.QueryRow(query, jobID).Scan(
@@ -110,7 +241,30 @@ func (usr *JobRepository) GetJobByID(jobID int) (*model.Job, error) {
 		&job.Company,
 		&job.JobLink,
 		&job.RequisitionID,
+		&job.SyntheticID,
 		&job.Description,
+		&job.ContentHash,
+		&job.EditedAt,
+		&job.Status,
+		&job.FirstSeenAt,
+		&job.ClosedAt,
+		&job.EmploymentType,
+		&job.DatePosted,
+		&job.SalaryMin,
+		&job.SalaryMax,
+		&job.SalaryCurrency,
+		&job.SalaryPeriod,
+		&job.LocationCity,
+		&job.LocationState,
+		&job.LocationCountry,
+		&job.WorkModel,
+		&job.Seniority,
+		&job.RoleFamily,
+		&job.ContractType,
+		&job.SalaryBasis,
+		(*pq.StringArray)(&job.Benefits),
+		&job.CompanyKey,
+		&job.CanonicalJobID,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -121,19 +275,134 @@ func (usr *JobRepository) GetJobByID(jobID int) (*model.Job, error) {
 	return &job, nil
 }
 
-func (usr *JobRepository) DeleteOldJobs() error {
-	query := `DELETE FROM jobs WHERE last_seen_at < NOW() - INTERVAL '1 day'`
+// GetJobsToNormalize returns up to limit jobs with ID greater than afterID, in ID
+// order, with the text the normalizer reads.
+func (usr *JobRepository) GetJobsToNormalize(afterID int, limit int) ([]model.Job, error) {
+	query := `SELECT id, title, location, company, COALESCE(description, ''), COALESCE(employment_type, ''),
+		salary_min, salary_max, COALESCE(salary_currency, ''), COALESCE(salary_period, ''), benefits
+		FROM jobs WHERE id > $1 ORDER BY id LIMIT $2`
+
+	rows, err := usr.connection.Query(query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching jobs to normalize: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []model.Job
+	for rows.Next() {
+		var job model.Job
+		if err := rows.Scan(&job.ID, &job.Title, &job.Location, &job.Company, &job.Description, &job.EmploymentType,
+			&job.SalaryMin, &job.SalaryMax, &job.SalaryCurrency, &job.SalaryPeriod, (*pq.StringArray)(&job.Benefits)); err != nil {
+			return nil, fmt.Errorf("error scanning job to normalize: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// UpdateNormalizedFields stores the fields the normalizer parsed for the job.
+func (usr *JobRepository) UpdateNormalizedFields(job model.Job) error {
+	query := `UPDATE jobs SET location_city = NULLIF($2, ''), location_state = NULLIF($3, ''), location_country = NULLIF($4, ''), work_model = NULLIF($5, ''),
+		seniority = NULLIF($6, ''), role_family = NULLIF($7, ''), contract_type = NULLIF($8, ''),
+		salary_min = $9, salary_max = $10, salary_currency = NULLIF($11, ''), salary_period = NULLIF($12, ''),
+		salary_basis = NULLIF($13, ''), benefits = $14, company_key = NULLIF($15, '')
+		WHERE id = $1`
 
-	result, err := usr.connection.Exec(query)
+	_, err := usr.connection.Exec(query, job.ID, job.LocationCity, job.LocationState, job.LocationCountry, job.WorkModel,
+		job.Seniority, job.RoleFamily, job.ContractType,
+		job.SalaryMin, job.SalaryMax, job.SalaryCurrency, job.SalaryPeriod, job.SalaryBasis, textArray(job.Benefits), job.CompanyKey)
 	if err != nil {
-		return fmt.Errorf("error deleting old jobs: %w", err)
+		return fmt.Errorf("error updating normalized fields of job %d: %w", job.ID, err)
 	}
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+// FindDuplicateCandidates returns the open jobs of the same company found on other
+// sites, oldest first, with the fields the duplicate detection compares.
+func (usr *JobRepository) FindDuplicateCandidates(siteID int, companyKey string) ([]model.Job, error) {
+	query := `SELECT id, site_id, title, COALESCE(description, ''), COALESCE(location_city, ''), COALESCE(location_state, ''),
+		company_key, canonical_job_id
+		FROM jobs WHERE company_key = $1 AND site_id <> $2 AND status = 'open' ORDER BY id`
+
+	rows, err := usr.connection.Query(query, companyKey, siteID)
 	if err != nil {
-		return fmt.Errorf("error getting rows affected: %w", err)
+		return nil, fmt.Errorf("error fetching duplicate candidates for site %d: %w", siteID, err)
 	}
+	defer rows.Close()
 
-	logging.Logger.Info().Int64("rows_affected", rowsAffected).Msg("deleted old jobs")
+	var jobs []model.Job
+	for rows.Next() {
+		var job model.Job
+		if err := rows.Scan(&job.ID, &job.SiteID, &job.Title, &job.Description, &job.LocationCity, &job.LocationState,
+			&job.CompanyKey, &job.CanonicalJobID); err != nil {
+			return nil, fmt.Errorf("error scanning duplicate candidate: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// SetCanonicalJob adds the job to the cluster of canonicalJobID.
+func (usr *JobRepository) SetCanonicalJob(jobID int, canonicalJobID int) error {
+	_, err := usr.connection.Exec(`UPDATE jobs SET canonical_job_id = $2 WHERE id = $1`, jobID, canonicalJobID)
+	if err != nil {
+		return fmt.Errorf("error setting canonical job of job %d: %w", jobID, err)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// UpdateJobLifecycle closes open jobs not seen within policy.CloseAfter by a
+// successful scrape of their active site, archives jobs closed for longer than
+// policy.ArchiveAfter and deletes archived jobs older than policy.PurgeAfter.
+// Jobs a user applied to or has an analysis of are never deleted, since deleting
+// a job cascades to its applications.
+func (usr *JobRepository) UpdateJobLifecycle(policy model.JobLifecyclePolicy) (model.JobLifecycleResult, error) {
+	var result model.JobLifecycleResult
+
+	tx, err := usr.connection.Begin()
+	if err != nil {
+		return result, fmt.Errorf("error starting job lifecycle transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A job is only missing if its site was scraped successfully after the grace
+	// period; a site that keeps failing, or was disabled, closes nothing.
+	closeQuery := `UPDATE jobs j SET status = 'closed', closed_at = NOW()
+		FROM site_scraping_config s
+		WHERE j.site_id = s.id AND s.is_active = TRUE
+		AND j.status = 'open' AND j.last_seen_at < NOW() - make_interval(secs => $1)
+		AND EXISTS (SELECT 1 FROM scrape_runs r WHERE r.site_id = j.site_id AND r.error_class IS NULL
+			AND r.started_at > j.last_seen_at + make_interval(secs => $1))`
+	if result.Closed, err = execRowsAffected(tx, closeQuery, policy.CloseAfter.Seconds()); err != nil {
+		return result, fmt.Errorf("error closing unseen jobs: %w", err)
+	}
+
+	archiveQuery := `UPDATE jobs SET status = 'archived', archived_at = NOW()
+		WHERE status = 'closed' AND closed_at < NOW() - make_interval(secs => $1)`
+	if result.Archived, err = execRowsAffected(tx, archiveQuery, policy.ArchiveAfter.Seconds()); err != nil {
+		return result, fmt.Errorf("error archiving closed jobs: %w", err)
+	}
+
+	purgeQuery := `DELETE FROM jobs j
+		WHERE j.status = 'archived' AND j.archived_at < NOW() - make_interval(secs => $1)
+		AND NOT EXISTS (SELECT 1 FROM job_applications ja WHERE ja.job_id = j.id)
+		AND NOT EXISTS (SELECT 1 FROM job_notifications jn WHERE jn.job_id = j.id AND jn.analysis_result IS NOT NULL)`
+	if result.Purged, err = execRowsAffected(tx, purgeQuery, policy.PurgeAfter.Seconds()); err != nil {
+		return result, fmt.Errorf("error purging archived jobs: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("error committing job lifecycle: %w", err)
+	}
+
+	logging.Logger.Info().Int64("closed", result.Closed).Int64("archived", result.Archived).Int64("purged", result.Purged).Msg("job lifecycle updated")
+	return result, nil
+}
+
+func execRowsAffected(tx *sql.Tx, query string, args ...any) (int64, error) {
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
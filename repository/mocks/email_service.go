@@ -30,3 +30,8 @@ func (m *MockEmailService) SendPasswordResetEmail(ctx context.Context, email, us
 	args := m.Called(ctx, email, userName, resetLink)
 	return args.Error(0)
 }
+
+func (m *MockEmailService) SendSiteAnomalyEmail(ctx context.Context, adminEmail string, anomaly model.SiteAnomaly) error {
+	args := m.Called(ctx, adminEmail, anomaly)
+	return args.Error(0)
+}
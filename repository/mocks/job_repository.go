@@ -15,26 +15,57 @@ func (m *MockJobRepository) CreateJob(job model.Job) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockJobRepository) FindJobByRequisitionID(requisition_ID string) (bool, error) {
-	args := m.Called(requisition_ID)
+func (m *MockJobRepository) FindJobByRequisitionID(siteID int, requisition_ID string) (bool, error) {
+	args := m.Called(siteID, requisition_ID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockJobRepository) FindJobsByRequisitionIDs(requisition_IDs []string) (map[string]bool, error) {
-	args := m.Called(requisition_IDs)
-	return args.Get(0).(map[string]bool), args.Error(1)
+func (m *MockJobRepository) FindJobsByRequisitionIDs(siteID int, requisition_IDs []string) (map[string]string, error) {
+	args := m.Called(siteID, requisition_IDs)
+	return args.Get(0).(map[string]string), args.Error(1)
 }
 
-func (m *MockJobRepository) UpdateLastSeen(requisition_ID string) (int, error) {
-	args := m.Called(requisition_ID)
+func (m *MockJobRepository) FindKnownJobs(siteID int) ([]model.KnownJob, error) {
+	args := m.Called(siteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KnownJob), args.Error(1)
+}
+
+func (m *MockJobRepository) CountMissingJobs(siteID int, seenIDs []string) (int, error) {
+	args := m.Called(siteID, seenIDs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockJobRepository) MarkDetailsFetched(jobIDs []int) error {
+	args := m.Called(jobIDs)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateLastSeen(siteID int, requisition_ID string) (int, error) {
+	args := m.Called(siteID, requisition_ID)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockJobRepository) DeleteOldJobs() error {
-	args := m.Called()
+func (m *MockJobRepository) UpdateJobContent(jobID int, job model.Job) error {
+	args := m.Called(jobID, job)
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) GetJobRevisions(jobID int) ([]model.JobRevision, error) {
+	args := m.Called(jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.JobRevision), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdateJobLifecycle(policy model.JobLifecyclePolicy) (model.JobLifecycleResult, error) {
+	args := m.Called(policy)
+	return args.Get(0).(model.JobLifecycleResult), args.Error(1)
+}
+
 func (m *MockJobRepository) GetJobByID(jobID int) (*model.Job, error) {
 	args := m.Called(jobID)
 	if args.Get(0) == nil {
@@ -42,3 +73,16 @@ func (m *MockJobRepository) GetJobByID(jobID int) (*model.Job, error) {
 	}
 	return args.Get(0).(*model.Job), args.Error(1)
 }
+
+func (m *MockJobRepository) FindDuplicateCandidates(siteID int, companyKey string) ([]model.Job, error) {
+	args := m.Called(siteID, companyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) SetCanonicalJob(jobID int, canonicalJobID int) error {
+	args := m.Called(jobID, canonicalJobID)
+	return args.Error(0)
+}
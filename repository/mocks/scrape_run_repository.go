@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScrapeRunRepository struct {
+	mock.Mock
+}
+
+func (m *MockScrapeRunRepository) RecordRun(run model.ScrapeRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockScrapeRunRepository) GetSiteRuns(siteID int, limit int) ([]model.ScrapeRun, error) {
+	args := m.Called(siteID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ScrapeRun), args.Error(1)
+}
+
+func (m *MockScrapeRunRepository) GetRecentRunsBySite(limit int) ([]model.SiteRunHistory, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SiteRunHistory), args.Error(1)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"time"
 	"web-scrapper/model"
 
 	"github.com/stretchr/testify/mock"
@@ -10,8 +11,8 @@ type MockSiteCareerRepository struct {
 	mock.Mock
 }
 
-func (m *MockSiteCareerRepository) InsertNewSiteCareer(site model.SiteScrapingConfig) (model.SiteScrapingConfig, error) {
-	args := m.Called(site)
+func (m *MockSiteCareerRepository) InsertNewSiteCareer(site model.SiteScrapingConfig, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error) {
+	args := m.Called(site, author)
 	return args.Get(0).(model.SiteScrapingConfig), args.Error(1)
 }
 
@@ -19,3 +20,61 @@ func (m *MockSiteCareerRepository) GetAllSites() ([]model.SiteScrapingConfig, er
 	args := m.Called()
 	return args.Get(0).([]model.SiteScrapingConfig), args.Error(1)
 }
+
+func (m *MockSiteCareerRepository) GetSiteByID(siteID int) (model.SiteScrapingConfig, error) {
+	args := m.Called(siteID)
+	return args.Get(0).(model.SiteScrapingConfig), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) UpdateSiteCareer(site model.SiteScrapingConfig, changeType string, note string, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error) {
+	args := m.Called(site, changeType, note, author)
+	return args.Get(0).(model.SiteScrapingConfig), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) SetSiteActive(siteID int, active bool, note string, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error) {
+	args := m.Called(siteID, active, note, author)
+	return args.Get(0).(model.SiteScrapingConfig), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) DisableSite(siteID int, reason string) (bool, error) {
+	args := m.Called(siteID, reason)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) DeleteSite(siteID int, author model.SiteConfigAuthor) error {
+	args := m.Called(siteID, author)
+	return args.Error(0)
+}
+
+func (m *MockSiteCareerRepository) GetSiteVersions(siteID int) ([]model.SiteConfigVersion, error) {
+	args := m.Called(siteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SiteConfigVersion), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) GetSiteVersion(siteID int, version int) (model.SiteConfigVersion, error) {
+	args := m.Called(siteID, version)
+	return args.Get(0).(model.SiteConfigVersion), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) GetScheduledSites() ([]model.ScheduledSite, error) {
+	args := m.Called()
+	return args.Get(0).([]model.ScheduledSite), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) MarkSiteScheduled(siteID int, at time.Time) error {
+	args := m.Called(siteID, at)
+	return args.Error(0)
+}
+
+func (m *MockSiteCareerRepository) GetSitePostingStats(since time.Time) ([]model.SitePostingStats, error) {
+	args := m.Called(since)
+	return args.Get(0).([]model.SitePostingStats), args.Error(1)
+}
+
+func (m *MockSiteCareerRepository) SetAdaptiveInterval(siteID int, minutes int) error {
+	args := m.Called(siteID, minutes)
+	return args.Error(0)
+}
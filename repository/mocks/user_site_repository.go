@@ -15,8 +15,8 @@ func (m *MockUserSiteRepository) GetUsersBySiteId(siteId int) ([]model.UserSiteC
 	return args.Get(0).([]model.UserSiteCurriculum), args.Error(1)
 }
 
-func (m *MockUserSiteRepository) InsertNewUserSite(userId int, siteId int, filters []string) error {
-	args := m.Called(userId, siteId, filters)
+func (m *MockUserSiteRepository) InsertNewUserSite(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error {
+	args := m.Called(userId, siteId, filters, classFilters)
 	return args.Error(0)
 }
 
@@ -30,8 +30,8 @@ func (m *MockUserSiteRepository) DeleteUserSite(userId int, siteId string) error
 	return args.Error(0)
 }
 
-func (m *MockUserSiteRepository) UpdateUserSiteFilters(userId int, siteId int, filters []string) error {
-	args := m.Called(userId, siteId, filters)
+func (m *MockUserSiteRepository) UpdateUserSiteFilters(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error {
+	args := m.Called(userId, siteId, filters, classFilters)
 	return args.Error(0)
 }
 
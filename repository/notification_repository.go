@@ -117,14 +117,18 @@ func (db *NotificationRepository) GetNotificationsByUser(userId int, limit int)
 
 func (db *NotificationRepository) GetUnnotifiedJobsForUser(userID int) ([]model.JobWithFilters, error) {
 	query := `
-		SELECT j.id, j.title, j.location, j.company, j.job_link, us.filters
+		SELECT j.id, j.title, j.location, j.company, j.job_link, us.filters,
+			COALESCE(j.seniority, ''), COALESCE(j.role_family, ''), COALESCE(j.contract_type, ''),
+			us.seniorities, us.role_families, us.contract_types, COALESCE(j.canonical_job_id, j.id)
 		FROM jobs j
 		INNER JOIN user_sites us ON j.site_id = us.site_id AND us.user_id = $1
-		WHERE j.last_seen_at >= NOW() - INTERVAL '24 hours'
+		WHERE j.status = 'open' AND j.last_seen_at >= NOW() - INTERVAL '24 hours'
 		  AND NOT EXISTS (
 			  SELECT 1 FROM job_notifications jn
-			  WHERE jn.user_id = $1 AND jn.job_id = j.id
-		  )`
+			  INNER JOIN jobs nj ON nj.id = jn.job_id
+			  WHERE jn.user_id = $1 AND COALESCE(nj.canonical_job_id, nj.id) = COALESCE(j.canonical_job_id, j.id)
+		  )
+		ORDER BY j.id`
 
 	rows, err := db.connection.Query(query, userID)
 	if err != nil {
@@ -136,7 +140,10 @@ func (db *NotificationRepository) GetUnnotifiedJobsForUser(userID int) ([]model.
 	for rows.Next() {
 		var j model.JobWithFilters
 		var filtersJSON sql.NullString
-		if err := rows.Scan(&j.JobID, &j.Title, &j.Location, &j.Company, &j.JobLink, &filtersJSON); err != nil {
+		if err := rows.Scan(&j.JobID, &j.Title, &j.Location, &j.Company, &j.JobLink, &filtersJSON,
+			&j.Seniority, &j.RoleFamily, &j.ContractType,
+			pq.Array(&j.ClassFilters.Seniorities), pq.Array(&j.ClassFilters.RoleFamilies), pq.Array(&j.ClassFilters.ContractTypes),
+			&j.ClusterID); err != nil {
 			return nil, fmt.Errorf("error scanning job with filters: %w", err)
 		}
 		if filtersJSON.Valid {
@@ -186,7 +193,13 @@ func (db *NotificationRepository) GetUserIDsWithPendingNotifications() ([]int, e
 func (db *NotificationRepository) GetPendingJobsForUser(userID int) ([]model.NotificationWithJob, error) {
 	query := `
 		SELECT jn.id, jn.job_id, jn.user_id, jn.notified_at,
-			   j.title, j.company, j.location, j.job_link
+			   j.title, j.company, j.location, j.job_link, COALESCE(j.canonical_job_id, j.id),
+			   ARRAY(
+				   SELECT d.job_link FROM jobs d
+				   WHERE (d.id = COALESCE(j.canonical_job_id, j.id) OR d.canonical_job_id = COALESCE(j.canonical_job_id, j.id))
+				     AND d.id <> j.id AND d.status = 'open'
+				   ORDER BY d.id
+			   )
 		FROM job_notifications jn
 		INNER JOIN jobs j ON jn.job_id = j.id
 		WHERE jn.user_id = $1 AND jn.status = 'PENDING'
@@ -202,7 +215,7 @@ func (db *NotificationRepository) GetPendingJobsForUser(userID int) ([]model.Not
 	for rows.Next() {
 		var n model.NotificationWithJob
 		if err := rows.Scan(&n.ID, &n.JobID, &n.UserID, &n.NotifiedAt,
-			&n.JobTitle, &n.JobCompany, &n.JobLocation, &n.JobLink); err != nil {
+			&n.JobTitle, &n.JobCompany, &n.JobLocation, &n.JobLink, &n.ClusterID, pq.Array(&n.AlternateLinks)); err != nil {
 			return nil, fmt.Errorf("error scanning pending notification: %w", err)
 		}
 		notifications = append(notifications, n)
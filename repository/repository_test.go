@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 	"web-scrapper/model"
 )
 
@@ -78,4 +79,64 @@ func TestNotification(t *testing.T){
 	if newJobID == 0 {
 		t.Fatal("the job ID didnt return")
 	}
-}
\ No newline at end of file
+}
+func TestJobLifecycle(t *testing.T) {
+	var siteID int
+	err := testDB.QueryRow(`INSERT INTO site_scraping_config (site_name, base_url, scraping_type) VALUES ('Lifecycle', 'https://lifecycle.example', 'HTML') RETURNING id`).Scan(&siteID)
+	if err != nil {
+		t.Fatalf("error to insert site: %s", err)
+	}
+
+	jobRepository := NewJobRepository(testDB)
+	jobID, err := jobRepository.CreateJob(model.Job{Title: "Go Developer", Company: "Lifecycle", JobLink: "https://lifecycle.example/1", RequisitionID: "lc-1", SiteID: siteID})
+	if err != nil {
+		t.Fatalf("error to create job: %s", err)
+	}
+
+	if _, err := testDB.Exec(`UPDATE jobs SET last_seen_at = NOW() - INTERVAL '4 days' WHERE id = $1`, jobID); err != nil {
+		t.Fatalf("error to age job: %s", err)
+	}
+
+	policy := model.JobLifecyclePolicy{CloseAfter: 72 * time.Hour, ArchiveAfter: 30 * 24 * time.Hour, PurgeAfter: 365 * 24 * time.Hour}
+	result, err := jobRepository.UpdateJobLifecycle(policy)
+	if err != nil {
+		t.Fatalf("error to update lifecycle: %s", err)
+	}
+	if job, _ := jobRepository.GetJobByID(jobID); job.Status != model.JobStatusOpen {
+		t.Fatalf("job should stay open while its site has no successful scrape, got %+v", result)
+	}
+
+	if _, err := testDB.Exec(`INSERT INTO scrape_runs (site_id, site_name, error_class, strategy, started_at) VALUES ($1, 'Lifecycle', 'network', 'CSS', NOW())`, siteID); err != nil {
+		t.Fatalf("error to insert failed run: %s", err)
+	}
+	if result, err = jobRepository.UpdateJobLifecycle(policy); err != nil || result.Closed != 0 {
+		t.Fatalf("failed runs must not close jobs, got %+v, %v", result, err)
+	}
+
+	if _, err := testDB.Exec(`INSERT INTO scrape_runs (site_id, site_name, strategy, started_at) VALUES ($1, 'Lifecycle', 'CSS', NOW())`, siteID); err != nil {
+		t.Fatalf("error to insert successful run: %s", err)
+	}
+	result, err = jobRepository.UpdateJobLifecycle(policy)
+	if err != nil {
+		t.Fatalf("error to update lifecycle: %s", err)
+	}
+	if result.Closed < 1 {
+		t.Fatalf("expected the unseen job to be closed, got %+v", result)
+	}
+
+	job, err := jobRepository.GetJobByID(jobID)
+	if err != nil || job == nil {
+		t.Fatalf("closed job should still exist: %v", err)
+	}
+	if job.Status != model.JobStatusClosed || job.ClosedAt == nil {
+		t.Fatalf("expected closed job with closed_at, got status %q", job.Status)
+	}
+
+	if _, err := jobRepository.UpdateLastSeen(siteID, "lc-1"); err != nil {
+		t.Fatalf("error to update last seen: %s", err)
+	}
+	job, _ = jobRepository.GetJobByID(jobID)
+	if job.Status != model.JobStatusOpen || job.ClosedAt != nil {
+		t.Fatalf("expected job to be reopened, got status %q", job.Status)
+	}
+}
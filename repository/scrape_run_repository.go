@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"web-scrapper/model"
+)
+
+type ScrapeRunRepository struct {
+	connection *sql.DB
+}
+
+func NewScrapeRunRepository(db *sql.DB) *ScrapeRunRepository {
+	return &ScrapeRunRepository{
+		connection: db,
+	}
+}
+
+const scrapeRunColumns = `r.id, r.site_id, r.site_name, COALESCE(r.task_id, ''), r.strategy, r.started_at, r.duration_ms,
+	r.listed_count, r.new_count, r.updated_count, r.missing_count, r.detail_failures, r.empty_title_count,
+	COALESCE(r.error_class, ''), COALESCE(r.error_message, '')`
+
+// scrapeRunColumnsNullable are the run columns of a LEFT JOIN, where every one may be NULL.
+const scrapeRunColumnsNullable = `r.id, r.site_id, r.site_name, r.task_id, r.strategy, r.started_at, r.duration_ms,
+	r.listed_count, r.new_count, r.updated_count, r.missing_count, r.detail_failures, r.empty_title_count,
+	r.error_class, r.error_message`
+
+func (r *ScrapeRunRepository) RecordRun(run model.ScrapeRun) error {
+	query := `INSERT INTO scrape_runs (site_id, site_name, task_id, strategy, started_at, duration_ms,
+		listed_count, new_count, updated_count, missing_count, detail_failures, empty_title_count, error_class, error_message)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), NULLIF($14, ''))`
+	_, err := r.connection.Exec(query, run.SiteID, run.SiteName, run.TaskID, run.Strategy, run.StartedAt, run.DurationMs,
+		run.Listed, run.New, run.Updated, run.Missing, run.DetailFailures, run.EmptyTitles, run.ErrorClass, run.ErrorMessage)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar execução de scraping do site %d: %w", run.SiteID, err)
+	}
+	return nil
+}
+
+// GetSiteRuns lists the site's latest runs, newest first.
+func (r *ScrapeRunRepository) GetSiteRuns(siteID int, limit int) ([]model.ScrapeRun, error) {
+	query := `SELECT ` + scrapeRunColumns + ` FROM scrape_runs r WHERE r.site_id = $1 ORDER BY r.started_at DESC LIMIT $2`
+
+	rows, err := r.connection.Query(query, siteID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar execuções de scraping do site %d: %w", siteID, err)
+	}
+	defer rows.Close()
+
+	runs := []model.ScrapeRun{}
+	for rows.Next() {
+		run, err := scanScrapeRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// GetRecentRunsBySite returns every configured site with up to limit of its latest
+// runs, newest first; sites never scraped come with no runs.
+func (r *ScrapeRunRepository) GetRecentRunsBySite(limit int) ([]model.SiteRunHistory, error) {
+	query := `
+		SELECT sc.id, sc.site_name, sc.is_active, COALESCE(sc.disabled_reason, ''), r.id IS NOT NULL, ` + scrapeRunColumnsNullable + `
+		FROM site_scraping_config sc
+		LEFT JOIN LATERAL (
+			SELECT * FROM scrape_runs
+			WHERE site_id = sc.id
+			ORDER BY started_at DESC
+			LIMIT $1
+		) r ON TRUE
+		ORDER BY sc.id, r.started_at DESC`
+
+	rows, err := r.connection.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar histórico de scraping dos sites: %w", err)
+	}
+	defer rows.Close()
+
+	var histories []model.SiteRunHistory
+	for rows.Next() {
+		var site model.SiteRunHistory
+		var hasRun bool
+		var runID, siteID, durationMs, listed, created, updated, missing, detailFailures, emptyTitles sql.NullInt64
+		var siteName, taskID, strategy, errorClass, errorMessage sql.NullString
+		var startedAt sql.NullTime
+		err := rows.Scan(&site.SiteID, &site.SiteName, &site.IsActive, &site.DisabledReason, &hasRun,
+			&runID, &siteID, &siteName, &taskID, &strategy, &startedAt, &durationMs,
+			&listed, &created, &updated, &missing, &detailFailures, &emptyTitles, &errorClass, &errorMessage)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler histórico de scraping: %w", err)
+		}
+
+		if n := len(histories); n == 0 || histories[n-1].SiteID != site.SiteID {
+			histories = append(histories, site)
+		}
+		if !hasRun {
+			continue
+		}
+		run := model.ScrapeRun{
+			ID:           int(runID.Int64),
+			SiteID:       int(siteID.Int64),
+			SiteName:     siteName.String,
+			TaskID:       taskID.String,
+			Strategy:     strategy.String,
+			StartedAt:    startedAt.Time,
+			DurationMs:   durationMs.Int64,
+			ErrorClass:   errorClass.String,
+			ErrorMessage: errorMessage.String,
+			ScrapeCounts: model.ScrapeCounts{
+				Listed:         int(listed.Int64),
+				New:            int(created.Int64),
+				Updated:        int(updated.Int64),
+				Missing:        int(missing.Int64),
+				DetailFailures: int(detailFailures.Int64),
+				EmptyTitles:    int(emptyTitles.Int64),
+			},
+		}
+		last := &histories[len(histories)-1]
+		last.Runs = append(last.Runs, run)
+	}
+	return histories, rows.Err()
+}
+
+func scanScrapeRun(rows *sql.Rows) (model.ScrapeRun, error) {
+	var run model.ScrapeRun
+	err := rows.Scan(&run.ID, &run.SiteID, &run.SiteName, &run.TaskID, &run.Strategy, &run.StartedAt, &run.DurationMs,
+		&run.Listed, &run.New, &run.Updated, &run.Missing, &run.DetailFailures, &run.EmptyTitles, &run.ErrorClass, &run.ErrorMessage)
+	if err != nil {
+		return run, fmt.Errorf("erro ao ler execução de scraping: %w", err)
+	}
+	return run, nil
+}
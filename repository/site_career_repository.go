@@ -2,6 +2,9 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 	"web-scrapper/model"
 	"fmt"
 )
@@ -16,7 +19,36 @@ func NewSiteCareerRepository(db *sql.DB) *SiteCareerRepository{
 	}
 }
 
-func (st *SiteCareerRepository) InsertNewSiteCareer(site model.SiteScrapingConfig) (model.SiteScrapingConfig, error){
+// siteConfigColumns are the config columns, in the order scanSiteConfig reads them.
+const siteConfigColumns = `id, site_name, base_url, is_active, scraping_type,
+            job_list_item_selector, title_selector, link_selector, link_attribute,
+            location_selector, next_page_selector, job_description_selector, job_requisition_id_selector,
+            api_endpoint_template, api_method, api_headers_json, api_payload_template, json_data_mappings, logo_url, ats_slug, url_pattern, max_age_days, headless_actions, max_pages, page_url_template,
+            schedule_cron, schedule_interval_minutes, schedule_window_start, schedule_window_end, schedule_timezone,
+            schedule_min_interval_minutes, schedule_max_interval_minutes, COALESCE(disabled_reason, '')`
+
+// rowScanner is a *sql.Row or *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSiteConfig reads the config columns, then any extra columns selected after them.
+func scanSiteConfig(row rowScanner, extra ...interface{}) (model.SiteScrapingConfig, error) {
+	var site model.SiteScrapingConfig
+	dest := []interface{}{
+		&site.ID, &site.SiteName, &site.BaseURL, &site.IsActive, &site.ScrapingType,
+		&site.JobListItemSelector, &site.TitleSelector, &site.LinkSelector, &site.LinkAttribute,
+		&site.LocationSelector, &site.NextPageSelector, &site.JobDescriptionSelector, &site.JobRequisitionIdSelector,
+		&site.APIEndpointTemplate, &site.APIMethod, &site.APIHeadersJSON, &site.APIPayloadTemplate, &site.JSONDataMappings, &site.LogoURL, &site.ATSSlug, &site.URLPattern, &site.MaxAgeDays, &site.HeadlessActions, &site.MaxPages, &site.PageURLTemplate,
+		&site.ScheduleCron, &site.ScheduleIntervalMinutes, &site.ScheduleWindowStart, &site.ScheduleWindowEnd, &site.ScheduleTimezone,
+		&site.ScheduleMinIntervalMinutes, &site.ScheduleMaxIntervalMinutes, &site.DisabledReason,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return site, err
+}
+
+// InsertNewSiteCareer creates the site and its first config version.
+func (st *SiteCareerRepository) InsertNewSiteCareer(site model.SiteScrapingConfig, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error){
 	nilReturn := model.SiteScrapingConfig{}
 
 	query := `
@@ -24,30 +56,29 @@ func (st *SiteCareerRepository) InsertNewSiteCareer(site model.SiteScrapingConfi
             site_name, base_url, is_active, scraping_type,
             job_list_item_selector, title_selector, link_selector, link_attribute,
             location_selector, next_page_selector, job_description_selector, job_requisition_id_selector,
-            api_endpoint_template, api_method, api_headers_json, api_payload_template, json_data_mappings, logo_url 
+            api_endpoint_template, api_method, api_headers_json, api_payload_template, json_data_mappings, logo_url, ats_slug, url_pattern, max_age_days, headless_actions, max_pages, page_url_template,
+            schedule_cron, schedule_interval_minutes, schedule_window_start, schedule_window_end, schedule_timezone,
+            schedule_min_interval_minutes, schedule_max_interval_minutes
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
-        ) RETURNING 
-            id, site_name, base_url, is_active, scraping_type,
-            job_list_item_selector, title_selector, link_selector, link_attribute,
-            location_selector, next_page_selector, job_description_selector, job_requisition_id_selector,
-            api_endpoint_template, api_method, api_headers_json, api_payload_template, json_data_mappings, logo_url
-    `
-	var siteCreated model.SiteScrapingConfig
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24,
+            $25, $26, $27, $28, $29, $30, $31
+        ) RETURNING ` + siteConfigColumns
 
+	tx, err := st.connection.Begin()
+	if err != nil {
+		return nilReturn, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	err := st.connection.QueryRow(
+	siteCreated, err := scanSiteConfig(tx.QueryRow(
 		query,
 		site.SiteName, site.BaseURL, site.IsActive, site.ScrapingType,
 		site.JobListItemSelector, site.TitleSelector, site.LinkSelector, site.LinkAttribute,
 		site.LocationSelector, site.NextPageSelector, site.JobDescriptionSelector, site.JobRequisitionIdSelector,
-		site.APIEndpointTemplate, site.APIMethod, site.APIHeadersJSON, site.APIPayloadTemplate, site.JSONDataMappings, site.LogoURL,
-	).Scan(
-		&siteCreated.ID, &siteCreated.SiteName, &siteCreated.BaseURL, &siteCreated.IsActive, &siteCreated.ScrapingType,
-		&siteCreated.JobListItemSelector, &siteCreated.TitleSelector, &siteCreated.LinkSelector, &siteCreated.LinkAttribute,
-		&siteCreated.LocationSelector, &siteCreated.NextPageSelector, &siteCreated.JobDescriptionSelector, &siteCreated.JobRequisitionIdSelector,
-		&siteCreated.APIEndpointTemplate, &siteCreated.APIMethod, &siteCreated.APIHeadersJSON, &siteCreated.APIPayloadTemplate, &siteCreated.JSONDataMappings, &siteCreated.LogoURL,
-	)
+		site.APIEndpointTemplate, site.APIMethod, site.APIHeadersJSON, site.APIPayloadTemplate, site.JSONDataMappings, site.LogoURL, site.ATSSlug, site.URLPattern, site.MaxAgeDays, site.HeadlessActions, site.MaxPages, site.PageURLTemplate,
+		site.ScheduleCron, site.ScheduleIntervalMinutes, site.ScheduleWindowStart, site.ScheduleWindowEnd, site.ScheduleTimezone,
+		site.ScheduleMinIntervalMinutes, site.ScheduleMaxIntervalMinutes,
+	))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -56,6 +87,13 @@ func (st *SiteCareerRepository) InsertNewSiteCareer(site model.SiteScrapingConfi
 		return nilReturn, err
 	}
 
+	if err := recordConfigVersion(tx, siteCreated, model.SiteConfigCreated, "", author); err != nil {
+		return nilReturn, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nilReturn, fmt.Errorf("error committing site creation: %w", err)
+	}
+
 	return siteCreated, nil
 }
 
@@ -65,7 +103,9 @@ func (st *SiteCareerRepository) GetAllSites() ([]model.SiteScrapingConfig, error
 	query := `SELECT id, site_name, base_url, is_active, scraping_type,
 		job_list_item_selector, title_selector, link_selector, link_attribute,
 		location_selector, next_page_selector, job_description_selector, job_requisition_id_selector,
-		api_endpoint_template, api_method, api_headers_json, api_payload_template, json_data_mappings, logo_url
+		api_endpoint_template, api_method, api_headers_json, api_payload_template, json_data_mappings, logo_url, ats_slug, url_pattern, max_age_days, headless_actions, max_pages, page_url_template,
+		schedule_cron, schedule_interval_minutes, schedule_window_start, schedule_window_end, schedule_timezone,
+		schedule_min_interval_minutes, schedule_max_interval_minutes
 		FROM site_scraping_config WHERE is_active = TRUE`
 	rows, err := st.connection.Query(query)
 
@@ -97,6 +137,19 @@ func (st *SiteCareerRepository) GetAllSites() ([]model.SiteScrapingConfig, error
 			&site.APIPayloadTemplate,
 			&site.JSONDataMappings,
 			&site.LogoURL,
+			&site.ATSSlug,
+			&site.URLPattern,
+			&site.MaxAgeDays,
+			&site.HeadlessActions,
+			&site.MaxPages,
+			&site.PageURLTemplate,
+			&site.ScheduleCron,
+			&site.ScheduleIntervalMinutes,
+			&site.ScheduleWindowStart,
+			&site.ScheduleWindowEnd,
+			&site.ScheduleTimezone,
+			&site.ScheduleMinIntervalMinutes,
+			&site.ScheduleMaxIntervalMinutes,
 		)
 
 		if err != nil {
@@ -111,4 +164,277 @@ func (st *SiteCareerRepository) GetAllSites() ([]model.SiteScrapingConfig, error
 	}
 
 	return listOfSites, nil
-}
\ No newline at end of file
+}
+func (st *SiteCareerRepository) GetSiteByID(siteID int) (model.SiteScrapingConfig, error) {
+	query := `SELECT ` + siteConfigColumns + ` FROM site_scraping_config WHERE id = $1`
+
+	site, err := scanSiteConfig(st.connection.QueryRow(query, siteID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.SiteScrapingConfig{}, fmt.Errorf("site %d: %w", siteID, model.ErrSiteNotFound)
+		}
+		return model.SiteScrapingConfig{}, fmt.Errorf("error fetching site %d: %w", siteID, err)
+	}
+	return site, nil
+}
+
+// UpdateSiteCareer replaces the config of a site, except whether it is active, and
+// records the result as a new version of the given change type.
+func (st *SiteCareerRepository) UpdateSiteCareer(site model.SiteScrapingConfig, changeType string, note string, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error) {
+	query := `
+        UPDATE site_scraping_config SET
+            site_name = $2, base_url = $3, scraping_type = $4,
+            job_list_item_selector = $5, title_selector = $6, link_selector = $7, link_attribute = $8,
+            location_selector = $9, next_page_selector = $10, job_description_selector = $11, job_requisition_id_selector = $12,
+            api_endpoint_template = $13, api_method = $14, api_headers_json = $15, api_payload_template = $16, json_data_mappings = $17,
+            logo_url = $18, ats_slug = $19, url_pattern = $20, max_age_days = $21, headless_actions = $22, max_pages = $23, page_url_template = $24,
+            schedule_cron = $25, schedule_interval_minutes = $26, schedule_window_start = $27, schedule_window_end = $28, schedule_timezone = $29,
+            schedule_min_interval_minutes = $30, schedule_max_interval_minutes = $31
+        WHERE id = $1
+        RETURNING ` + siteConfigColumns
+
+	return st.changeSite(site.ID, changeType, note, author, query,
+		site.ID, site.SiteName, site.BaseURL, site.ScrapingType,
+		site.JobListItemSelector, site.TitleSelector, site.LinkSelector, site.LinkAttribute,
+		site.LocationSelector, site.NextPageSelector, site.JobDescriptionSelector, site.JobRequisitionIdSelector,
+		site.APIEndpointTemplate, site.APIMethod, site.APIHeadersJSON, site.APIPayloadTemplate, site.JSONDataMappings,
+		site.LogoURL, site.ATSSlug, site.URLPattern, site.MaxAgeDays, site.HeadlessActions, site.MaxPages, site.PageURLTemplate,
+		site.ScheduleCron, site.ScheduleIntervalMinutes, site.ScheduleWindowStart, site.ScheduleWindowEnd, site.ScheduleTimezone,
+		site.ScheduleMinIntervalMinutes, site.ScheduleMaxIntervalMinutes,
+	)
+}
+
+// SetSiteActive switches a site on or off by hand. Either way it clears why the site
+// was disabled automatically: a site switched off by hand is no longer one that
+// revalidation may switch back on.
+func (st *SiteCareerRepository) SetSiteActive(siteID int, active bool, note string, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error) {
+	query := `UPDATE site_scraping_config SET is_active = $2, disabled_reason = NULL, disabled_at = NULL
+        WHERE id = $1
+        RETURNING ` + siteConfigColumns
+
+	changeType := model.SiteConfigDeactivated
+	if active {
+		changeType = model.SiteConfigActivated
+	}
+	return st.changeSite(siteID, changeType, note, author, query, siteID, active)
+}
+
+// DisableSite switches an active site off, recording why, so the scheduler stops
+// scraping it. It reports whether the site was active.
+func (st *SiteCareerRepository) DisableSite(siteID int, reason string) (bool, error) {
+	query := `UPDATE site_scraping_config SET is_active = FALSE, disabled_reason = $2, disabled_at = NOW()
+        WHERE id = $1 AND is_active = TRUE
+        RETURNING ` + siteConfigColumns
+
+	_, err := st.changeSite(siteID, model.SiteConfigAutoDisable, reason, model.SiteConfigAuthor{}, query, siteID, reason)
+	if errors.Is(err, model.ErrSiteNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error disabling site %d: %w", siteID, err)
+	}
+	return true, nil
+}
+
+// DeleteSite removes a site; its last version, of type delete, keeps the config.
+func (st *SiteCareerRepository) DeleteSite(siteID int, author model.SiteConfigAuthor) error {
+	tx, err := st.connection.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	site, err := scanSiteConfig(tx.QueryRow(`SELECT `+siteConfigColumns+` FROM site_scraping_config WHERE id = $1 FOR UPDATE`, siteID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("site %d: %w", siteID, model.ErrSiteNotFound)
+		}
+		return fmt.Errorf("error fetching site %d: %w", siteID, err)
+	}
+	if err := recordConfigVersion(tx, site, model.SiteConfigDeleted, "", author); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM site_scraping_config WHERE id = $1`, siteID); err != nil {
+		return fmt.Errorf("error deleting site %d: %w", siteID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing site deletion: %w", err)
+	}
+	return nil
+}
+
+// GetScheduledSites lists the active sites with when the scheduler last enqueued them.
+func (st *SiteCareerRepository) GetScheduledSites() ([]model.ScheduledSite, error) {
+	query := `SELECT ` + siteConfigColumns + `, last_scheduled_at, adaptive_interval_minutes FROM site_scraping_config WHERE is_active = TRUE`
+
+	rows, err := st.connection.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying scheduled sites: %w", err)
+	}
+	defer rows.Close()
+
+	sites := []model.ScheduledSite{}
+	for rows.Next() {
+		var lastScheduled sql.NullTime
+		var adaptiveInterval sql.NullInt64
+		config, err := scanSiteConfig(rows, &lastScheduled, &adaptiveInterval)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning scheduled site: %w", err)
+		}
+		site := model.ScheduledSite{Config: config}
+		if lastScheduled.Valid {
+			site.LastScheduledAt = &lastScheduled.Time
+		}
+		if adaptiveInterval.Valid {
+			minutes := int(adaptiveInterval.Int64)
+			site.AdaptiveIntervalMinutes = &minutes
+		}
+		sites = append(sites, site)
+	}
+	return sites, rows.Err()
+}
+
+// MarkSiteScheduled records that the scheduler enqueued the site at the given time.
+func (st *SiteCareerRepository) MarkSiteScheduled(siteID int, at time.Time) error {
+	if _, err := st.connection.Exec(`UPDATE site_scraping_config SET last_scheduled_at = $2 WHERE id = $1`, siteID, at); err != nil {
+		return fmt.Errorf("error marking site %d as scheduled: %w", siteID, err)
+	}
+	return nil
+}
+
+// GetSitePostingStats counts, per active site, the jobs created since the given time
+// and the users subscribed to it.
+func (st *SiteCareerRepository) GetSitePostingStats(since time.Time) ([]model.SitePostingStats, error) {
+	query := `SELECT s.id,
+            (SELECT COUNT(*) FROM jobs j WHERE j.site_id = s.id AND j.created_at >= $1),
+            (SELECT COUNT(*) FROM user_sites us WHERE us.site_id = s.id)
+        FROM site_scraping_config s
+        WHERE s.is_active = TRUE`
+
+	rows, err := st.connection.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("error querying site posting stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []model.SitePostingStats{}
+	for rows.Next() {
+		var stat model.SitePostingStats
+		if err := rows.Scan(&stat.SiteID, &stat.NewJobs, &stat.Subscribers); err != nil {
+			return nil, fmt.Errorf("error scanning site posting stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// SetAdaptiveInterval stores the interval picked for an adaptive site.
+func (st *SiteCareerRepository) SetAdaptiveInterval(siteID int, minutes int) error {
+	query := `UPDATE site_scraping_config SET adaptive_interval_minutes = $2, adaptive_interval_updated_at = NOW() WHERE id = $1`
+	if _, err := st.connection.Exec(query, siteID, minutes); err != nil {
+		return fmt.Errorf("error setting adaptive interval of site %d: %w", siteID, err)
+	}
+	return nil
+}
+
+// GetSiteVersions lists the site's config versions, newest first.
+func (st *SiteCareerRepository) GetSiteVersions(siteID int) ([]model.SiteConfigVersion, error) {
+	query := `SELECT ` + siteVersionColumns + ` FROM site_config_versions WHERE site_id = $1 ORDER BY version DESC`
+
+	rows, err := st.connection.Query(query, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching config versions of site %d: %w", siteID, err)
+	}
+	defer rows.Close()
+
+	versions := []model.SiteConfigVersion{}
+	for rows.Next() {
+		version, err := scanSiteVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (st *SiteCareerRepository) GetSiteVersion(siteID int, version int) (model.SiteConfigVersion, error) {
+	query := `SELECT ` + siteVersionColumns + ` FROM site_config_versions WHERE site_id = $1 AND version = $2`
+
+	rows, err := st.connection.Query(query, siteID, version)
+	if err != nil {
+		return model.SiteConfigVersion{}, fmt.Errorf("error fetching version %d of site %d: %w", version, siteID, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return model.SiteConfigVersion{}, err
+		}
+		return model.SiteConfigVersion{}, fmt.Errorf("site %d version %d: %w", siteID, version, model.ErrSiteVersionNotFound)
+	}
+	return scanSiteVersion(rows)
+}
+
+// changeSite runs query, which must return the site's config columns, and records
+// the changed config as a new version in the same transaction.
+func (st *SiteCareerRepository) changeSite(siteID int, changeType string, note string, author model.SiteConfigAuthor, query string, args ...interface{}) (model.SiteScrapingConfig, error) {
+	tx, err := st.connection.Begin()
+	if err != nil {
+		return model.SiteScrapingConfig{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	site, err := scanSiteConfig(tx.QueryRow(query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.SiteScrapingConfig{}, fmt.Errorf("site %d: %w", siteID, model.ErrSiteNotFound)
+		}
+		return model.SiteScrapingConfig{}, fmt.Errorf("error changing site %d: %w", siteID, err)
+	}
+	if err := recordConfigVersion(tx, site, changeType, note, author); err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.SiteScrapingConfig{}, fmt.Errorf("error committing change of site %d: %w", siteID, err)
+	}
+	return site, nil
+}
+
+// recordConfigVersion stores site as the next version of its config. The caller holds
+// the site row locked, so versions are numbered without gaps or races.
+func recordConfigVersion(tx *sql.Tx, site model.SiteScrapingConfig, changeType string, note string, author model.SiteConfigAuthor) error {
+	snapshot, err := json.Marshal(site)
+	if err != nil {
+		return fmt.Errorf("error encoding config of site %d: %w", site.ID, err)
+	}
+
+	query := `INSERT INTO site_config_versions (site_id, version, change_type, config, changed_by, changed_by_email, note)
+        SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, NULLIF($4, 0), NULLIF($5, ''), NULLIF($6, '')
+        FROM site_config_versions WHERE site_id = $1`
+	if _, err := tx.Exec(query, site.ID, changeType, snapshot, author.UserID, author.Email, note); err != nil {
+		return fmt.Errorf("error recording config version of site %d: %w", site.ID, err)
+	}
+	return nil
+}
+
+const siteVersionColumns = `id, site_id, version, change_type, config, changed_by, COALESCE(changed_by_email, ''), COALESCE(note, ''), created_at`
+
+func scanSiteVersion(rows *sql.Rows) (model.SiteConfigVersion, error) {
+	var version model.SiteConfigVersion
+	var config []byte
+	var changedBy sql.NullInt64
+	err := rows.Scan(&version.ID, &version.SiteID, &version.Version, &version.ChangeType, &config,
+		&changedBy, &version.ChangedByEmail, &version.Note, &version.CreatedAt)
+	if err != nil {
+		return version, fmt.Errorf("error scanning config version: %w", err)
+	}
+	if changedBy.Valid {
+		id := int(changedBy.Int64)
+		version.ChangedBy = &id
+	}
+	if err := json.Unmarshal(config, &version.Config); err != nil {
+		return version, fmt.Errorf("error decoding config version %d of site %d: %w", version.Version, version.SiteID, err)
+	}
+	return version, nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"web-scrapper/model"
+
+	"github.com/lib/pq"
 )
 
 
@@ -104,15 +106,16 @@ func (dep *UserSiteRepository) GetUsersBySiteId(siteId int) ([]model.UserSiteCur
 	return users, nil
 }
 
-func (dep *UserSiteRepository) InsertNewUserSite(userId int, siteId int, filters []string) error{
-	query := `INSERT INTO user_sites(user_id, site_id, filters) VALUES($1, $2, $3)`
+func (dep *UserSiteRepository) InsertNewUserSite(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error{
+	query := `INSERT INTO user_sites(user_id, site_id, filters, seniorities, role_families, contract_types) VALUES($1, $2, $3, $4, $5, $6)`
 
 	jsonFilters, err := json.Marshal(filters)
     if err != nil {
         return fmt.Errorf("erro ao serializar os filtros para JSON: %w", err)
     }
 
-	_, err = dep.connection.Exec(query , userId, siteId, jsonFilters)
+	_, err = dep.connection.Exec(query , userId, siteId, jsonFilters,
+		textArray(classFilters.Seniorities), textArray(classFilters.RoleFamilies), textArray(classFilters.ContractTypes))
 
 	if err != nil{
 		return fmt.Errorf("error to insert register user %d to site %d: %w", userId, siteId, err)
@@ -177,16 +180,17 @@ func (usr *UserSiteRepository) GetUserSiteCount(userID int) (int, error) {
 	return count, nil
 }
 
-// UpdateUserSiteFilters atualiza os filtros (palavras-chave) de um user_site
-func (usr *UserSiteRepository) UpdateUserSiteFilters(userId int, siteId int, filters []string) error {
-	query := `UPDATE user_sites SET filters = $1 WHERE user_id = $2 AND site_id = $3`
+// UpdateUserSiteFilters atualiza os filtros (palavras-chave e classificação) de um user_site
+func (usr *UserSiteRepository) UpdateUserSiteFilters(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error {
+	query := `UPDATE user_sites SET filters = $1, seniorities = $4, role_families = $5, contract_types = $6 WHERE user_id = $2 AND site_id = $3`
 
 	jsonFilters, err := json.Marshal(filters)
 	if err != nil {
 		return fmt.Errorf("erro ao serializar os filtros para JSON: %w", err)
 	}
 
-	result, err := usr.connection.Exec(query, jsonFilters, userId, siteId)
+	result, err := usr.connection.Exec(query, jsonFilters, userId, siteId,
+		textArray(classFilters.Seniorities), textArray(classFilters.RoleFamilies), textArray(classFilters.ContractTypes))
 	if err != nil {
 		return fmt.Errorf("error to update user_site filters: %w", err)
 	}
@@ -203,6 +207,14 @@ func (usr *UserSiteRepository) UpdateUserSiteFilters(userId int, siteId int, fil
 	return nil
 }
 
+// textArray binds values to a NOT NULL TEXT[] column, as '{}' when empty.
+func textArray(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+	return pq.StringArray(values)
+}
+
 // GetActiveUserIDs returns all user IDs that have at least one monitored site.
 // The query is unbounded but acceptable: user count scales with paid subscriptions
 // (expected <10k). If scale becomes a concern, add LIMIT/OFFSET pagination.
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"time"
 	"unicode"
+	"web-scrapper/logging"
 	"web-scrapper/model"
+	"web-scrapper/normalizer"
 
 	"github.com/tidwall/gjson"
 	"golang.org/x/text/runes"
@@ -20,6 +22,7 @@ import (
 
 type APIScrapper struct {
 	client *http.Client
+	clock  func() time.Time
 }
 
 func NewAPIScrapper() *APIScrapper{
@@ -30,6 +33,18 @@ func NewAPIScrapper() *APIScrapper{
 	}
 }
 
+func (s *APIScrapper) usePoliteness(p *Politeness) {
+	s.client.Transport = p.Transport(s.client.Transport)
+}
+
+func (s *APIScrapper) useTransport(rt http.RoundTripper) {
+	s.client.Transport = rt
+}
+
+func (s *APIScrapper) useClock(now func() time.Time) {
+	s.clock = now
+}
+
 func (s *APIScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error){
 	if config.APIEndpointTemplate == nil {
 		return nil, fmt.Errorf("API endpoint template is required for site %s", config.SiteName)
@@ -38,16 +53,121 @@ func (s *APIScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfi
 		return nil, fmt.Errorf("JSON data mappings is required for site %s", config.SiteName)
 	}
 
+	var mappings Mapeamentos
+	if err := json.Unmarshal([]byte(*config.JSONDataMappings), &mappings); err != nil {
+		return nil, fmt.Errorf("ERROR to parse json maps: %w", err)
+	}
+
 	method := "GET"
 	if config.APIMethod != nil && *config.APIMethod != "" {
-		method = *config.APIMethod
+		method = strings.ToUpper(*config.APIMethod)
+	}
+
+	endpointTmpl, err := parseRequestTemplate("endpoint", *config.APIEndpointTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%w (site %s)", err, config.SiteName)
+	}
+	var payloadTmpl *requestTemplate
+	if config.APIPayloadTemplate != nil && strings.TrimSpace(*config.APIPayloadTemplate) != "" {
+		payloadTmpl, err = parseRequestTemplate("payload", *config.APIPayloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("%w (site %s)", err, config.SiteName)
+		}
+	}
+
+	pages, err := newPaginator(mappings.Pagination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pagination config for %s: %w", config.SiteName, err)
+	}
+
+	now := time.Now()
+	if s.clock != nil {
+		now = s.clock()
+	}
+	var jobs []*model.Job
+	for {
+		pageURL, payload, err := s.buildPageRequest(pages, endpointTmpl, payloadTmpl, newRequestVars(pages.vars(), mappings.SearchKeyword, now))
+		if err != nil {
+			return nil, fmt.Errorf("%w (site %s)", err, config.SiteName)
+		}
+		if pages.markSeen(method + " " + pageURL + " " + payload) {
+			logging.Logger.Debug().Str("site_name", config.SiteName).Str("url", pageURL).Msg("API page already fetched, stopping pagination")
+			break
+		}
+
+		// A page missing from the listing would make its jobs look closed, so any
+		// failure fails the whole scrape
+		body, err := s.fetchPage(ctx, config, method, pageURL, payload)
+		if err != nil {
+			return nil, listingPageError(pages.state.Page, err)
+		}
+
+		pageJobs, err := s.parseAPIResponse(body, mappings, config.BaseURL)
+		if err != nil {
+			return nil, listingPageError(pages.state.Page, err)
+		}
+		jobs = append(jobs, pageJobs...)
+
+		hasNext, err := pages.next(pageURL, body, len(pageJobs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute next API page for %s: %w", config.SiteName, err)
+		}
+		if !hasNext {
+			break
+		}
+		logging.Logger.Debug().Str("site_name", config.SiteName).Int("page", pages.state.Page).Msg("Fetching next API page")
+	}
+
+	return jobs, nil
+}
+
+// listingPageError wraps the failure of a listing page after the first, which the
+// first page's error alone would not tell apart.
+func listingPageError(page int, err error) error {
+	if page == 1 {
+		return err
 	}
+	return fmt.Errorf("listing page %d: %w", page, err)
+}
 
-	req, err := http.NewRequestWithContext(ctx, method, *config.APIEndpointTemplate, nil)
+// buildPageRequest renders the endpoint and payload templates for the current page.
+// For next_url pagination the URL read from the previous response wins over the template.
+func (s *APIScrapper) buildPageRequest(pages *paginator, endpointTmpl, payloadTmpl *requestTemplate, vars requestVars) (string, string, error) {
+	payload, err := payloadTmpl.render(vars)
+	if err != nil {
+		return "", "", err
+	}
+
+	if pages.state.NextURL != "" {
+		return pages.state.NextURL, payload, nil
+	}
+
+	pageURL, err := endpointTmpl.render(vars)
+	if err != nil {
+		return "", "", err
+	}
+	pageURL, err = pages.applyQuery(pageURL)
+	if err != nil {
+		return "", "", err
+	}
+	return pageURL, payload, nil
+}
+
+func (s *APIScrapper) fetchPage(ctx context.Context, config model.SiteScrapingConfig, method string, pageURL string, payload string) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != "" && method != http.MethodGet {
+		reqBody = strings.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, pageURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("ERROR to create request %s: %w", config.SiteName, err)
 	}
 
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
 	if config.APIHeadersJSON != nil && *config.APIHeadersJSON != "" {
 		var headers map[string]string
 		if err := json.Unmarshal([]byte(*config.APIHeadersJSON), &headers); err == nil {
@@ -67,30 +187,33 @@ func (s *APIScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfi
 		return nil, fmt.Errorf("unexpected status code to %s: %d", config.SiteName, resp.StatusCode)
 	}
 
-	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao ler corpo da resposta de %s: %w", config.SiteName, err)
 	}
 
-	return s.parseAPIResponse(body, *config.JSONDataMappings, config.BaseURL)
+	return body, nil
 }
 
 type Mapeamentos struct {
-	JobsArrayPath     string `json:"jobs_array_path"`
-	TitlePath         string `json:"title_path"`
-	LinkPath          string `json:"link_path"`
-	LocationPath      string `json:"location_path"`
-	DescriptionPath   string `json:"description_path"`
-	RequisitionIDPath string `json:"requisition_id_path"`
+	JobsArrayPath      string      `json:"jobs_array_path"`
+	TitlePath          string      `json:"title_path"`
+	LinkPath           string      `json:"link_path"`
+	LocationPath       string      `json:"location_path"`
+	DescriptionPath    string      `json:"description_path"`
+	RequisitionIDPath  string      `json:"requisition_id_path"`
+	SalaryMinPath      string      `json:"salary_min_path,omitempty"`
+	SalaryMaxPath      string      `json:"salary_max_path,omitempty"`
+	SalaryCurrencyPath string      `json:"salary_currency_path,omitempty"`
+	SalaryPeriodPath   string      `json:"salary_period_path,omitempty"`
+	SalaryTextPath     string      `json:"salary_text_path,omitempty"` // a single string such as "R$ 5.000 a R$ 7.000", used when there are no numeric paths
+	BenefitsPath       string      `json:"benefits_path,omitempty"`    // an array of names or a single text listing them
+	Pagination         *Pagination `json:"pagination,omitempty"`
+	SearchKeyword      string      `json:"search_keyword,omitempty"` // exposed to the templates as {{.Keyword}}
+	LinkBaseURL        string      `json:"link_base_url,omitempty"`  // prefix for relative links, used as-is instead of BaseURL + title slug
 }
 
-func (s *APIScrapper) parseAPIResponse(body []byte, mappingsJSON string, baseURL string) ([]*model.Job, error) {
-	var mappings Mapeamentos
-	if err := json.Unmarshal([]byte(mappingsJSON), &mappings); err != nil {
-		return nil, fmt.Errorf("ERROR to parse json maps: %w", err)
-	}
-
+func (s *APIScrapper) parseAPIResponse(body []byte, mappings Mapeamentos, baseURL string) ([]*model.Job, error) {
 	var jobs []*model.Job
 	result := gjson.Get(string(body), mappings.JobsArrayPath)
 
@@ -101,7 +224,9 @@ func (s *APIScrapper) parseAPIResponse(body []byte, mappingsJSON string, baseURL
 	result.ForEach(func(key, value gjson.Result) bool {
 		title := value.Get(mappings.TitlePath).String()
 		jobLink := value.Get(mappings.LinkPath).String()
-		if jobLink != "" && !strings.HasPrefix(jobLink, "http") {
+		if jobLink != "" && !strings.HasPrefix(jobLink, "http") && mappings.LinkBaseURL != "" {
+			jobLink = strings.TrimRight(mappings.LinkBaseURL, "/") + "/" + strings.TrimLeft(jobLink, "/")
+		} else if jobLink != "" && !strings.HasPrefix(jobLink, "http") {
 			slug := generateSlug(title)
 			base := strings.TrimRight(baseURL, "/")
 			id := strings.TrimLeft(jobLink, "/")
@@ -123,6 +248,10 @@ func (s *APIScrapper) parseAPIResponse(body []byte, mappingsJSON string, baseURL
 		if reqIDStr != "" {
 			job.RequisitionID = reqIDStr
 		}
+		applySalaryMappings(job, value, mappings)
+		if mappings.BenefitsPath != "" {
+			job.Benefits = stringValues(value.Get(mappings.BenefitsPath))
+		}
 
 		jobs = append(jobs, job)
 		return true 
@@ -131,6 +260,36 @@ func (s *APIScrapper) parseAPIResponse(body []byte, mappingsJSON string, baseURL
 	return jobs, nil
 }
 
+// applySalaryMappings reads the pay of an API job from numeric paths, or from a
+// salary text when the API only has that.
+func applySalaryMappings(job *model.Job, item gjson.Result, mappings Mapeamentos) {
+	if mappings.SalaryMinPath != "" {
+		job.SalaryMin = positiveAmount(item.Get(mappings.SalaryMinPath))
+	}
+	if mappings.SalaryMaxPath != "" {
+		job.SalaryMax = positiveAmount(item.Get(mappings.SalaryMaxPath))
+	}
+	if job.SalaryMin == nil && job.SalaryMax == nil && mappings.SalaryTextPath != "" {
+		if salary := normalizer.ParseSalaryField(item.Get(mappings.SalaryTextPath).String()); salary != nil {
+			job.SalaryMin, job.SalaryMax = salary.Min, salary.Max
+			job.SalaryCurrency, job.SalaryPeriod = salary.Currency, salary.Period
+		}
+	}
+	if job.SalaryMin == nil && job.SalaryMax == nil {
+		return
+	}
+	if mappings.SalaryCurrencyPath != "" {
+		if currency := strings.TrimSpace(item.Get(mappings.SalaryCurrencyPath).String()); currency != "" {
+			job.SalaryCurrency = strings.ToUpper(currency)
+		}
+	}
+	if mappings.SalaryPeriodPath != "" {
+		if period := normalizer.SalaryPeriodOf(item.Get(mappings.SalaryPeriodPath).String()); period != "" {
+			job.SalaryPeriod = period
+		}
+	}
+}
+
 var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
 
 func generateSlug(title string) string {
@@ -0,0 +1,328 @@
+package scrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func apiConfig(endpoint, mappings string) model.SiteScrapingConfig {
+	return model.SiteScrapingConfig{
+		SiteName:            "Acme",
+		BaseURL:             "https://acme.com/careers",
+		ScrapingType:        "API",
+		APIEndpointTemplate: strPtr(endpoint),
+		JSONDataMappings:    strPtr(mappings),
+	}
+}
+
+func TestAPIScrapper_Scrape_SinglePage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jobs":[{"id":1,"title":"Go Dev","url":"https://acme.com/1"},{"id":2,"title":"QA","url":"https://acme.com/2"}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"jobs","title_path":"title","link_path":"url","requisition_id_path":"id"}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, "1", jobs[0].RequisitionID)
+}
+
+func TestAPIScrapper_Scrape_SalaryAndBenefitsMappings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jobs":[
+			{"id":1,"title":"Go Dev","url":"https://acme.com/1","pay":{"min":"8000","max":12000,"currency":"brl","unit":"MONTHLY"},"perks":["VR","Plano de Saúde"]},
+			{"id":2,"title":"QA","url":"https://acme.com/2","pay":{"text":"US$ 40 - 55/hour"},"perks":"Gympass e PLR"},
+			{"id":3,"title":"PO","url":"https://acme.com/3","pay":{"text":"A combinar"}}
+		]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"jobs","title_path":"title","link_path":"url","requisition_id_path":"id",
+		"salary_min_path":"pay.min","salary_max_path":"pay.max","salary_currency_path":"pay.currency","salary_period_path":"pay.unit",
+		"salary_text_path":"pay.text","benefits_path":"perks"}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+	require.NotNil(t, jobs[0].SalaryMin)
+	assert.Equal(t, 8000.0, *jobs[0].SalaryMin)
+	assert.Equal(t, 12000.0, *jobs[0].SalaryMax)
+	assert.Equal(t, "BRL", jobs[0].SalaryCurrency)
+	assert.Equal(t, "month", jobs[0].SalaryPeriod)
+	assert.Equal(t, []string{"VR", "Plano de Saúde"}, jobs[0].Benefits)
+
+	require.NotNil(t, jobs[1].SalaryMin)
+	assert.Equal(t, 40.0, *jobs[1].SalaryMin)
+	assert.Equal(t, 55.0, *jobs[1].SalaryMax)
+	assert.Equal(t, "USD", jobs[1].SalaryCurrency)
+	assert.Equal(t, "hour", jobs[1].SalaryPeriod)
+	assert.Equal(t, []string{"Gympass e PLR"}, jobs[1].Benefits)
+
+	assert.Nil(t, jobs[2].SalaryMin)
+	assert.Nil(t, jobs[2].SalaryMax)
+	assert.Empty(t, jobs[2].SalaryCurrency)
+}
+
+func TestAPIScrapper_Scrape_OffsetPagination(t *testing.T) {
+	const total = 7
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		num, _ := strconv.Atoi(r.URL.Query().Get("num"))
+		assert.Equal(t, 3, num)
+
+		var items []string
+		for i := start; i < start+num && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"id":%d,"name":"Job %d"}`, i, i))
+		}
+		fmt.Fprintf(w, `{"count":%d,"positions":[%s]}`, total, strings.Join(items, ","))
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL+"/jobs?start=0&num=100", `{
+		"jobs_array_path":"positions","title_path":"name","requisition_id_path":"id",
+		"pagination":{"type":"offset","param":"start","size_param":"num","page_size":3,"total_path":"count"}
+	}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, total)
+	assert.Equal(t, 3, requests)
+}
+
+func TestAPIScrapper_Scrape_PagePagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"data":[{"t":"A"},{"t":"B"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"data":[{"t":"C"}]}`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"data","title_path":"t","pagination":{"type":"page","param":"page"}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+}
+
+func TestAPIScrapper_Scrape_ZeroBasedPagePagination(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		switch r.URL.Query().Get("page") {
+		case "0":
+			fmt.Fprint(w, `{"content":[{"t":"A"},{"t":"B"}]}`)
+		case "1":
+			fmt.Fprint(w, `{"content":[{"t":"C"}]}`)
+		default:
+			fmt.Fprint(w, `{"content":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"content","title_path":"t","pagination":{"type":"page","param":"page","start":0}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, []string{"0", "1", "2"}, pages)
+}
+
+func TestAPIScrapper_Scrape_CursorPagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"items":[{"t":"A"}],"meta":{"next":"abc"}}`)
+		case "abc":
+			fmt.Fprint(w, `{"items":[{"t":"B"}],"meta":{"next":""}}`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"items","title_path":"t","pagination":{"type":"cursor","param":"cursor","next_path":"meta.next"}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+}
+
+func TestAPIScrapper_Scrape_NextURLPaginationStopsOnLoop(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Page 2 points back to page 1: the scraper must not loop forever.
+		if r.URL.Path == "/p2" {
+			fmt.Fprint(w, `{"items":[{"t":"B"}],"next":"/p1"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"t":"A"}],"next":"/p2"}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL+"/p1", `{"jobs_array_path":"items","title_path":"t","pagination":{"type":"next_url","next_path":"next"}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, 2, requests)
+}
+
+func TestAPIScrapper_Scrape_RespectsMaxPages(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"items":[{"t":"A"},{"t":"B"}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"items","title_path":"t","pagination":{"type":"page","param":"p","max_pages":4}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 8)
+	assert.Equal(t, 4, requests)
+}
+
+func TestAPIScrapper_Scrape_FailsOnLaterPageFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"t":"A"}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"items","title_path":"t","pagination":{"type":"page","param":"page"}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listing page 2")
+	assert.Nil(t, jobs)
+}
+
+func TestAPIScrapper_Scrape_FailsOnUnparsableLaterPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `<html>maintenance</html>`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"t":"A"}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL, `{"jobs_array_path":"items","title_path":"t","pagination":{"type":"page","param":"page"}}`)
+
+	_, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	assert.Error(t, err)
+}
+
+func TestAPIScrapper_Scrape_InvalidPagination(t *testing.T) {
+	for _, mappings := range []string{
+		`{"jobs_array_path":"items","pagination":{"type":"cursor"}}`,
+		`{"jobs_array_path":"items","pagination":{"type":"page","start":-1}}`,
+	} {
+		_, err := NewAPIScrapper().Scrape(context.Background(), apiConfig("http://localhost", mappings))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid pagination config")
+	}
+}
+
+func TestAPIScrapper_Scrape_PostPayloadTemplate(t *testing.T) {
+	var offsets []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var body struct {
+			Limit      int    `json:"limit"`
+			Offset     int    `json:"offset"`
+			SearchText string `json:"searchText"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, 2, body.Limit)
+		assert.Equal(t, `dev "go"`, body.SearchText)
+		offsets = append(offsets, body.Offset)
+
+		if body.Offset == 0 {
+			fmt.Fprint(w, `{"total":3,"jobPostings":[{"title":"A"},{"title":"B"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total":3,"jobPostings":[{"title":"C"}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL+"/wday/cxs/acme/Careers/jobs", `{
+		"jobs_array_path":"jobPostings","title_path":"title","search_keyword":"dev \"go\"",
+		"pagination":{"type":"offset","page_size":2,"total_path":"total"}
+	}`)
+	cfg.APIMethod = strPtr("POST")
+	cfg.APIPayloadTemplate = strPtr(`{"appliedFacets":{},"limit":{{.PageSize}},"offset":{{.Offset}},"searchText":{{json .Keyword}}}`)
+
+	jobs, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, []int{0, 2}, offsets)
+}
+
+func TestAPIScrapper_Scrape_EndpointTemplate(t *testing.T) {
+	var gotPaths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.RequestURI())
+		fmt.Fprint(w, `{"items":[]}`)
+	}))
+	defer srv.Close()
+
+	cfg := apiConfig(srv.URL+"/jobs?since={{.Today}}&page={{.Page}}", `{"jobs_array_path":"items","title_path":"t"}`)
+
+	_, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, gotPaths, 1)
+	today := time.Now().In(saoPaulo).Format("2006-01-02")
+	assert.Equal(t, "/jobs?since="+today+"&page=1", gotPaths[0])
+}
+
+func TestAPIScrapper_Scrape_InvalidTemplate(t *testing.T) {
+	cfg := apiConfig("http://localhost/jobs?p={{.Nope", `{"jobs_array_path":"items"}`)
+
+	_, err := NewAPIScrapper().Scrape(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid endpoint template")
+}
@@ -0,0 +1,267 @@
+package scrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+)
+
+// ATS platforms with built-in scraping presets. Sites using one of these
+// scraping types only need ATSSlug; endpoints, pagination, field mappings and
+// detail URLs come from the preset below.
+const (
+	ATSGreenhouse = "GREENHOUSE"
+	ATSLever      = "LEVER"
+	ATSGupy       = "GUPY"
+	ATSWorkday    = "WORKDAY"
+	ATSEightfold  = "EIGHTFOLD"
+)
+
+// atsPreset builds the underlying API or CSS config for a company slug.
+type atsPreset func(slug string) (model.SiteScrapingConfig, error)
+
+var atsPresets = map[string]atsPreset{
+	ATSGreenhouse: greenhousePreset,
+	ATSLever:      leverPreset,
+	ATSGupy:       gupyPreset,
+	ATSWorkday:    workdayPreset,
+	ATSEightfold:  eightfoldPreset,
+}
+
+// IsATSType reports whether scrapingType is one of the built-in ATS platforms.
+func IsATSType(scrapingType string) bool {
+	_, ok := atsPresets[scrapingType]
+	return ok
+}
+
+// ResolveATSConfig expands an ATS site config into the API or CSS config that
+// actually scrapes it. Identity fields (ID, SiteName, LogoURL, IsActive) are kept.
+// The slug decides where jobs are read from: BaseURL is only kept when it points to
+// the platform's own host, otherwise the public board URL is used.
+func ResolveATSConfig(config model.SiteScrapingConfig) (model.SiteScrapingConfig, error) {
+	preset, ok := atsPresets[config.ScrapingType]
+	if !ok {
+		return model.SiteScrapingConfig{}, fmt.Errorf("unknown ATS type: %s", config.ScrapingType)
+	}
+	if config.ATSSlug == nil || strings.TrimSpace(*config.ATSSlug) == "" {
+		return model.SiteScrapingConfig{}, fmt.Errorf("ats_slug is required for %s site %s", config.ScrapingType, config.SiteName)
+	}
+
+	resolved, err := preset(strings.TrimSpace(*config.ATSSlug))
+	if err != nil {
+		return model.SiteScrapingConfig{}, fmt.Errorf("invalid ats_slug for %s site %s: %w", config.ScrapingType, config.SiteName, err)
+	}
+
+	resolved.ID = config.ID
+	resolved.SiteName = config.SiteName
+	resolved.LogoURL = config.LogoURL
+	resolved.IsActive = config.IsActive
+	resolved.ATSSlug = config.ATSSlug
+	if config.BaseURL != "" {
+		if sameHost(config.BaseURL, resolved.BaseURL) {
+			resolved.BaseURL = config.BaseURL
+		} else {
+			logging.Logger.Warn().Str("site_name", config.SiteName).Str("base_url", config.BaseURL).Str("ats_base_url", resolved.BaseURL).Msg("Ignoring BaseURL outside the ATS host")
+		}
+	}
+	return resolved, nil
+}
+
+// ATSScrapper scrapes sites hosted on a known ATS by delegating to the API or
+// CSS scraper with the platform preset.
+type ATSScrapper struct {
+	api *APIScrapper
+	css *JobScrapper
+}
+
+func NewATSScrapper() *ATSScrapper {
+	return &ATSScrapper{
+		api: NewAPIScrapper(),
+		css: NewJobScraper(),
+	}
+}
+
+func (s *ATSScrapper) usePoliteness(p *Politeness) {
+	s.api.usePoliteness(p)
+	s.css.usePoliteness(p)
+}
+
+func (s *ATSScrapper) useTransport(rt http.RoundTripper) {
+	s.api.useTransport(rt)
+	s.css.useTransport(rt)
+}
+
+func (s *ATSScrapper) useClock(now func() time.Time) {
+	s.api.useClock(now)
+}
+
+func (s *ATSScrapper) useStats(stats *ScrapeStats) {
+	s.css.useStats(stats)
+}
+
+func (s *ATSScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error) {
+	resolved, err := ResolveATSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resolved.ScrapingType {
+	case "API":
+		return s.api.Scrape(ctx, resolved)
+	case "CSS":
+		return s.css.Scrape(ctx, resolved)
+	default:
+		return nil, fmt.Errorf("ATS preset for %s resolved to unsupported strategy %s", config.ScrapingType, resolved.ScrapingType)
+	}
+}
+
+// sameHost reports whether both URLs point to the same host.
+func sameHost(a, b string) bool {
+	ua, errA := url.Parse(strings.TrimSpace(a))
+	ub, errB := url.Parse(strings.TrimSpace(b))
+	return errA == nil && errB == nil && ua.Host != "" && strings.EqualFold(ua.Host, ub.Host)
+}
+
+func apiPreset(baseURL, endpoint, method string, payload *string, mappings Mapeamentos) (model.SiteScrapingConfig, error) {
+	mappingsJSON, err := json.Marshal(mappings)
+	if err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+	m := string(mappingsJSON)
+	headers := `{"Accept": "application/json"}`
+	return model.SiteScrapingConfig{
+		BaseURL:             baseURL,
+		ScrapingType:        "API",
+		APIEndpointTemplate: &endpoint,
+		APIMethod:           &method,
+		APIHeadersJSON:      &headers,
+		APIPayloadTemplate:  payload,
+		JSONDataMappings:    &m,
+	}, nil
+}
+
+// greenhousePreset: slug is the board token, e.g. "nubank".
+func greenhousePreset(slug string) (model.SiteScrapingConfig, error) {
+	if strings.ContainsAny(slug, "/?#") {
+		return model.SiteScrapingConfig{}, fmt.Errorf("greenhouse slug must be the board token, got %q", slug)
+	}
+	return apiPreset(
+		"https://job-boards.greenhouse.io/"+slug,
+		"https://boards-api.greenhouse.io/v1/boards/"+slug+"/jobs?content=true",
+		"GET", nil,
+		Mapeamentos{
+			JobsArrayPath:     "jobs",
+			TitlePath:         "title",
+			LinkPath:          "absolute_url",
+			LocationPath:      "location.name",
+			DescriptionPath:   "content",
+			RequisitionIDPath: "id",
+		},
+	)
+}
+
+// leverPreset: slug is the Lever account name, e.g. "acme" for jobs.lever.co/acme.
+func leverPreset(slug string) (model.SiteScrapingConfig, error) {
+	if strings.ContainsAny(slug, "/?#") {
+		return model.SiteScrapingConfig{}, fmt.Errorf("lever slug must be the account name, got %q", slug)
+	}
+	return apiPreset(
+		"https://jobs.lever.co/"+slug,
+		"https://api.lever.co/v0/postings/"+slug+"?mode=json",
+		"GET", nil,
+		Mapeamentos{
+			JobsArrayPath:     "@this",
+			TitlePath:         "text",
+			LinkPath:          "hostedUrl",
+			LocationPath:      "categories.location",
+			DescriptionPath:   "descriptionPlain",
+			RequisitionIDPath: "id",
+			Pagination:        &Pagination{Type: PaginationOffset, Param: "skip", SizeParam: "limit", PageSize: 100},
+		},
+	)
+}
+
+// gupyPreset: slug is the career page subdomain, e.g. "vemproitau" for vemproitau.gupy.io.
+// The board is rendered client-side from its public job listing API, so that is what
+// we read; the _next/data endpoint changes on every deploy.
+func gupyPreset(slug string) (model.SiteScrapingConfig, error) {
+	if strings.ContainsAny(slug, "./?#") {
+		return model.SiteScrapingConfig{}, fmt.Errorf("gupy slug must be the career page subdomain, got %q", slug)
+	}
+	origin := "https://" + slug + ".gupy.io"
+	return apiPreset(
+		origin+"/",
+		origin+"/api/job?name=",
+		"GET", nil,
+		Mapeamentos{
+			JobsArrayPath:     "data",
+			TitlePath:         "name",
+			LinkPath:          "id",
+			LocationPath:      "city",
+			DescriptionPath:   "description",
+			RequisitionIDPath: "id",
+			LinkBaseURL:       origin + "/jobs",
+			Pagination:        &Pagination{Type: PaginationOffset, Param: "offset", SizeParam: "limit", PageSize: 100, TotalPath: "pagination.total"},
+		},
+	)
+}
+
+// workdayPreset: slug is "{tenant}.{instance}/{site}", e.g. "natura.wd501/NaturaCarreiras"
+// for natura.wd501.myworkdayjobs.com/NaturaCarreiras.
+func workdayPreset(slug string) (model.SiteScrapingConfig, error) {
+	host, site, ok := strings.Cut(slug, "/")
+	tenant, instance, hasInstance := strings.Cut(host, ".")
+	if !ok || !hasInstance || tenant == "" || instance == "" || site == "" || strings.Contains(site, "/") {
+		return model.SiteScrapingConfig{}, fmt.Errorf("workday slug must look like tenant.wd1/Site, got %q", slug)
+	}
+	origin := "https://" + tenant + "." + instance + ".myworkdayjobs.com"
+	payload := `{"appliedFacets": {}, "limit": {{.PageSize}}, "offset": {{.Offset}}, "searchText": {{json .Keyword}}}`
+	return apiPreset(
+		origin+"/"+site,
+		origin+"/wday/cxs/"+tenant+"/"+site+"/jobs",
+		"POST", &payload,
+		Mapeamentos{
+			JobsArrayPath:     "jobPostings",
+			TitlePath:         "title",
+			LinkPath:          "externalPath",
+			LocationPath:      "locationsText",
+			RequisitionIDPath: "bulletFields.0",
+			LinkBaseURL:       origin + "/" + site,
+			// Workday rejects pages larger than 20.
+			Pagination: &Pagination{Type: PaginationOffset, PageSize: 20, TotalPath: "total"},
+		},
+	)
+}
+
+// eightfoldPreset: slug is "{tenant}" or "{tenant}/{domain}", e.g. "vale" or
+// "mercadolibre/mercadolibre.com". The domain defaults to "{tenant}.com".
+func eightfoldPreset(slug string) (model.SiteScrapingConfig, error) {
+	tenant, domain, hasDomain := strings.Cut(slug, "/")
+	if !hasDomain {
+		domain = tenant + ".com"
+	}
+	if tenant == "" || domain == "" || strings.ContainsAny(tenant, ".?#") || strings.ContainsAny(domain, "/?#") {
+		return model.SiteScrapingConfig{}, fmt.Errorf("eightfold slug must look like tenant or tenant/domain.com, got %q", slug)
+	}
+	origin := "https://" + tenant + ".eightfold.ai"
+	return apiPreset(
+		origin+"/careers",
+		origin+"/api/apply/v2/jobs?domain="+domain,
+		"GET", nil,
+		Mapeamentos{
+			JobsArrayPath:     "positions",
+			TitlePath:         "name",
+			LinkPath:          "canonicalPositionUrl",
+			LocationPath:      "location",
+			DescriptionPath:   "job_description",
+			RequisitionIDPath: "id",
+			Pagination:        &Pagination{Type: PaginationOffset, Param: "start", SizeParam: "num", PageSize: 100, TotalPath: "count"},
+		},
+	)
+}
@@ -0,0 +1,116 @@
+package scrapper
+
+import (
+	"encoding/json"
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func atsConfig(scrapingType, slug string) model.SiteScrapingConfig {
+	return model.SiteScrapingConfig{ID: 7, SiteName: "Acme", IsActive: true, ScrapingType: scrapingType, ATSSlug: strPtr(slug)}
+}
+
+func TestResolveATSConfig_Greenhouse(t *testing.T) {
+	cfg, err := ResolveATSConfig(atsConfig(ATSGreenhouse, "nubank"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "API", cfg.ScrapingType)
+	assert.Equal(t, 7, cfg.ID)
+	assert.Equal(t, "Acme", cfg.SiteName)
+	assert.Equal(t, "https://boards-api.greenhouse.io/v1/boards/nubank/jobs?content=true", *cfg.APIEndpointTemplate)
+	assert.Equal(t, "https://job-boards.greenhouse.io/nubank", cfg.BaseURL)
+}
+
+func TestResolveATSConfig_Workday(t *testing.T) {
+	cfg, err := ResolveATSConfig(atsConfig(ATSWorkday, "natura.wd501/NaturaCarreiras"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "POST", *cfg.APIMethod)
+	assert.Equal(t, "https://natura.wd501.myworkdayjobs.com/wday/cxs/natura/NaturaCarreiras/jobs", *cfg.APIEndpointTemplate)
+
+	var mappings Mapeamentos
+	require.NoError(t, json.Unmarshal([]byte(*cfg.JSONDataMappings), &mappings))
+	assert.Equal(t, "https://natura.wd501.myworkdayjobs.com/NaturaCarreiras", mappings.LinkBaseURL)
+	require.NotNil(t, mappings.Pagination)
+	assert.Equal(t, 20, mappings.Pagination.PageSize)
+
+	_, err = parseRequestTemplate("payload", *cfg.APIPayloadTemplate)
+	assert.NoError(t, err)
+}
+
+func TestResolveATSConfig_EightfoldDefaultsDomain(t *testing.T) {
+	cfg, err := ResolveATSConfig(atsConfig(ATSEightfold, "vale"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://vale.eightfold.ai/api/apply/v2/jobs?domain=vale.com", *cfg.APIEndpointTemplate)
+}
+
+func TestResolveATSConfig_GupyUsesJobListingAPI(t *testing.T) {
+	cfg, err := ResolveATSConfig(atsConfig(ATSGupy, "vemproitau"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "API", cfg.ScrapingType)
+	assert.Equal(t, "https://vemproitau.gupy.io/", cfg.BaseURL)
+	assert.Equal(t, "https://vemproitau.gupy.io/api/job?name=", *cfg.APIEndpointTemplate)
+
+	var mappings Mapeamentos
+	require.NoError(t, json.Unmarshal([]byte(*cfg.JSONDataMappings), &mappings))
+	jobs, err := NewAPIScrapper().parseAPIResponse([]byte(`{"data": [{"id": 4521, "name": "Analista de Dados", "city": "São Paulo", "description": "<p>SQL</p>"}], "pagination": {"total": 1}}`), mappings, cfg.BaseURL)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "https://vemproitau.gupy.io/jobs/4521", jobs[0].JobLink)
+	assert.Equal(t, "4521", jobs[0].RequisitionID)
+}
+
+func TestResolveATSConfig_KeepsBaseURLOnTheATSHost(t *testing.T) {
+	site := atsConfig(ATSLever, "acme")
+	site.BaseURL = "https://jobs.lever.co/acme?team=Engineering"
+
+	cfg, err := ResolveATSConfig(site)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://jobs.lever.co/acme?team=Engineering", cfg.BaseURL)
+}
+
+func TestResolveATSConfig_IgnoresBaseURLOutsideTheATSHost(t *testing.T) {
+	site := atsConfig(ATSGupy, "acme")
+	site.BaseURL = "https://acme.com/careers"
+
+	cfg, err := ResolveATSConfig(site)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://acme.gupy.io/", cfg.BaseURL)
+}
+
+func TestResolveATSConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		site model.SiteScrapingConfig
+	}{
+		{"missing slug", model.SiteScrapingConfig{SiteName: "Acme", ScrapingType: ATSLever}},
+		{"blank slug", atsConfig(ATSGreenhouse, "  ")},
+		{"workday without site", atsConfig(ATSWorkday, "natura.wd501")},
+		{"workday without instance", atsConfig(ATSWorkday, "natura/Careers")},
+		{"gupy with domain", atsConfig(ATSGupy, "acme.gupy.io")},
+		{"not an ATS", atsConfig("CSS", "acme")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveATSConfig(tt.site)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewScraperFactory_ATSTypes(t *testing.T) {
+	for _, scrapingType := range []string{ATSGreenhouse, ATSLever, ATSGupy, ATSWorkday, ATSEightfold} {
+		s, err := NewScraperFactory(model.SiteScrapingConfig{ScrapingType: scrapingType})
+
+		require.NoError(t, err)
+		assert.IsType(t, &ATSScrapper{}, s)
+	}
+}
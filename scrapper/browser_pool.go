@@ -0,0 +1,269 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+	"web-scrapper/logging"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	defaultBrowserPoolSize = 2
+	defaultBrowserMaxUses  = 25
+	defaultBrowserMaxAge   = 30 * time.Minute
+	browserStartTimeout    = 30 * time.Second
+	browserProbeTimeout    = 5 * time.Second
+	browserCloseTimeout    = 10 * time.Second
+)
+
+var ErrBrowserPoolClosed = errors.New("browser pool is closed")
+
+// BrowserPoolConfig bounds the Chrome processes owned by a BrowserPool.
+type BrowserPoolConfig struct {
+	Size    int           // browsers kept alive; each serves one scrape at a time
+	MaxUses int           // scrapes served before a browser is restarted, to reclaim leaked memory
+	MaxAge  time.Duration // lifetime after which a browser is restarted
+}
+
+// BrowserPoolStats is a snapshot of the pool, exposed as Prometheus gauges by the worker.
+type BrowserPoolStats struct {
+	Size     int
+	InUse    int
+	Alive    int
+	Launched uint64
+	Recycled uint64
+	Crashed  uint64
+	Waits    uint64 // acquires that had to wait for a free browser
+}
+
+type pooledBrowser struct {
+	allocCancel context.CancelFunc
+	ctx         context.Context // chromedp context owning the browser process
+	uses        int
+	startedAt   time.Time
+}
+
+// BrowserPool keeps a bounded set of long-lived Chrome processes and hands out an
+// isolated browser context (its own cookies and storage) per scrape. Browsers are
+// started lazily and restarted when they crash, leak tabs, or reach MaxUses/MaxAge.
+type BrowserPool struct {
+	cfg       BrowserPoolConfig
+	allocOpts []chromedp.ExecAllocatorOption
+	slots     chan *pooledBrowser
+
+	closed atomic.Bool
+	inUse  atomic.Int64
+	alive  atomic.Int64
+
+	launched atomic.Uint64
+	recycled atomic.Uint64
+	crashed  atomic.Uint64
+	waits    atomic.Uint64
+}
+
+func NewBrowserPool(cfg BrowserPoolConfig) *BrowserPool {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultBrowserPoolSize
+	}
+	if cfg.MaxUses <= 0 {
+		cfg.MaxUses = defaultBrowserMaxUses
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultBrowserMaxAge
+	}
+
+	p := &BrowserPool{
+		cfg:       cfg,
+		allocOpts: headlessAllocatorOptions(),
+		slots:     make(chan *pooledBrowser, cfg.Size),
+	}
+	for i := 0; i < cfg.Size; i++ {
+		p.slots <- &pooledBrowser{}
+	}
+	return p
+}
+
+func headlessAllocatorOptions() []chromedp.ExecAllocatorOption {
+	return append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("disable-crash-reporter", true),
+		chromedp.Flag("disable-extensions", true),
+		chromedp.Flag("disable-software-rasterizer", true),
+	)
+}
+
+// BrowserLease is a tab in a fresh browser context of a pooled browser. Tabs opened
+// with chromedp.NewContext(lease.Ctx) share that browser context.
+type BrowserLease struct {
+	Ctx     context.Context
+	pool    *BrowserPool
+	browser *pooledBrowser
+	cancel  context.CancelFunc
+	stop    func() bool
+	once    sync.Once
+}
+
+// Acquire waits for a free browser, starting or restarting it when needed. The
+// lease is cancelled when ctx is done; callers must always call Release.
+func (p *BrowserPool) Acquire(ctx context.Context) (*BrowserLease, error) {
+	if p.closed.Load() {
+		return nil, ErrBrowserPoolClosed
+	}
+
+	var b *pooledBrowser
+	select {
+	case b = <-p.slots:
+	default:
+		p.waits.Add(1)
+		select {
+		case b = <-p.slots:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for a headless browser: %w", ctx.Err())
+		}
+	}
+	if p.closed.Load() {
+		p.slots <- b
+		return nil, ErrBrowserPoolClosed
+	}
+
+	if b.ctx != nil && b.ctx.Err() != nil {
+		p.crashed.Add(1)
+		p.stopBrowser(b)
+	}
+	if b.ctx == nil {
+		if err := p.startBrowser(b); err != nil {
+			p.slots <- b
+			return nil, err
+		}
+	}
+
+	tabCtx, cancel := chromedp.NewContext(b.ctx, chromedp.WithNewBrowserContext())
+	stop := context.AfterFunc(ctx, cancel)
+	p.inUse.Add(1)
+	return &BrowserLease{Ctx: tabCtx, pool: p, browser: b, cancel: cancel, stop: stop}, nil
+}
+
+// Release closes the lease's browser context and returns the browser to the pool,
+// restarting it first if it crashed, leaked tabs or is due for recycling.
+func (l *BrowserLease) Release() {
+	l.once.Do(func() {
+		l.stop()
+		l.cancel()
+		l.pool.inUse.Add(-1)
+
+		b := l.browser
+		b.uses++
+		switch {
+		case b.ctx.Err() != nil || !l.pool.healthy(b):
+			l.pool.crashed.Add(1)
+			logging.Logger.Warn().Int("uses", b.uses).Msg("Headless browser is unhealthy, restarting it")
+			l.pool.stopBrowser(b)
+		case b.uses >= l.pool.cfg.MaxUses || time.Since(b.startedAt) >= l.pool.cfg.MaxAge:
+			l.pool.recycled.Add(1)
+			logging.Logger.Debug().Int("uses", b.uses).Dur("age", time.Since(b.startedAt)).Msg("Recycling headless browser")
+			l.pool.stopBrowser(b)
+		}
+		if l.pool.closed.Load() {
+			l.pool.stopBrowser(b)
+		}
+		l.pool.slots <- b
+	})
+}
+
+// healthy probes the browser and checks that no tabs were left behind by the lease.
+func (p *BrowserPool) healthy(b *pooledBrowser) bool {
+	probeCtx, cancel := context.WithTimeout(b.ctx, browserProbeTimeout)
+	defer cancel()
+
+	targets, err := chromedp.Targets(probeCtx)
+	if err != nil {
+		return false
+	}
+	pages := 0
+	for _, t := range targets {
+		if t.Type == "page" {
+			pages++
+		}
+	}
+	// Only the initial blank tab should remain once a lease is released.
+	return pages <= 1
+}
+
+func (p *BrowserPool) startBrowser(b *pooledBrowser) error {
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), p.allocOpts...)
+	browserCtx, _ := chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, args ...any) {
+		logging.Logger.Debug().Msgf(format, args...)
+	}))
+
+	started := make(chan error, 1)
+	go func() { started <- chromedp.Run(browserCtx) }()
+	select {
+	case err := <-started:
+		if err != nil {
+			allocCancel()
+			return fmt.Errorf("failed to start headless browser: %w", err)
+		}
+	case <-time.After(browserStartTimeout):
+		allocCancel()
+		return fmt.Errorf("failed to start headless browser: timed out after %s", browserStartTimeout)
+	}
+
+	b.allocCancel = allocCancel
+	b.ctx = browserCtx
+	b.uses = 0
+	b.startedAt = time.Now()
+	p.launched.Add(1)
+	p.alive.Add(1)
+	return nil
+}
+
+func (p *BrowserPool) stopBrowser(b *pooledBrowser) {
+	if b.ctx == nil {
+		return
+	}
+	closeCtx, cancel := context.WithTimeout(b.ctx, browserCloseTimeout)
+	if err := chromedp.Cancel(closeCtx); err != nil && !errors.Is(err, context.Canceled) {
+		logging.Logger.Debug().Err(err).Msg("Headless browser did not close gracefully")
+	}
+	cancel()
+	b.allocCancel()
+	b.ctx = nil
+	b.allocCancel = nil
+	p.alive.Add(-1)
+}
+
+// Stats returns a snapshot of the pool for metrics.
+func (p *BrowserPool) Stats() BrowserPoolStats {
+	return BrowserPoolStats{
+		Size:     p.cfg.Size,
+		InUse:    int(p.inUse.Load()),
+		Alive:    int(p.alive.Load()),
+		Launched: p.launched.Load(),
+		Recycled: p.recycled.Load(),
+		Crashed:  p.crashed.Load(),
+		Waits:    p.waits.Load(),
+	}
+}
+
+// Close stops every browser, waiting up to timeout for leased browsers to be released.
+func (p *BrowserPool) Close(timeout time.Duration) {
+	p.closed.Store(true)
+	deadline := time.After(timeout)
+	for i := 0; i < p.cfg.Size; i++ {
+		select {
+		case b := <-p.slots:
+			p.stopBrowser(b)
+		case <-deadline:
+			logging.Logger.Warn().Int("in_use", int(p.inUse.Load())).Msg("Timed out waiting for headless browsers to be released")
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package scrapper
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBrowserPool_Defaults(t *testing.T) {
+	pool := NewBrowserPool(BrowserPoolConfig{})
+
+	stats := pool.Stats()
+	assert.Equal(t, defaultBrowserPoolSize, stats.Size)
+	assert.Equal(t, 0, stats.Alive)
+	assert.Equal(t, defaultBrowserMaxUses, pool.cfg.MaxUses)
+	assert.Equal(t, defaultBrowserMaxAge, pool.cfg.MaxAge)
+}
+
+func TestBrowserPool_AcquireWaitsForFreeBrowser(t *testing.T) {
+	pool := NewBrowserPool(BrowserPoolConfig{Size: 1})
+	busy := <-pool.slots // simulate a browser leased to another scrape
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := pool.Acquire(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, uint64(1), pool.Stats().Waits)
+	pool.slots <- busy
+}
+
+func TestBrowserPool_AcquireAfterClose(t *testing.T) {
+	pool := NewBrowserPool(BrowserPoolConfig{Size: 1})
+	pool.Close(time.Second)
+
+	_, err := pool.Acquire(context.Background())
+
+	assert.ErrorIs(t, err, ErrBrowserPoolClosed)
+}
+
+func TestNewScraperFactory_HeadlessWithPool(t *testing.T) {
+	pool := NewBrowserPool(BrowserPoolConfig{Size: 1})
+
+	s, err := NewScraperFactory(model.SiteScrapingConfig{ScrapingType: "HEADLESS"}, WithBrowserPool(pool))
+
+	require.NoError(t, err)
+	require.IsType(t, &HeadlessScraper{}, s)
+	assert.Same(t, pool, s.(*HeadlessScraper).pool)
+}
+
+func TestBrowserPool_ReusesBrowser(t *testing.T) {
+	if !chromeAvailable() {
+		t.Skip("Chrome not installed")
+	}
+	pool := NewBrowserPool(BrowserPoolConfig{Size: 1, MaxUses: 2})
+	defer pool.Close(10 * time.Second)
+
+	for i := 0; i < 3; i++ {
+		lease, err := pool.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, pool.Stats().InUse)
+		lease.Release()
+	}
+
+	stats := pool.Stats()
+	assert.Equal(t, 0, stats.InUse)
+	// The third scrape runs on a new browser: the first one reached MaxUses.
+	assert.Equal(t, uint64(2), stats.Launched)
+	assert.Equal(t, uint64(1), stats.Recycled)
+}
+
+func chromeAvailable() bool {
+	for _, name := range []string{"headless-shell", "chromium", "chromium-browser", "google-chrome", "google-chrome-stable"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
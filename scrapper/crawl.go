@@ -0,0 +1,74 @@
+package scrapper
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"web-scrapper/model"
+)
+
+// pageCrawl tracks the listing pages visited by one colly scrape, so pagination
+// stops at MaxPages and never revisits a page reached through a different link.
+type pageCrawl struct {
+	mu       sync.Mutex
+	maxPages int
+	template string
+	visited  map[string]bool
+	pages    int
+}
+
+func newPageCrawl(config model.SiteScrapingConfig) *pageCrawl {
+	p := &pageCrawl{visited: make(map[string]bool)}
+	maxPages := 0
+	if config.MaxPages != nil {
+		maxPages = *config.MaxPages
+	}
+	p.maxPages = clampMaxPages(maxPages)
+	if config.PageURLTemplate != nil {
+		p.template = strings.TrimSpace(*config.PageURLTemplate)
+	}
+	return p
+}
+
+// claim reports whether rawURL should be visited as the next listing page, and
+// counts it as visited when it should.
+func (p *pageCrawl) claim(rawURL string) bool {
+	key := normalizePageURL(rawURL)
+	if key == "" {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.visited[key] || p.pages >= p.maxPages {
+		return false
+	}
+	p.visited[key] = true
+	p.pages++
+	return true
+}
+
+// numberedURL renders PageURLTemplate for page n, e.g. "https://acme.com/jobs?page={n}".
+func (p *pageCrawl) numberedURL(n int) string {
+	if p.template == "" {
+		return ""
+	}
+	return strings.ReplaceAll(p.template, "{n}", strconv.Itoa(n))
+}
+
+// normalizePageURL drops the fragment and sorts the query, so "?b=2&a=1#top"
+// and "?a=1&b=2" count as the same page.
+func normalizePageURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.Fragment = ""
+	u.RawQuery = u.Query().Encode()
+	u.Host = strings.ToLower(u.Host)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -0,0 +1,38 @@
+package scrapper
+
+import "web-scrapper/model"
+
+// DetailFilter is called for each job found on a listing page, before its detail
+// page is fetched. Returning false skips the fetch; the filter may then fill in
+// what the detail page would have provided, such as a known requisition ID.
+// It is called concurrently.
+type DetailFilter func(job *model.Job) bool
+
+// WithDetailFilter makes CSS, HEADLESS, JSONLD and FEED scrapers ask f before
+// fetching a job's detail page.
+func WithDetailFilter(f DetailFilter) Option {
+	return func(o *factoryOptions) {
+		o.detailFilter = f
+	}
+}
+
+// wants reports whether job's detail page should be fetched. A nil filter fetches
+// every detail page.
+func (f DetailFilter) wants(job *model.Job) bool {
+	return f == nil || f(job)
+}
+
+// detailScraper is implemented by scrapers that fetch a detail page per job.
+type detailScraper interface {
+	useDetailFilter(f DetailFilter)
+}
+
+// UsesDetailPages reports whether scrapers for config fetch a detail page per job,
+// and so can benefit from a DetailFilter.
+func UsesDetailPages(config model.SiteScrapingConfig) bool {
+	switch config.ScrapingType {
+	case "CSS", "HEADLESS", "JSONLD", "FEED":
+		return true
+	}
+	return false
+}
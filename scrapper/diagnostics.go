@@ -0,0 +1,353 @@
+package scrapper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+	"web-scrapper/model"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// maxDiagnosticPages is how many response bodies are kept to check selectors against.
+	maxDiagnosticPages = 30
+	// maxDiagnosticBody caps each kept body.
+	maxDiagnosticBody = 2 << 20
+	maxSampleBytes    = 4096
+	maxItemSamples    = 3
+)
+
+// Diagnostics records the requests of a scrape, with their status, timing and
+// bodies, to explain its result in the sandbox. A nil *Diagnostics records nothing.
+type Diagnostics struct {
+	started time.Time
+
+	mu       sync.Mutex
+	requests []model.SandboxRequest
+	bodies   []diagnosticBody
+}
+
+type diagnosticBody struct {
+	url  string
+	body []byte
+}
+
+// NewDiagnostics starts timing a scrape.
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{started: time.Now()}
+}
+
+// WithDiagnostics makes the built scraper report its requests into d.
+func WithDiagnostics(d *Diagnostics) Option {
+	return func(o *factoryOptions) {
+		o.diagnostics = d
+	}
+}
+
+// diagnosedScraper is implemented by scrapers that fetch pages outside of the HTTP
+// transport (in a browser) and report them to Diagnostics themselves.
+type diagnosedScraper interface {
+	useDiagnostics(d *Diagnostics)
+}
+
+// Transport wraps base so every request passing through it is recorded.
+func (d *Diagnostics) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if d == nil {
+		return base
+	}
+	return &diagnosticTransport{diagnostics: d, base: base}
+}
+
+type diagnosticTransport struct {
+	diagnostics *Diagnostics
+	base        http.RoundTripper
+}
+
+func (t *diagnosticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.diagnostics.record(model.SandboxRequest{Method: req.Method, URL: req.URL.String(), Error: err.Error()}, started, nil)
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.diagnostics.record(model.SandboxRequest{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode, Error: err.Error()}, started, nil)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.diagnostics.record(model.SandboxRequest{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode}, started, body)
+	return resp, nil
+}
+
+// recordRendered saves a page the browser rendered, or failed to render.
+func (d *Diagnostics) recordRendered(pageURL string, started time.Time, html string, err error) {
+	if d == nil {
+		return
+	}
+	req := model.SandboxRequest{Method: http.MethodGet, URL: pageURL, Rendered: true}
+	if err != nil {
+		req.Error = err.Error()
+		d.record(req, started, nil)
+		return
+	}
+	d.record(req, started, []byte(html))
+}
+
+func (d *Diagnostics) record(req model.SandboxRequest, started time.Time, body []byte) {
+	req.DurationMs = time.Since(started).Milliseconds()
+	req.Bytes = len(body)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = append(d.requests, req)
+	if req.Error == "" && len(d.bodies) < maxDiagnosticPages && (req.Status == 0 || req.Status < 300) {
+		if len(body) > maxDiagnosticBody {
+			body = body[:maxDiagnosticBody]
+		}
+		d.bodies = append(d.bodies, diagnosticBody{url: req.URL, body: body})
+	}
+}
+
+// Report builds the sandbox report of a scrape of config that returned jobs.
+func (d *Diagnostics) Report(config model.SiteScrapingConfig, jobs []*model.Job) model.SandboxDiagnostics {
+	report := model.SandboxDiagnostics{
+		JobsFound:    len(jobs),
+		FillRates:    fillRates(jobs),
+		Requests:     []model.SandboxRequest{},
+		Samples:      []model.SandboxSample{},
+		DuplicateIDs: duplicateIDs(jobs),
+		Selectors:    []model.SandboxSelectorMatch{},
+		Warnings:     []string{},
+	}
+	if d == nil {
+		return report
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	report.DurationMs = time.Since(d.started).Milliseconds()
+
+	// CSS scrapers may keep job links relative to the listing
+	base, _ := url.Parse(config.BaseURL)
+	detailURLs := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		link, err := url.Parse(strings.TrimSpace(job.JobLink))
+		if err != nil || job.JobLink == "" {
+			continue
+		}
+		if base != nil {
+			link = base.ResolveReference(link)
+		}
+		if normalized := normalizePageURL(link.String()); normalized != "" {
+			detailURLs[normalized] = true
+		}
+	}
+	isDetail := func(rawURL string) bool {
+		return detailURLs[normalizePageURL(rawURL)]
+	}
+
+	var failed int
+	for _, req := range d.requests {
+		req.Kind = model.SandboxRequestPage
+		if isDetail(req.URL) {
+			req.Kind = model.SandboxRequestDetail
+		}
+		if req.Error != "" || req.Status >= 400 {
+			failed++
+		} else if req.Kind == model.SandboxRequestPage {
+			report.PagesFollowed++
+		}
+		report.Requests = append(report.Requests, req)
+	}
+
+	var listing, detail *diagnosticBody
+	for i := range d.bodies {
+		if isDetail(d.bodies[i].url) {
+			if detail == nil {
+				detail = &d.bodies[i]
+			}
+		} else if listing == nil {
+			listing = &d.bodies[i]
+		}
+	}
+
+	if usesSelectors(config) && listing != nil {
+		report.Selectors, report.Samples = checkListingSelectors(config, listing)
+		if detail != nil {
+			report.Selectors = append(report.Selectors, checkDetailSelectors(config, detail)...)
+		}
+	} else if listing != nil {
+		report.Samples = append(report.Samples, bodySample(listing.url, string(listing.body)))
+	}
+
+	report.Warnings = diagnosticWarnings(report, failed)
+	return report
+}
+
+// usesSelectors reports whether config extracts jobs with its CSS selectors.
+func usesSelectors(config model.SiteScrapingConfig) bool {
+	return (config.ScrapingType == "CSS" || config.ScrapingType == "HEADLESS") && config.JobListItemSelector != nil
+}
+
+// checkListingSelectors counts the matches of the listing selectors on the first
+// listing page. Field selectors count the items they match in.
+func checkListingSelectors(config model.SiteScrapingConfig, page *diagnosticBody) ([]model.SandboxSelectorMatch, []model.SandboxSample) {
+	selectors := []model.SandboxSelectorMatch{}
+	samples := []model.SandboxSample{}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.body))
+	if err != nil {
+		return selectors, append(samples, bodySample(page.url, string(page.body)))
+	}
+
+	items := doc.Find(*config.JobListItemSelector)
+	selectors = append(selectors, model.SandboxSelectorMatch{Field: "job_list_item_selector", Selector: *config.JobListItemSelector, Matches: items.Length(), URL: page.url})
+
+	fields := []struct {
+		name     string
+		selector *string
+	}{
+		{"title_selector", config.TitleSelector},
+		{"link_selector", config.LinkSelector},
+		{"location_selector", config.LocationSelector},
+	}
+	for _, field := range fields {
+		if field.selector == nil || *field.selector == "" {
+			continue
+		}
+		matched := 0
+		items.Each(func(_ int, item *goquery.Selection) {
+			if item.Find(*field.selector).Length() > 0 {
+				matched++
+			}
+		})
+		selectors = append(selectors, model.SandboxSelectorMatch{Field: field.name, Selector: *field.selector, Matches: matched, URL: page.url})
+	}
+	if config.NextPageSelector != nil && *config.NextPageSelector != "" {
+		selectors = append(selectors, model.SandboxSelectorMatch{Field: "next_page_selector", Selector: *config.NextPageSelector, Matches: doc.Find(*config.NextPageSelector).Length(), URL: page.url})
+	}
+
+	items.EachWithBreak(func(i int, item *goquery.Selection) bool {
+		html, err := goquery.OuterHtml(item)
+		if err == nil {
+			samples = append(samples, bodySample(page.url, strings.TrimSpace(html)))
+		}
+		return i+1 < maxItemSamples
+	})
+	if len(samples) == 0 {
+		samples = append(samples, bodySample(page.url, string(page.body)))
+	}
+	return selectors, samples
+}
+
+// checkDetailSelectors counts the matches of the detail selectors on the first
+// detail page.
+func checkDetailSelectors(config model.SiteScrapingConfig, page *diagnosticBody) []model.SandboxSelectorMatch {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.body))
+	if err != nil {
+		return nil
+	}
+	var selectors []model.SandboxSelectorMatch
+	if config.JobDescriptionSelector != nil && *config.JobDescriptionSelector != "" {
+		selectors = append(selectors, model.SandboxSelectorMatch{Field: "job_description_selector", Selector: *config.JobDescriptionSelector, Matches: doc.Find(*config.JobDescriptionSelector).Length(), URL: page.url})
+	}
+	if config.JobRequisitionIdSelector != nil && *config.JobRequisitionIdSelector != "" {
+		selectors = append(selectors, model.SandboxSelectorMatch{Field: "job_requisition_id_selector", Selector: *config.JobRequisitionIdSelector, Matches: doc.Find(*config.JobRequisitionIdSelector).Length(), URL: page.url})
+	}
+	return selectors
+}
+
+// bodySample keeps the start of content, cut on a rune boundary.
+func bodySample(pageURL, content string) model.SandboxSample {
+	if len(content) <= maxSampleBytes {
+		return model.SandboxSample{URL: pageURL, Content: content}
+	}
+	cut := maxSampleBytes
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return model.SandboxSample{URL: pageURL, Content: content[:cut], Truncated: true}
+}
+
+func fillRates(jobs []*model.Job) []model.SandboxFieldFill {
+	fields := []struct {
+		name  string
+		value func(*model.Job) string
+	}{
+		{"title", func(j *model.Job) string { return j.Title }},
+		{"link", func(j *model.Job) string { return j.JobLink }},
+		{"location", func(j *model.Job) string { return j.Location }},
+		{"description", func(j *model.Job) string { return j.Description }},
+		{"requisition_id", func(j *model.Job) string { return j.RequisitionID }},
+	}
+
+	rates := make([]model.SandboxFieldFill, 0, len(fields))
+	for _, field := range fields {
+		fill := model.SandboxFieldFill{Field: field.name, Total: len(jobs)}
+		for _, job := range jobs {
+			if strings.TrimSpace(field.value(job)) != "" {
+				fill.Filled++
+			}
+		}
+		if fill.Total > 0 {
+			fill.Rate = float64(fill.Filled) / float64(fill.Total)
+		}
+		rates = append(rates, fill)
+	}
+	return rates
+}
+
+func duplicateIDs(jobs []*model.Job) []model.SandboxDuplicateID {
+	counts := make(map[string]int)
+	for _, job := range jobs {
+		if id := strings.TrimSpace(job.RequisitionID); id != "" {
+			counts[id]++
+		}
+	}
+	duplicates := []model.SandboxDuplicateID{}
+	for id, count := range counts {
+		if count > 1 {
+			duplicates = append(duplicates, model.SandboxDuplicateID{RequisitionID: id, Count: count})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].RequisitionID < duplicates[j].RequisitionID })
+	return duplicates
+}
+
+func diagnosticWarnings(report model.SandboxDiagnostics, failedRequests int) []string {
+	warnings := []string{}
+	for _, selector := range report.Selectors {
+		if selector.Matches == 0 {
+			warnings = append(warnings, fmt.Sprintf("o seletor %s (%q) não encontrou nada em %s", selector.Field, selector.Selector, selector.URL))
+		}
+	}
+	if report.JobsFound == 0 {
+		warnings = append(warnings, "nenhuma vaga foi extraída")
+	} else {
+		for _, fill := range report.FillRates {
+			if fill.Filled == 0 {
+				warnings = append(warnings, fmt.Sprintf("nenhuma vaga tem o campo %s preenchido", fill.Field))
+			}
+		}
+	}
+	if len(report.DuplicateIDs) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d IDs de requisição aparecem em mais de uma vaga", len(report.DuplicateIDs)))
+	}
+	if failedRequests > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d de %d requisições falharam", failedRequests, len(report.Requests)))
+	}
+	return warnings
+}
@@ -0,0 +1,106 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics_Report(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobs":
+			fmt.Fprint(w, `<html><body><ul>
+<li class="job"><a href="/jobs/1">Go Dev</a></li>
+<li class="job"><a href="/jobs/2">QA</a></li>
+<li class="job"><a href="/jobs/3">SRE</a></li>
+</ul></body></html>`)
+		case "/jobs/3":
+			http.Error(w, "gone", http.StatusGone)
+		default:
+			fmt.Fprint(w, `<html><body><span class="req">R-1</span></body></html>`)
+		}
+	}))
+	defer srv.Close()
+
+	config := cssConfig(srv.URL + "/jobs")
+	config.LocationSelector = strPtr(".location")
+	diagnostics := NewDiagnostics()
+	s, err := NewScraperFactory(config, WithDiagnostics(diagnostics))
+	require.NoError(t, err)
+
+	jobs, err := s.Scrape(context.Background(), config)
+	require.NoError(t, err)
+	report := diagnostics.Report(config, jobs)
+
+	assert.Equal(t, 3, report.JobsFound)
+	assert.Equal(t, 1, report.PagesFollowed)
+	require.Len(t, report.Requests, 4)
+	assert.Equal(t, model.SandboxRequestPage, report.Requests[0].Kind)
+	assert.Equal(t, http.StatusOK, report.Requests[0].Status)
+	var gone int
+	for _, req := range report.Requests[1:] {
+		assert.Equal(t, model.SandboxRequestDetail, req.Kind)
+		if req.Status == http.StatusGone {
+			gone++
+		}
+	}
+	assert.Equal(t, 1, gone)
+
+	assert.Equal(t, []model.SandboxDuplicateID{{RequisitionID: "R-1", Count: 2}}, report.DuplicateIDs)
+	assert.Contains(t, report.FillRates, model.SandboxFieldFill{Field: "requisition_id", Filled: 2, Total: 3, Rate: 2.0 / 3})
+
+	matches := make(map[string]int)
+	for _, selector := range report.Selectors {
+		matches[selector.Field] = selector.Matches
+	}
+	assert.Equal(t, 3, matches["job_list_item_selector"])
+	assert.Equal(t, 3, matches["title_selector"])
+	assert.Equal(t, 0, matches["location_selector"])
+	assert.Equal(t, 0, matches["next_page_selector"])
+	assert.Equal(t, 1, matches["job_requisition_id_selector"])
+
+	require.Len(t, report.Samples, 3)
+	assert.Contains(t, report.Samples[0].Content, "Go Dev")
+	assert.True(t, containsWarning(report.Warnings, "location_selector"))
+	assert.True(t, containsWarning(report.Warnings, "nenhuma vaga tem o campo location"))
+	assert.True(t, containsWarning(report.Warnings, "1 de 4 requisições falharam"))
+}
+
+func TestDiagnostics_ReportSamplesJSONResponses(t *testing.T) {
+	d := NewDiagnostics()
+	body := `{"jobs":[` + strings.Repeat(`{"title":"Dev"},`, 500) + `{"title":"QA"}]}`
+	d.record(model.SandboxRequest{Method: http.MethodGet, URL: "https://api.acme.com/jobs", Status: http.StatusOK}, d.started, []byte(body))
+
+	report := d.Report(model.SiteScrapingConfig{ScrapingType: "API"}, nil)
+
+	require.Len(t, report.Samples, 1)
+	assert.True(t, report.Samples[0].Truncated)
+	assert.Len(t, report.Samples[0].Content, maxSampleBytes)
+	assert.Equal(t, []string{"nenhuma vaga foi extraída"}, report.Warnings)
+}
+
+func TestDiagnostics_NilReportsJobsOnly(t *testing.T) {
+	var d *Diagnostics
+
+	report := d.Report(model.SiteScrapingConfig{}, []*model.Job{{Title: "Dev"}})
+
+	assert.Equal(t, 1, report.JobsFound)
+	assert.Empty(t, report.Requests)
+}
+
+func containsWarning(warnings []string, text string) bool {
+	for _, warning := range warnings {
+		if strings.Contains(warning, text) {
+			return true
+		}
+	}
+	return false
+}
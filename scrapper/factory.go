@@ -1,21 +1,140 @@
-
 package scrapper
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 	"web-scrapper/interfaces"
 	"web-scrapper/model"
 )
 
-func NewScraperFactory(config model.SiteScrapingConfig) (interfaces.Scraper, error) {
+type factoryOptions struct {
+	browserPool  *BrowserPool
+	politeness   *Politeness
+	detailFilter DetailFilter
+	transport    http.RoundTripper
+	recorder     *Recorder
+	stats        *ScrapeStats
+	diagnostics  *Diagnostics
+	clock        func() time.Time
+}
+
+// Option customizes the scrapers built by NewScraperFactory.
+type Option func(*factoryOptions)
+
+// WithBrowserPool makes HEADLESS scrapers borrow browsers from pool.
+func WithBrowserPool(pool *BrowserPool) Option {
+	return func(o *factoryOptions) {
+		o.browserPool = pool
+	}
+}
+
+// WithPoliteness sends every request of the built scraper through p.
+func WithPoliteness(p *Politeness) Option {
+	return func(o *factoryOptions) {
+		o.politeness = p
+	}
+}
+
+// WithTransport sends the HTTP requests of the built scraper through rt instead of
+// the network, e.g. a ReplayTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *factoryOptions) {
+		o.transport = rt
+	}
+}
+
+// WithRecorder saves every response of the built scraper, and the rendered pages of
+// HEADLESS scrapers, into rec's fixture.
+func WithRecorder(rec *Recorder) Option {
+	return func(o *factoryOptions) {
+		o.recorder = rec
+	}
+}
+
+// withClock makes the built scraper render the time variables of its request
+// templates from now instead of the current time.
+func withClock(now func() time.Time) Option {
+	return func(o *factoryOptions) {
+		o.clock = now
+	}
+}
+
+func NewScraperFactory(config model.SiteScrapingConfig, opts ...Option) (interfaces.Scraper, error) {
+	var options factoryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	scraper, err := newScraper(config, options)
+	if err != nil {
+		return nil, err
+	}
+	transport := options.transport
+	clock := options.clock
+	if options.recorder != nil {
+		transport = options.recorder.Transport(transport)
+		if clock == nil {
+			clock = options.recorder.clock
+		}
+		if rendered, ok := scraper.(renderedRecorder); ok {
+			rendered.useRecorder(options.recorder)
+		}
+	}
+	if options.diagnostics != nil {
+		transport = options.diagnostics.Transport(transport)
+		if diagnosed, ok := scraper.(diagnosedScraper); ok {
+			diagnosed.useDiagnostics(options.diagnostics)
+		}
+	}
+	// The transport goes first, so politeness wraps it.
+	if custom, ok := scraper.(transportScraper); ok && transport != nil {
+		custom.useTransport(transport)
+	}
+	if polite, ok := scraper.(politeScraper); ok && options.politeness != nil {
+		polite.usePoliteness(options.politeness)
+	}
+	if reporting, ok := scraper.(statsScraper); ok && options.stats != nil {
+		reporting.useStats(options.stats)
+	}
+	if detail, ok := scraper.(detailScraper); ok && options.detailFilter != nil {
+		detail.useDetailFilter(options.detailFilter)
+	}
+	if clocked, ok := scraper.(clockedScraper); ok && clock != nil {
+		clocked.useClock(clock)
+	}
+	return scraper, nil
+}
+
+func newScraper(config model.SiteScrapingConfig, options factoryOptions) (interfaces.Scraper, error) {
 	switch config.ScrapingType {
 	case "CSS":
 		return NewJobScraper(), nil 
 	case "API":
 		return NewAPIScrapper(), nil
 	case "HEADLESS":
+		if options.browserPool != nil {
+			return NewPooledHeadlessScraper(options.browserPool), nil
+		}
 		return NewHeadlessScraper(), nil
+	case "JSONLD":
+		return NewJSONLDScraper(), nil
+	case "FEED":
+		return NewFeedScraper(), nil
+	case ATSGreenhouse, ATSLever, ATSGupy, ATSWorkday, ATSEightfold:
+		return NewATSScrapper(), nil
 	default:
 		return nil, fmt.Errorf("scrap strategy not found: %s", config.ScrapingType)
 	}
-}
\ No newline at end of file
+}
+
+// transportScraper is implemented by scrapers whose HTTP requests can be sent
+// through a custom transport.
+type transportScraper interface {
+	useTransport(rt http.RoundTripper)
+}
+
+// clockedScraper is implemented by scrapers whose requests depend on the time.
+type clockedScraper interface {
+	useClock(now func() time.Time)
+}
@@ -0,0 +1,497 @@
+package scrapper
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+const (
+	// maxFeedEntries caps how many postings a single feed or sitemap run may yield.
+	maxFeedEntries = 1000
+	// maxChildSitemaps caps how many sitemaps of a sitemap index are fetched.
+	maxChildSitemaps = 50
+	// maxFeedBytes caps the size of a single feed or sitemap document.
+	maxFeedBytes = 50 << 20
+)
+
+// FeedScrapper reads postings from an RSS/Atom feed or an XML sitemap at BaseURL,
+// then visits each posting with the detail selectors of the CSS strategy.
+type FeedScrapper struct {
+	client       *http.Client
+	politeness   *Politeness
+	transport    http.RoundTripper
+	stats        *ScrapeStats
+	detailFilter DetailFilter
+}
+
+func NewFeedScraper() *FeedScrapper {
+	return &FeedScrapper{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (s *FeedScrapper) usePoliteness(p *Politeness) {
+	s.politeness = p
+	s.client.Transport = p.Transport(s.client.Transport)
+}
+
+func (s *FeedScrapper) useStats(stats *ScrapeStats) {
+	s.stats = stats
+}
+
+func (s *FeedScrapper) useDetailFilter(f DetailFilter) {
+	s.detailFilter = f
+}
+
+func (s *FeedScrapper) useTransport(rt http.RoundTripper) {
+	s.transport = rt
+	s.client.Transport = rt
+}
+
+// feedEntry is a posting listed in a feed or sitemap.
+type feedEntry struct {
+	Title       string
+	Link        string
+	GUID        string
+	Description string
+	Published   *time.Time
+	Modified    *time.Time
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	About       string `xml:"about,attr"` // rdf:about, the item's URI in RSS 1.0
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"date"` // dc:date in RSS 1.0
+}
+
+// rssDocument covers RSS 2.0, with items inside <channel>, and RSS 1.0, whose
+// items are siblings of <channel> under <rdf:RDF>.
+type rssDocument struct {
+	Items    []rssItem `xml:"channel>item"`
+	RDFItems []rssItem `xml:"item"`
+}
+
+type atomDocument struct {
+	Entries []struct {
+		Title string `xml:"title"`
+		ID    string `xml:"id"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+type sitemapURLSet struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+type sitemapIndex struct {
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
+func (s *FeedScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("BaseURL is required for FEED site %s", config.SiteName)
+	}
+
+	var pattern *regexp.Regexp
+	if config.URLPattern != nil && *config.URLPattern != "" {
+		var err error
+		pattern, err = regexp.Compile(*config.URLPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url_pattern for site %s: %w", config.SiteName, err)
+		}
+	}
+	var cutoff time.Time
+	if config.MaxAgeDays != nil && *config.MaxAgeDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -*config.MaxAgeDays)
+	}
+
+	if err := s.politeness.Allowed(ctx, config.BaseURL); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.fetchEntries(ctx, config.BaseURL, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed for site %s: %w", config.SiteName, err)
+	}
+
+	entries = filterFeedEntries(entries, pattern, cutoff)
+	if len(entries) > maxFeedEntries {
+		logging.Logger.Warn().Str("site_name", config.SiteName).Int("entries", len(entries)).Int("limit", maxFeedEntries).Msg("Feed has too many entries, truncating")
+		entries = entries[:maxFeedEntries]
+	}
+
+	jobs := make([]*model.Job, 0, len(entries))
+	for _, entry := range entries {
+		jobs = append(jobs, &model.Job{
+			Title:         entry.Title,
+			JobLink:       entry.Link,
+			Description:   htmlToText(entry.Description),
+			RequisitionID: entry.GUID,
+			DatePosted:    entry.Published,
+		})
+	}
+
+	if err := s.fillFromDetailPages(ctx, config, jobs); err != nil {
+		return nil, err
+	}
+
+	var result []*model.Job
+	for _, job := range jobs {
+		if job.Title == "" {
+			logging.Logger.Debug().Str("url", job.JobLink).Msg("Dropping feed entry without title")
+			continue
+		}
+		result = append(result, job)
+	}
+	return result, nil
+}
+
+// fetchEntries downloads a feed or sitemap and returns its postings. Sitemap
+// indexes are followed one level deep.
+func (s *FeedScrapper) fetchEntries(ctx context.Context, feedURL string, depth int) ([]feedEntry, error) {
+	body, err := s.fetch(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := rootElement(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid XML at %s: %w", feedURL, err)
+	}
+
+	switch root {
+	case "rss", "RDF":
+		return parseRSS(body)
+	case "feed":
+		return parseAtom(body)
+	case "urlset":
+		return parseURLSet(body)
+	case "sitemapindex":
+		if depth > 0 {
+			return nil, fmt.Errorf("nested sitemap index at %s", feedURL)
+		}
+		var index sitemapIndex
+		if err := xml.Unmarshal(body, &index); err != nil {
+			return nil, err
+		}
+		var entries []feedEntry
+		for i, child := range index.Sitemaps {
+			if i >= maxChildSitemaps {
+				logging.Logger.Warn().Str("url", feedURL).Int("limit", maxChildSitemaps).Msg("Sitemap index has too many sitemaps, truncating")
+				break
+			}
+			// Skipping a sitemap would make its postings look closed
+			childEntries, err := s.fetchEntries(ctx, strings.TrimSpace(child.Loc), depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read child sitemap %s: %w", strings.TrimSpace(child.Loc), err)
+			}
+			entries = append(entries, childEntries...)
+		}
+		return entries, nil
+	default:
+		return nil, fmt.Errorf("unsupported feed format <%s> at %s", root, feedURL)
+	}
+}
+
+func (s *FeedScrapper) fetch(ctx context.Context, feedURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml;q=0.9, */*;q=0.8")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from %s: %d", feedURL, resp.StatusCode)
+	}
+
+	var reader io.Reader = bufio.NewReader(io.LimitReader(resp.Body, maxFeedBytes))
+	// Sitemaps are often served as raw .xml.gz files.
+	if magic, _ := reader.(*bufio.Reader).Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = io.LimitReader(gz, maxFeedBytes)
+	}
+	return io.ReadAll(reader)
+}
+
+// fillFromDetailPages visits each posting and applies the detail selectors. Pages
+// are only fetched when a detail selector is configured or the entry has no title.
+func (s *FeedScrapper) fillFromDetailPages(ctx context.Context, config model.SiteScrapingConfig, jobs []*model.Job) error {
+	needsDetail := config.JobDescriptionSelector != nil || config.JobRequisitionIdSelector != nil
+
+	c := colly.NewCollector(colly.Async(true))
+	s.politeness.applyTo(c, s.transport)
+	c.Limit(&colly.LimitRule{DomainGlob: "*", Parallelism: 4})
+	c.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"
+
+	var mu sync.Mutex
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		job, ok := e.Request.Ctx.GetAny("job").(*model.Job)
+		if !ok {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		applyDetailSelectors(job, e.DOM, config)
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		logging.Logger.Warn().Err(err).Str("url", r.Request.URL.String()).Msg("Failed to fetch feed entry detail page")
+		s.stats.addDetailFailure()
+	})
+
+	requested := 0
+	for _, job := range jobs {
+		if job.JobLink == "" || (!needsDetail && job.Title != "") || !s.detailFilter.wants(job) {
+			continue
+		}
+		reqCtx := colly.NewContext()
+		reqCtx.Put("job", job)
+		if err := c.Request("GET", job.JobLink, nil, reqCtx, nil); err != nil {
+			logging.Logger.Debug().Err(err).Str("url", job.JobLink).Msg("Skipping feed entry detail page")
+			continue
+		}
+		requested++
+	}
+	if requested == 0 {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("scraping timed out for site %s: %w", config.SiteName, ctx.Err())
+	case <-done:
+		return nil
+	}
+}
+
+func applyDetailSelectors(job *model.Job, doc *goquery.Selection, config model.SiteScrapingConfig) {
+	if job.Title == "" {
+		if config.TitleSelector != nil {
+			job.Title = strings.TrimSpace(doc.Find(*config.TitleSelector).First().Text())
+		}
+		if job.Title == "" {
+			job.Title = strings.TrimSpace(doc.Find("h1").First().Text())
+		}
+		if job.Title == "" {
+			job.Title = strings.TrimSpace(doc.Find("title").First().Text())
+		}
+	}
+
+	if config.LocationSelector != nil && job.Location == "" {
+		job.Location = strings.TrimSpace(doc.Find(*config.LocationSelector).First().Text())
+	}
+
+	if config.JobDescriptionSelector != nil {
+		if description := strings.TrimSpace(doc.Find(*config.JobDescriptionSelector).Text()); description != "" {
+			job.Description = description
+		} else {
+			logging.Logger.Warn().Str("job_title", job.Title).Msg("Failed to extract description HTML")
+		}
+	}
+
+	if config.JobRequisitionIdSelector != nil {
+		if reqID := strings.TrimSpace(doc.Find(*config.JobRequisitionIdSelector).First().Text()); reqID != "" {
+			job.RequisitionID = reqID
+			job.SyntheticID = false
+		} else {
+			logging.Logger.Warn().Str("job_title", job.Title).Msg("Failed to extract requisition ID")
+		}
+	}
+}
+
+// rootElement returns the local name of the document's root element.
+func rootElement(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func parseRSS(body []byte) ([]feedEntry, error) {
+	var doc rssDocument
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	items := append(doc.Items, doc.RDFItems...)
+	entries := make([]feedEntry, 0, len(items))
+	for _, item := range items {
+		link := strings.TrimSpace(item.Link)
+		guid := strings.TrimSpace(item.GUID)
+		if guid == "" {
+			guid = strings.TrimSpace(item.About)
+		}
+		if link == "" && strings.HasPrefix(guid, "http") {
+			link = guid
+		}
+		date := item.PubDate
+		if date == "" {
+			date = item.Date
+		}
+		published := parseFeedDate(date)
+		entries = append(entries, feedEntry{
+			Title:       strings.TrimSpace(item.Title),
+			Link:        link,
+			GUID:        guid,
+			Description: item.Description,
+			Published:   published,
+			Modified:    published,
+		})
+	}
+	return entries, nil
+}
+
+func parseAtom(body []byte) ([]feedEntry, error) {
+	var doc atomDocument
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	entries := make([]feedEntry, 0, len(doc.Entries))
+	for _, entry := range doc.Entries {
+		var link string
+		for _, l := range entry.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = strings.TrimSpace(l.Href)
+				break
+			}
+		}
+		description := entry.Content
+		if strings.TrimSpace(description) == "" {
+			description = entry.Summary
+		}
+		modified := parseFeedDate(entry.Updated)
+		published := parseFeedDate(entry.Published)
+		if modified == nil {
+			modified = published
+		}
+		entries = append(entries, feedEntry{
+			Title:       strings.TrimSpace(entry.Title),
+			Link:        link,
+			GUID:        strings.TrimSpace(entry.ID),
+			Description: description,
+			Published:   published,
+			Modified:    modified,
+		})
+	}
+	return entries, nil
+}
+
+func parseURLSet(body []byte) ([]feedEntry, error) {
+	var set sitemapURLSet
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	if err := decoder.Decode(&set); err != nil {
+		return nil, err
+	}
+	entries := make([]feedEntry, 0, len(set.URLs))
+	for _, u := range set.URLs {
+		entries = append(entries, feedEntry{
+			Link:     strings.TrimSpace(u.Loc),
+			Modified: parseFeedDate(u.LastMod),
+		})
+	}
+	return entries, nil
+}
+
+// filterFeedEntries drops entries without a link, entries whose link does not match
+// pattern, entries older than cutoff (when the feed reports a date) and duplicates.
+func filterFeedEntries(entries []feedEntry, pattern *regexp.Regexp, cutoff time.Time) []feedEntry {
+	seen := make(map[string]bool)
+	var filtered []feedEntry
+	for _, entry := range entries {
+		if entry.Link == "" || seen[entry.Link] {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(entry.Link) {
+			continue
+		}
+		if !cutoff.IsZero() && entry.Modified != nil && entry.Modified.Before(cutoff) {
+			continue
+		}
+		seen[entry.Link] = true
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+}
+
+func parseFeedDate(raw string) *time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t
+		}
+	}
+	return parsePostingDate(raw)
+}
@@ -0,0 +1,189 @@
+package scrapper
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedScrapper_Scrape_RSS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel>
+			<item><title>Dev Go</title><link>https://acme.com/jobs/1</link><guid>job-1</guid><description>&lt;p&gt;Go e Postgres&lt;/p&gt;</description><pubDate>Mon, 02 Mar 2026 10:00:00 -0300</pubDate></item>
+			<item><title>Blog post</title><link>https://acme.com/blog/1</link></item>
+		</channel></rss>`)
+	}))
+	defer srv.Close()
+
+	cfg := model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL, ScrapingType: "FEED", URLPattern: strPtr(`/jobs/`)}
+
+	jobs, err := NewFeedScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "Dev Go", jobs[0].Title)
+	assert.Equal(t, "job-1", jobs[0].RequisitionID)
+	assert.Equal(t, "Go e Postgres", jobs[0].Description)
+	require.NotNil(t, jobs[0].DatePosted)
+	assert.Equal(t, "2026-03-02", jobs[0].DatePosted.Format("2006-01-02"))
+}
+
+func TestFeedScrapper_Scrape_RSS1RDF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?>
+		<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+			<channel rdf:about="https://acme.com/jobs.rdf"><title>Vagas Acme</title><items><rdf:Seq><rdf:li rdf:resource="https://acme.com/jobs/3"/></rdf:Seq></items></channel>
+			<item rdf:about="https://acme.com/jobs/3"><title>Analista de Suporte</title><link>https://acme.com/jobs/3</link><description>Atendimento N2</description><dc:date>2026-03-05T09:00:00-03:00</dc:date></item>
+		</rdf:RDF>`)
+	}))
+	defer srv.Close()
+
+	jobs, err := NewFeedScraper().Scrape(context.Background(), model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL, ScrapingType: "FEED"})
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "Analista de Suporte", jobs[0].Title)
+	assert.Equal(t, "https://acme.com/jobs/3", jobs[0].JobLink)
+	assert.Equal(t, "https://acme.com/jobs/3", jobs[0].RequisitionID)
+	assert.Equal(t, "Atendimento N2", jobs[0].Description)
+	require.NotNil(t, jobs[0].DatePosted)
+	assert.Equal(t, "2026-03-05", jobs[0].DatePosted.Format("2006-01-02"))
+}
+
+func TestFeedScrapper_Scrape_Atom(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom">
+			<entry><title>QA</title><id>urn:job:2</id><link rel="alternate" href="https://acme.com/jobs/2"/><summary>Testes</summary><updated>2026-03-01T12:00:00Z</updated></entry>
+		</feed>`)
+	}))
+	defer srv.Close()
+
+	jobs, err := NewFeedScraper().Scrape(context.Background(), model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL})
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "https://acme.com/jobs/2", jobs[0].JobLink)
+	assert.Equal(t, "urn:job:2", jobs[0].RequisitionID)
+}
+
+func TestFeedScrapper_Scrape_SitemapIndexWithDetailSelectors(t *testing.T) {
+	recent := time.Now().Format("2006-01-02")
+	mux := http.NewServeMux()
+	var srvURL string
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/sitemap-jobs.xml.gz</loc></sitemap></sitemapindex>`, srvURL)
+	})
+	mux.HandleFunc("/sitemap-jobs.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		fmt.Fprintf(gz, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<url><loc>%[1]s/jobs/10</loc><lastmod>%[2]s</lastmod></url>
+			<url><loc>%[1]s/jobs/11</loc><lastmod>2020-01-01</lastmod></url>
+			<url><loc>%[1]s/about</loc><lastmod>%[2]s</lastmod></url>
+		</urlset>`, srvURL, recent)
+	})
+	mux.HandleFunc("/jobs/10", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Acme</title></head><body><h1>Analista de Dados</h1><div class="desc">SQL e Python</div><span class="req">R-10</span></body></html>`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	srvURL = srv.URL
+
+	maxAge := 30
+	cfg := model.SiteScrapingConfig{
+		SiteName:                 "Acme",
+		BaseURL:                  srv.URL + "/sitemap.xml",
+		ScrapingType:             "FEED",
+		URLPattern:               strPtr(`/jobs/\d+$`),
+		MaxAgeDays:               &maxAge,
+		JobDescriptionSelector:   strPtr("div.desc"),
+		JobRequisitionIdSelector: strPtr("span.req"),
+	}
+
+	jobs, err := NewFeedScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "Analista de Dados", jobs[0].Title)
+	assert.Equal(t, "SQL e Python", jobs[0].Description)
+	assert.Equal(t, "R-10", jobs[0].RequisitionID)
+}
+
+func TestFeedScrapper_Scrape_DetailFilterSkipsDetailPages(t *testing.T) {
+	var detailHits sync.Map
+	mux := http.NewServeMux()
+	var srvURL string
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%[1]s/jobs/1</loc></url><url><loc>%[1]s/jobs/2</loc></url></urlset>`, srvURL)
+	})
+	mux.HandleFunc("/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		detailHits.Store(r.URL.Path, true)
+		fmt.Fprint(w, `<html><body><h1>Dev Go</h1><span class="req">R-2</span></body></html>`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	srvURL = srv.URL
+	config := model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL + "/sitemap.xml", ScrapingType: "FEED", JobRequisitionIdSelector: strPtr("span.req")}
+	s, err := NewScraperFactory(config, WithDetailFilter(func(job *model.Job) bool {
+		if job.JobLink == srvURL+"/jobs/1" {
+			job.RequisitionID = "R-1"
+			job.Title = "Vaga conhecida"
+			return false
+		}
+		return true
+	}))
+	require.NoError(t, err)
+
+	jobs, err := s.Scrape(context.Background(), config)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.ElementsMatch(t, []string{"R-1", "R-2"}, []string{jobs[0].RequisitionID, jobs[1].RequisitionID})
+	_, fetched := detailHits.Load("/jobs/1")
+	assert.False(t, fetched)
+}
+
+func TestFeedScrapper_Scrape_FailingChildSitemapFailsTheScrape(t *testing.T) {
+	mux := http.NewServeMux()
+	var srvURL string
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%[1]s/sitemap-1.xml</loc></sitemap><sitemap><loc>%[1]s/sitemap-2.xml</loc></sitemap></sitemapindex>`, srvURL)
+	})
+	mux.HandleFunc("/sitemap-1.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%s/jobs/1</loc></url></urlset>`, srvURL)
+	})
+	mux.HandleFunc("/sitemap-2.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	srvURL = srv.URL
+
+	jobs, err := NewFeedScraper().Scrape(context.Background(), model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL + "/sitemap.xml"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sitemap-2.xml")
+	assert.Nil(t, jobs)
+}
+
+func TestFeedScrapper_Scrape_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>not a feed</body></html>`)
+	}))
+	defer srv.Close()
+
+	_, err := NewFeedScraper().Scrape(context.Background(), model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL})
+	assert.ErrorContains(t, err, "unsupported feed format")
+
+	_, err = NewFeedScraper().Scrape(context.Background(), model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL, URLPattern: strPtr("(")})
+	assert.ErrorContains(t, err, "invalid url_pattern")
+}
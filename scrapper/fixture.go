@@ -0,0 +1,434 @@
+package scrapper
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+)
+
+// A fixture directory holds a recorded scrape:
+//
+//	config.json   the SiteScrapingConfig that was scraped
+//	index.json    one entry per recorded response or rendered page
+//	bodies/       the response bodies, one file per entry
+//	golden.json   the jobs the scrape is expected to extract
+const (
+	fixtureConfigFile = "config.json"
+	fixtureIndexFile  = "index.json"
+	fixtureBodiesDir  = "bodies"
+	fixtureGoldenFile = "golden.json"
+)
+
+type fixtureIndex struct {
+	// RecordedAt is the clock the scrape ran with. Replays render {{.Today}} and
+	// {{.Now}} from it, so requests built from them match the recorded ones.
+	RecordedAt *time.Time     `json:"recorded_at,omitempty"`
+	Entries    []fixtureEntry `json:"entries"`
+}
+
+type fixtureEntry struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	BodyHash    string `json:"request_body_sha256,omitempty"` // requests with a body (API POSTs) are matched on it too
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	File        string `json:"file"`
+	// Rendered marks HTML captured from the headless browser rather than an HTTP response.
+	Rendered bool `json:"rendered,omitempty"`
+}
+
+func (e fixtureEntry) key() string {
+	return fixtureKey(e.Method, e.URL, e.BodyHash)
+}
+
+func fixtureKey(method, rawURL, bodyHash string) string {
+	u := normalizePageURL(rawURL)
+	if u == "" {
+		u = rawURL
+	}
+	return strings.ToUpper(method) + " " + u + " " + bodyHash
+}
+
+// Recorder captures the responses of a live scrape into a fixture directory.
+// Call Close to write the index once the scrape is done.
+type Recorder struct {
+	dir string
+	now time.Time
+
+	mu      sync.Mutex
+	entries []fixtureEntry
+}
+
+// NewRecorder records into dir, creating it if needed. Bodies recorded earlier in
+// dir are left in place but no longer indexed.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Join(dir, fixtureBodiesDir), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating fixture dir %s: %w", dir, err)
+	}
+	return &Recorder{dir: dir, now: time.Now().Truncate(time.Second)}, nil
+}
+
+// clock is the fixed time scrapes being recorded run with.
+func (r *Recorder) clock() time.Time {
+	return r.now
+}
+
+// Transport wraps base so every response passing through it is recorded.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingTransport{recorder: r, base: base}
+}
+
+// Close writes the fixture index.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return writeJSONFile(filepath.Join(r.dir, fixtureIndexFile), fixtureIndex{RecordedAt: &r.now, Entries: r.entries})
+}
+
+func (r *Recorder) record(entry fixtureEntry, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.File = fmt.Sprintf("%s/%03d%s", fixtureBodiesDir, len(r.entries)+1, bodyExtension(entry.ContentType))
+	if err := os.WriteFile(filepath.Join(r.dir, entry.File), body, 0o644); err != nil {
+		return fmt.Errorf("error writing fixture body for %s: %w", entry.URL, err)
+	}
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// recordRendered saves the HTML the headless browser rendered for pageURL. A nil
+// *Recorder records nothing.
+func (r *Recorder) recordRendered(pageURL, html string) {
+	if r == nil {
+		return
+	}
+	entry := fixtureEntry{Method: http.MethodGet, URL: pageURL, Status: http.StatusOK, ContentType: "text/html; charset=utf-8", Rendered: true}
+	if err := r.record(entry, []byte(html)); err != nil {
+		// Recording must not fail the scrape
+		logging.Logger.Warn().Err(err).Str("url", pageURL).Msg("Failed to record rendered page")
+	}
+}
+
+// renderedRecorder is implemented by scrapers that render pages in a browser and
+// can record the result.
+type renderedRecorder interface {
+	useRecorder(rec *Recorder)
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bodyHash, err := requestBodyHash(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := fixtureEntry{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		BodyHash:    bodyHash,
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if err := t.recorder.record(entry, body); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// requestBodyHash hashes req's body, if any, and puts the body back.
+func requestBodyHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 {
+		return "", nil
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ReplayTransport answers requests from a recorded fixture instead of the
+// network. Requests the fixture has no response for fail.
+type ReplayTransport struct {
+	dir        string
+	recordedAt *time.Time
+	responses  map[string]fixtureEntry
+}
+
+// NewReplayTransport loads the fixture in dir. Rendered headless pages are not
+// served, since they were never HTTP responses.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	var index fixtureIndex
+	if err := readJSONFile(filepath.Join(dir, fixtureIndexFile), &index); err != nil {
+		return nil, err
+	}
+	t := &ReplayTransport{dir: dir, recordedAt: index.RecordedAt, responses: make(map[string]fixtureEntry, len(index.Entries))}
+	for _, entry := range index.Entries {
+		if entry.Rendered {
+			continue
+		}
+		// The first response recorded for a request wins, as pages are usually fetched once
+		if _, ok := t.responses[entry.key()]; !ok {
+			t.responses[entry.key()] = entry
+		}
+	}
+	return t, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bodyHash, err := requestBodyHash(req)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := t.responses[fixtureKey(req.Method, req.URL.String(), bodyHash)]
+	if !ok {
+		return nil, fmt.Errorf("fixture %s has no response for %s %s", t.dir, req.Method, req.URL)
+	}
+	body, err := os.ReadFile(filepath.Join(t.dir, entry.File))
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture body %s: %w", entry.File, err)
+	}
+
+	header := make(http.Header)
+	if entry.ContentType != "" {
+		header.Set("Content-Type", entry.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// WriteFixtureConfig saves the config a fixture was recorded with.
+func WriteFixtureConfig(dir string, config model.SiteScrapingConfig) error {
+	return writeJSONFile(filepath.Join(dir, fixtureConfigFile), config)
+}
+
+// ReplayFixture runs the scraper of the fixture's config against its recorded
+// responses and returns the jobs in a stable order. The clock is pinned to the
+// recording time, so time template variables render as they did then. HEADLESS
+// fixtures cannot be replayed.
+func ReplayFixture(ctx context.Context, dir string) ([]*model.Job, error) {
+	var config model.SiteScrapingConfig
+	if err := readJSONFile(filepath.Join(dir, fixtureConfigFile), &config); err != nil {
+		return nil, err
+	}
+	if config.ScrapingType == "HEADLESS" {
+		return nil, fmt.Errorf("fixture %s: HEADLESS scrapes can be recorded but not replayed", dir)
+	}
+
+	transport, err := NewReplayTransport(dir)
+	if err != nil {
+		return nil, err
+	}
+	opts := []Option{WithTransport(transport)}
+	if transport.recordedAt != nil {
+		recordedAt := *transport.recordedAt
+		opts = append(opts, withClock(func() time.Time { return recordedAt }))
+	}
+	scraper, err := NewScraperFactory(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := scraper.Scrape(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	SortJobs(jobs)
+	return jobs, nil
+}
+
+// SortJobs orders jobs by requisition ID, link and title, so scrapes can be
+// compared regardless of the order pages were fetched in.
+func SortJobs(jobs []*model.Job) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		a, b := jobs[i], jobs[j]
+		if a.RequisitionID != b.RequisitionID {
+			return a.RequisitionID < b.RequisitionID
+		}
+		if a.JobLink != b.JobLink {
+			return a.JobLink < b.JobLink
+		}
+		return a.Title < b.Title
+	})
+}
+
+// WriteGolden saves jobs as the fixture's expected result.
+func WriteGolden(dir string, jobs []*model.Job) error {
+	if jobs == nil {
+		jobs = []*model.Job{}
+	}
+	return writeJSONFile(filepath.Join(dir, fixtureGoldenFile), jobs)
+}
+
+// DiffGolden compares jobs against the fixture's golden file and describes every
+// difference, one per line. No lines means the scrape matches.
+func DiffGolden(dir string, jobs []*model.Job) ([]string, error) {
+	var golden []*model.Job
+	if err := readJSONFile(filepath.Join(dir, fixtureGoldenFile), &golden); err != nil {
+		return nil, err
+	}
+	return diffJobs(golden, jobs)
+}
+
+// diffJobs matches jobs by requisition ID (or link, then title) and compares their
+// JSON fields.
+func diffJobs(want, got []*model.Job) ([]string, error) {
+	wantByKey, wantKeys, err := jobsByDiffKey(want)
+	if err != nil {
+		return nil, err
+	}
+	gotByKey, gotKeys, err := jobsByDiffKey(got)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for _, key := range wantKeys {
+		gotFields, ok := gotByKey[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("- missing job %s", key))
+			continue
+		}
+		wantFields := wantByKey[key]
+		for _, field := range unionKeys(wantFields, gotFields) {
+			if wantFields[field] != gotFields[field] {
+				diffs = append(diffs, fmt.Sprintf("~ job %s: %s %s -> %s", key, field, wantFields[field], gotFields[field]))
+			}
+		}
+	}
+	for _, key := range gotKeys {
+		if _, ok := wantByKey[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("+ unexpected job %s", key))
+		}
+	}
+	return diffs, nil
+}
+
+func jobsByDiffKey(jobs []*model.Job) (map[string]map[string]string, []string, error) {
+	byKey := make(map[string]map[string]string, len(jobs))
+	var keys []string
+	for _, job := range jobs {
+		key := job.RequisitionID
+		if key == "" {
+			key = job.JobLink
+		}
+		if key == "" {
+			key = job.Title
+		}
+		raw, err := json.Marshal(job)
+		if err != nil {
+			return nil, nil, err
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, nil, err
+		}
+		flat := make(map[string]string, len(fields))
+		for name, value := range fields {
+			flat[name] = string(value)
+		}
+		if _, dup := byKey[key]; !dup {
+			keys = append(keys, key)
+		}
+		byKey[key] = flat
+	}
+	return byKey, keys, nil
+}
+
+func unionKeys(a, b map[string]string) []string {
+	seen := make(map[string]bool, len(a))
+	var keys []string
+	for _, m := range []map[string]string{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func bodyExtension(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/html":
+		return ".html"
+	case strings.HasSuffix(mediaType, "json"):
+		return ".json"
+	case strings.HasSuffix(mediaType, "xml"):
+		return ".xml"
+	default:
+		return ".txt"
+	}
+}
+
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package scrapper
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of testdata/fixtures")
+
+// TestFixtures replays every recorded site in testdata/fixtures and compares the
+// extracted jobs with its golden file. Run with -update after an intended change.
+func TestFixtures(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, dirs)
+
+	for _, dir := range dirs {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			jobs, err := ReplayFixture(context.Background(), dir)
+			require.NoError(t, err)
+
+			if *updateGolden {
+				require.NoError(t, WriteGolden(dir, jobs))
+				return
+			}
+			diffs, err := DiffGolden(dir, jobs)
+			require.NoError(t, err)
+			assert.Empty(t, diffs)
+		})
+	}
+}
+
+func TestRecorder_RecordedScrapeReplaysOffline(t *testing.T) {
+	srv, _ := listingServer(t, 2, func(page int) string {
+		if page >= 2 {
+			return ""
+		}
+		return "/jobs?page=2"
+	})
+	config := cssConfig(srv.URL + "/jobs")
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(dir)
+	require.NoError(t, err)
+	s, err := NewScraperFactory(config, WithRecorder(rec))
+	require.NoError(t, err)
+	recorded, err := s.Scrape(context.Background(), config)
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+	require.NoError(t, WriteFixtureConfig(dir, config))
+	SortJobs(recorded)
+	require.NoError(t, WriteGolden(dir, recorded))
+	srv.Close()
+
+	replayed, err := ReplayFixture(context.Background(), dir)
+
+	require.NoError(t, err)
+	assert.Len(t, replayed, 4)
+	diffs, err := DiffGolden(dir, replayed)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
+
+func TestRecorder_ReplayRendersTimeVariablesAtRecordingTime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[{"title":"Go Dev","url":"https://acme.com/1"}]}`)
+	}))
+	config := apiConfig(srv.URL+"/jobs?since={{.Today}}", `{"jobs_array_path":"items","title_path":"title","link_path":"url"}`)
+	config.APIMethod = strPtr("POST")
+	config.APIPayloadTemplate = strPtr(`{"postedAfter":{{json .Now}}}`)
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(dir)
+	require.NoError(t, err)
+	// A recording from another day: replaying with the current time would
+	// render a different URL and payload.
+	rec.now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s, err := NewScraperFactory(config, WithRecorder(rec))
+	require.NoError(t, err)
+	_, err = s.Scrape(context.Background(), config)
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+	require.NoError(t, WriteFixtureConfig(dir, config))
+	srv.Close()
+
+	replayed, err := ReplayFixture(context.Background(), dir)
+
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, "Go Dev", replayed[0].Title)
+}
+
+func TestReplayTransport_UnknownRequestFails(t *testing.T) {
+	transport, err := NewReplayTransport(filepath.Join("testdata", "fixtures", "css-acme"))
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("https://careers.acme.example/vagas?page=2#topo")
+	require.NoError(t, err, "fragment is ignored when matching")
+	resp.Body.Close()
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	_, err = client.Get("https://careers.acme.example/outra")
+	assert.ErrorContains(t, err, "has no response for GET https://careers.acme.example/outra")
+}
+
+func TestDiffJobs(t *testing.T) {
+	want := []*model.Job{
+		{RequisitionID: "1", Title: "Go Dev"},
+		{RequisitionID: "2", Title: "QA"},
+	}
+	got := []*model.Job{
+		{RequisitionID: "1", Title: "Go Developer"},
+		{RequisitionID: "3", Title: "SRE"},
+	}
+
+	diffs, err := diffJobs(want, got)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`~ job 1: title "Go Dev" -> "Go Developer"`,
+		"- missing job 2",
+		"+ unexpected job 3",
+	}, diffs)
+}
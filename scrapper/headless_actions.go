@@ -0,0 +1,267 @@
+package scrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"web-scrapper/logging"
+
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+)
+
+const (
+	ActionClick   = "click"   // click selector; with repeat, keeps clicking ("Ver mais vagas") while new items appear
+	ActionScroll  = "scroll"  // scroll to the bottom until no new items appear, at most repeat times
+	ActionWait    = "wait"    // wait until selector is visible
+	ActionDismiss = "dismiss" // click selector only if it is present (cookie/consent banners)
+	ActionType    = "type"    // type text into selector, pressing Enter when submit is set
+	ActionSleep   = "sleep"   // pause for delay_ms
+
+	defaultActionTimeout = 10 * time.Second
+	defaultActionRepeat  = 10
+	maxActionRepeat      = 50
+	maxHeadlessActions   = 20
+	// itemsSettleTimeout is how long click/scroll waits for new list items to render.
+	itemsSettleTimeout = 5 * time.Second
+	// actionsBudget bounds the whole action script of a scrape.
+	actionsBudget = 2 * time.Minute
+)
+
+// HeadlessAction is one declarative step run on the listing page before extraction.
+// Actions are stored as a JSON array in SiteScrapingConfig.HeadlessActions, e.g.
+// [{"type":"dismiss","selector":"#onetrust-accept-btn-handler"},{"type":"click","selector":"button.load-more","repeat":20}].
+type HeadlessAction struct {
+	Type      string `json:"type"`
+	Selector  string `json:"selector,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Submit    bool   `json:"submit,omitempty"`
+	Repeat    int    `json:"repeat,omitempty"`
+	DelayMs   int    `json:"delay_ms,omitempty"`   // pause after each step
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // per-step timeout, default 10s
+	Optional  bool   `json:"optional,omitempty"`   // failures are logged instead of aborting the scrape
+}
+
+// ParseHeadlessActions decodes and validates a site's action list. A nil or empty
+// value yields no actions.
+func ParseHeadlessActions(raw *string) ([]HeadlessAction, error) {
+	if raw == nil || *raw == "" || *raw == "null" {
+		return nil, nil
+	}
+	var actions []HeadlessAction
+	if err := json.Unmarshal([]byte(*raw), &actions); err != nil {
+		return nil, fmt.Errorf("invalid headless_actions: %w", err)
+	}
+	if len(actions) > maxHeadlessActions {
+		return nil, fmt.Errorf("invalid headless_actions: at most %d actions are allowed", maxHeadlessActions)
+	}
+	for i, a := range actions {
+		if err := a.validate(); err != nil {
+			return nil, fmt.Errorf("invalid headless_actions[%d]: %w", i, err)
+		}
+	}
+	return actions, nil
+}
+
+func (a HeadlessAction) validate() error {
+	switch a.Type {
+	case ActionClick, ActionWait, ActionDismiss:
+		if a.Selector == "" {
+			return fmt.Errorf("%s requires selector", a.Type)
+		}
+	case ActionType:
+		if a.Selector == "" || a.Text == "" {
+			return fmt.Errorf("type requires selector and text")
+		}
+	case ActionScroll:
+	case ActionSleep:
+		if a.DelayMs <= 0 {
+			return fmt.Errorf("sleep requires delay_ms")
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+	if a.Repeat < 0 || a.Repeat > maxActionRepeat {
+		return fmt.Errorf("repeat must be between 0 and %d", maxActionRepeat)
+	}
+	return nil
+}
+
+func (a HeadlessAction) timeout() time.Duration {
+	if a.TimeoutMs > 0 {
+		return time.Duration(a.TimeoutMs) * time.Millisecond
+	}
+	return defaultActionTimeout
+}
+
+func (a HeadlessAction) delay() time.Duration {
+	return time.Duration(a.DelayMs) * time.Millisecond
+}
+
+// runHeadlessActions executes actions in order on the listing tab. itemSelector is
+// the site's JobListItemSelector, used to detect when click/scroll stop loading jobs.
+func runHeadlessActions(ctx context.Context, actions []HeadlessAction, itemSelector string) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, actionsBudget)
+	defer cancel()
+
+	for i, a := range actions {
+		err := runHeadlessAction(ctx, a, itemSelector)
+		if err == nil {
+			continue
+		}
+		if a.Optional || a.Type == ActionDismiss {
+			logging.Logger.Debug().Err(err).Int("action", i).Str("type", a.Type).Msg("Optional headless action failed, continuing")
+			continue
+		}
+		return fmt.Errorf("headless action %d (%s %s) failed: %w", i, a.Type, a.Selector, err)
+	}
+	return nil
+}
+
+func runHeadlessAction(ctx context.Context, a HeadlessAction, itemSelector string) error {
+	switch a.Type {
+	case ActionWait:
+		return runWithTimeout(ctx, a.timeout(), chromedp.WaitVisible(a.Selector, chromedp.ByQuery))
+
+	case ActionDismiss:
+		present, err := selectorPresent(ctx, a.Selector)
+		if err != nil || !present {
+			return err
+		}
+		if err := runWithTimeout(ctx, a.timeout(), chromedp.Click(a.Selector, chromedp.ByQuery, chromedp.NodeVisible)); err != nil {
+			return err
+		}
+		return sleep(ctx, a.delay())
+
+	case ActionType:
+		steps := chromedp.Tasks{
+			chromedp.WaitVisible(a.Selector, chromedp.ByQuery),
+			chromedp.Clear(a.Selector, chromedp.ByQuery),
+			chromedp.SendKeys(a.Selector, a.Text, chromedp.ByQuery),
+		}
+		if a.Submit {
+			steps = append(steps, chromedp.SendKeys(a.Selector, kb.Enter, chromedp.ByQuery))
+		}
+		if err := runWithTimeout(ctx, a.timeout(), steps); err != nil {
+			return err
+		}
+		return sleep(ctx, a.delay())
+
+	case ActionSleep:
+		return sleep(ctx, a.delay())
+
+	case ActionClick:
+		if a.Repeat <= 1 {
+			if err := runWithTimeout(ctx, a.timeout(), chromedp.Click(a.Selector, chromedp.ByQuery, chromedp.NodeVisible)); err != nil {
+				return err
+			}
+			return sleep(ctx, a.delay())
+		}
+		return repeatUntilNoNewItems(ctx, a, itemSelector, func() error {
+			present, err := selectorPresent(ctx, a.Selector)
+			if err != nil {
+				return err
+			}
+			if !present {
+				return errNothingToLoad
+			}
+			return runWithTimeout(ctx, a.timeout(), chromedp.Click(a.Selector, chromedp.ByQuery, chromedp.NodeVisible))
+		})
+
+	case ActionScroll:
+		return repeatUntilNoNewItems(ctx, a, itemSelector, func() error {
+			return runWithTimeout(ctx, a.timeout(), chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight)`, nil))
+		})
+	}
+	return fmt.Errorf("unknown action type %q", a.Type)
+}
+
+var errNothingToLoad = errors.New("nothing left to load")
+
+// repeatUntilNoNewItems runs step until the number of list items stops growing,
+// the step reports nothing to load, or repeat (default 10) is reached.
+func repeatUntilNoNewItems(ctx context.Context, a HeadlessAction, itemSelector string, step func() error) error {
+	repeat := a.Repeat
+	if repeat <= 0 {
+		repeat = defaultActionRepeat
+	}
+
+	count, err := countItems(ctx, itemSelector)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < repeat; i++ {
+		if err := step(); err != nil {
+			if errors.Is(err, errNothingToLoad) {
+				return nil
+			}
+			// The first step must work; later failures just mean the list is exhausted.
+			if i == 0 {
+				return err
+			}
+			return nil
+		}
+		newCount, err := waitForMoreItems(ctx, itemSelector, count)
+		if err != nil {
+			return err
+		}
+		if newCount <= count {
+			return nil
+		}
+		count = newCount
+		if err := sleep(ctx, a.delay()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func waitForMoreItems(ctx context.Context, itemSelector string, previous int) (int, error) {
+	deadline := time.Now().Add(itemsSettleTimeout)
+	for {
+		count, err := countItems(ctx, itemSelector)
+		if err != nil || count > previous || time.Now().After(deadline) {
+			return count, err
+		}
+		if err := sleep(ctx, 250*time.Millisecond); err != nil {
+			return count, err
+		}
+	}
+}
+
+func countItems(ctx context.Context, selector string) (int, error) {
+	quoted, _ := json.Marshal(selector)
+	var count int
+	err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(`document.querySelectorAll(%s).length`, quoted), &count))
+	return count, err
+}
+
+func selectorPresent(ctx context.Context, selector string) (bool, error) {
+	quoted, _ := json.Marshal(selector)
+	var present bool
+	err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s) !== null`, quoted), &present))
+	return present, err
+}
+
+func runWithTimeout(ctx context.Context, timeout time.Duration, action chromedp.Action) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return chromedp.Run(ctx, action)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
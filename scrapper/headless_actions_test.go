@@ -0,0 +1,110 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeadlessActions(t *testing.T) {
+	actions, err := ParseHeadlessActions(strPtr(`[
+		{"type":"dismiss","selector":"#cookie-accept"},
+		{"type":"type","selector":"input[name=q]","text":"desenvolvedor","submit":true},
+		{"type":"click","selector":"button.load-more","repeat":20,"delay_ms":500},
+		{"type":"scroll","repeat":5},
+		{"type":"wait","selector":"li.job","timeout_ms":3000},
+		{"type":"sleep","delay_ms":1000}
+	]`))
+
+	require.NoError(t, err)
+	require.Len(t, actions, 6)
+	assert.Equal(t, ActionClick, actions[2].Type)
+	assert.Equal(t, 20, actions[2].Repeat)
+}
+
+func TestParseHeadlessActions_Empty(t *testing.T) {
+	for _, raw := range []*string{nil, strPtr(""), strPtr("null"), strPtr("[]")} {
+		actions, err := ParseHeadlessActions(raw)
+		assert.NoError(t, err)
+		assert.Empty(t, actions)
+	}
+}
+
+func TestParseHeadlessActions_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":           `{"type":"click"`,
+		"unknown type":       `[{"type":"hover","selector":"a"}]`,
+		"click w/o selector": `[{"type":"click"}]`,
+		"type w/o text":      `[{"type":"type","selector":"input"}]`,
+		"sleep w/o delay":    `[{"type":"sleep"}]`,
+		"repeat too large":   `[{"type":"scroll","repeat":500}]`,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseHeadlessActions(strPtr(raw))
+			assert.ErrorContains(t, err, "invalid headless_actions")
+		})
+	}
+}
+
+func TestHeadlessScraper_Scrape_RejectsInvalidActions(t *testing.T) {
+	cfg := model.SiteScrapingConfig{
+		SiteName:            "Acme",
+		BaseURL:             "http://localhost",
+		JobListItemSelector: strPtr("li"),
+		TitleSelector:       strPtr("a"),
+		LinkSelector:        strPtr("a"),
+		LinkAttribute:       strPtr("href"),
+		HeadlessActions:     strPtr(`[{"type":"teleport"}]`),
+	}
+
+	_, err := NewHeadlessScraper().Scrape(context.Background(), cfg)
+
+	assert.ErrorContains(t, err, "invalid headless_actions")
+}
+
+func TestHeadlessScraper_Scrape_LoadMoreAction(t *testing.T) {
+	if !chromeAvailable() {
+		t.Skip("Chrome not installed")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>
+			<div id="consent"><button id="accept" onclick="document.getElementById('consent').remove()">OK</button></div>
+			<ul id="jobs"><li><a href="/jobs/1">Job 1</a></li></ul>
+			<button id="more">Ver mais vagas</button>
+			<script>
+				var next = 2;
+				document.getElementById('more').onclick = function() {
+					setTimeout(function() {
+						var li = document.createElement('li');
+						li.innerHTML = '<a href="/jobs/' + next + '">Job ' + next + '</a>';
+						document.getElementById('jobs').appendChild(li);
+						if (++next > 3) document.getElementById('more').remove();
+					}, 100);
+				};
+			</script>
+		</body></html>`)
+	}))
+	defer srv.Close()
+
+	cfg := model.SiteScrapingConfig{
+		SiteName:            "Acme",
+		BaseURL:             srv.URL,
+		JobListItemSelector: strPtr("#jobs li"),
+		TitleSelector:       strPtr("a"),
+		LinkSelector:        strPtr("a"),
+		LinkAttribute:       strPtr("href"),
+		HeadlessActions:     strPtr(`[{"type":"dismiss","selector":"#accept"},{"type":"click","selector":"#more","repeat":10}]`),
+	}
+
+	jobs, err := NewHeadlessScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+}
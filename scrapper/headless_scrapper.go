@@ -20,40 +20,96 @@ const (
 	detailPageTimeout = 30 * time.Second
 )
 
-type HeadlessScraper struct{}
+type HeadlessScraper struct {
+	pool         *BrowserPool
+	politeness   *Politeness
+	detailFilter DetailFilter
+	recorder     *Recorder
+	stats        *ScrapeStats
+	diagnostics  *Diagnostics
+}
 
 func NewHeadlessScraper() *HeadlessScraper {
 	return &HeadlessScraper{}
 }
 
-func (s *HeadlessScraper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error) {
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-crash-reporter", true),
-		chromedp.Flag("disable-extensions", true),
-		chromedp.Flag("disable-software-rasterizer", true),
-	)
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
+// NewPooledHeadlessScraper borrows browsers from pool instead of launching Chrome per scrape.
+func NewPooledHeadlessScraper(pool *BrowserPool) *HeadlessScraper {
+	return &HeadlessScraper{pool: pool}
+}
+
+func (s *HeadlessScraper) usePoliteness(p *Politeness) {
+	s.politeness = p
+}
+
+func (s *HeadlessScraper) useDetailFilter(f DetailFilter) {
+	s.detailFilter = f
+}
+
+func (s *HeadlessScraper) useRecorder(rec *Recorder) {
+	s.recorder = rec
+}
+
+func (s *HeadlessScraper) useStats(stats *ScrapeStats) {
+	s.stats = stats
+}
 
-	taskCtx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, args ...any) {
+func (s *HeadlessScraper) useDiagnostics(d *Diagnostics) {
+	s.diagnostics = d
+}
+
+// browser returns a tab to scrape with and a func that gives the browser back.
+// Detail pages are opened as extra tabs of the same browser.
+func (s *HeadlessScraper) browser(ctx context.Context) (context.Context, func(), error) {
+	if s.pool != nil {
+		lease, err := s.pool.Acquire(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return lease.Ctx, lease.Release, nil
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, headlessAllocatorOptions()...)
+	tabCtx, tabCancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(func(format string, args ...any) {
 		logging.Logger.Debug().Msgf(format, args...)
 	}))
-	defer cancel()
-
-	// Apply a strict timeout so WaitVisible cannot hang indefinitely
-	taskCtx, timeoutCancel := context.WithTimeout(taskCtx, pageLoadTimeout)
-	defer timeoutCancel()
+	return tabCtx, func() {
+		tabCancel()
+		allocCancel()
+	}, nil
+}
 
+func (s *HeadlessScraper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error) {
 	if config.JobListItemSelector == nil || config.TitleSelector == nil || config.LinkSelector == nil || config.LinkAttribute == nil {
 		return nil, fmt.Errorf("required selectors (JobListItemSelector, TitleSelector, LinkSelector, LinkAttribute) must not be nil for headless scraping of %s", config.SiteName)
 	}
 
-	var htmlContent string
-	err := chromedp.Run(taskCtx,
+	actions, err := ParseHeadlessActions(config.HeadlessActions)
+	if err != nil {
+		return nil, fmt.Errorf("%w (site %s)", err, config.SiteName)
+	}
+
+	if err := s.politeness.Before(ctx, config.BaseURL); err != nil {
+		return nil, err
+	}
+
+	browserCtx, release, err := s.browser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no headless browser available for %s: %w", config.SiteName, err)
+	}
+	defer release()
+
+	// Open the tab without a deadline, so the timeouts below only bound each step
+	if err := chromedp.Run(browserCtx); err != nil {
+		return nil, fmt.Errorf("failed to open headless tab for %s: %w", config.SiteName, err)
+	}
+
+	started := time.Now()
+	// Apply a strict timeout so WaitVisible cannot hang indefinitely
+	taskCtx, timeoutCancel := context.WithTimeout(browserCtx, pageLoadTimeout)
+	defer timeoutCancel()
+
+	err = chromedp.Run(taskCtx,
 		network.Enable(),
 		network.SetBlockedURLs([]string{
 			"*.png", "*.jpg", "*.jpeg", "*.gif", "*.svg", "*.webp", "*.ico",
@@ -62,16 +118,26 @@ func (s *HeadlessScraper) Scrape(ctx context.Context, config model.SiteScrapingC
 		}),
 		chromedp.Navigate(config.BaseURL),
 		chromedp.WaitVisible(*config.JobListItemSelector, chromedp.ByQuery),
-		chromedp.OuterHTML("html", &htmlContent),
 	)
-
 	if err != nil {
+		s.diagnostics.recordRendered(config.BaseURL, started, "", err)
+		return nil, fmt.Errorf("chrome automation failed for %s: %w", config.SiteName, err)
+	}
+
+	if err := runHeadlessActions(browserCtx, actions, *config.JobListItemSelector); err != nil {
+		return nil, fmt.Errorf("chrome automation failed for %s: %w", config.SiteName, err)
+	}
+
+	var htmlContent string
+	if err := runWithTimeout(browserCtx, pageLoadTimeout, chromedp.OuterHTML("html", &htmlContent)); err != nil {
 		return nil, fmt.Errorf("chrome automation failed for %s: %w", config.SiteName, err)
 	}
 
 	if htmlContent == "" {
 		return nil, fmt.Errorf("error to remain HTML content from page %s", config.SiteName)
 	}
+	s.recorder.recordRendered(config.BaseURL, htmlContent)
+	s.diagnostics.recordRendered(config.BaseURL, started, htmlContent, nil)
 
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
@@ -115,13 +181,13 @@ func (s *HeadlessScraper) Scrape(ctx context.Context, config model.SiteScrapingC
 			JobLink:  jobLink,
 		}
 
-		if jobLink != "" && (config.JobDescriptionSelector != nil || config.JobRequisitionIdSelector != nil) {
+		if jobLink != "" && (config.JobDescriptionSelector != nil || config.JobRequisitionIdSelector != nil) && s.detailFilter.wants(job) {
 			wg.Add(1)
 			sem <- struct{}{}
 			go func(j *model.Job, link string) {
 				defer wg.Done()
 				defer func() { <-sem }()
-				s.fetchJobDetails(allocCtx, config, j, link)
+				s.fetchJobDetails(browserCtx, config, j, link)
 			}(job, jobLink)
 		}
 
@@ -134,8 +200,13 @@ func (s *HeadlessScraper) Scrape(ctx context.Context, config model.SiteScrapingC
 	return jobs, nil
 }
 
-func (s *HeadlessScraper) fetchJobDetails(allocCtx context.Context, config model.SiteScrapingConfig, job *model.Job, jobURL string) {
-	taskCtx, cancel := chromedp.NewContext(allocCtx) // reuses existing Chrome allocator
+func (s *HeadlessScraper) fetchJobDetails(browserCtx context.Context, config model.SiteScrapingConfig, job *model.Job, jobURL string) {
+	if err := s.politeness.Before(browserCtx, jobURL); err != nil {
+		logging.Logger.Debug().Err(err).Str("url", jobURL).Msg("Skipping job detail page")
+		return
+	}
+
+	taskCtx, cancel := chromedp.NewContext(browserCtx) // new tab in the scrape's browser
 	defer cancel()
 
 	// Apply a strict timeout so detail page fetches cannot hang indefinitely
@@ -151,6 +222,7 @@ func (s *HeadlessScraper) fetchJobDetails(allocCtx context.Context, config model
 	}
 
 	var detailHTML string
+	started := time.Now()
 	err := chromedp.Run(taskCtx,
 		network.Enable(),
 		network.SetBlockedURLs([]string{
@@ -164,12 +236,17 @@ func (s *HeadlessScraper) fetchJobDetails(allocCtx context.Context, config model
 	)
 	if err != nil {
 		logging.Logger.Warn().Err(err).Str("job_title", job.Title).Str("url", jobURL).Msg("Failed to fetch job detail page")
+		s.stats.addDetailFailure()
+		s.diagnostics.recordRendered(jobURL, started, "", err)
 		return
 	}
+	s.recorder.recordRendered(jobURL, detailHTML)
+	s.diagnostics.recordRendered(jobURL, started, detailHTML, nil)
 
 	detailDoc, err := goquery.NewDocumentFromReader(strings.NewReader(detailHTML))
 	if err != nil {
 		logging.Logger.Warn().Err(err).Str("job_title", job.Title).Msg("Failed to parse job detail HTML")
+		s.stats.addDetailFailure()
 		return
 	}
 
@@ -185,6 +262,7 @@ func (s *HeadlessScraper) fetchJobDetails(allocCtx context.Context, config model
 		} else {
 			logging.Logger.Debug().Str("job_title", job.Title).Str("requisition_id", reqID).Msg("Parsed requisition ID")
 			job.RequisitionID = reqID
+			job.SyntheticID = false
 		}
 	}
 }
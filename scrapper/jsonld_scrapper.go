@@ -0,0 +1,495 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/tidwall/gjson"
+)
+
+const jsonLDScriptSelector = `script[type="application/ld+json"]`
+
+// JSONLDScrapper reads schema.org JobPosting objects embedded as JSON-LD.
+// Postings found on the listing page are used directly; links to detail pages
+// (from an ItemList or from LinkSelector) are visited and their JobPosting fills
+// in the job. NextPageSelector is followed like in the CSS strategy.
+type JSONLDScrapper struct {
+	politeness   *Politeness
+	transport    http.RoundTripper
+	stats        *ScrapeStats
+	detailFilter DetailFilter
+}
+
+func NewJSONLDScraper() *JSONLDScrapper {
+	return &JSONLDScrapper{}
+}
+
+func (s *JSONLDScrapper) usePoliteness(p *Politeness) {
+	s.politeness = p
+}
+
+func (s *JSONLDScrapper) useTransport(rt http.RoundTripper) {
+	s.transport = rt
+}
+
+func (s *JSONLDScrapper) useStats(stats *ScrapeStats) {
+	s.stats = stats
+}
+
+func (s *JSONLDScrapper) useDetailFilter(f DetailFilter) {
+	s.detailFilter = f
+}
+
+func (s *JSONLDScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("BaseURL is required for JSONLD site %s", config.SiteName)
+	}
+	if err := s.politeness.Allowed(ctx, config.BaseURL); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	jobsByLink := make(map[string]*model.Job)
+	var jobs []*model.Job
+
+	addJob := func(job *model.Job) *model.Job {
+		mu.Lock()
+		defer mu.Unlock()
+		if job.JobLink != "" {
+			if existing, ok := jobsByLink[job.JobLink]; ok {
+				return existing
+			}
+			jobsByLink[job.JobLink] = job
+		}
+		jobs = append(jobs, job)
+		return job
+	}
+
+	c := colly.NewCollector(colly.Async(true))
+	s.politeness.applyTo(c, s.transport)
+	detailCollector := c.Clone()
+	c.Limit(&colly.LimitRule{DomainGlob: "*", Parallelism: 8})
+	c.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"
+
+	visitDetail := func(e *colly.HTMLElement, link string, job *model.Job) {
+		jobURL := e.Request.AbsoluteURL(link)
+		if jobURL == "" {
+			return
+		}
+		if job == nil {
+			job = &model.Job{JobLink: jobURL}
+		}
+		job = addJob(job)
+		if !s.detailFilter.wants(job) {
+			return
+		}
+		reqCtx := colly.NewContext()
+		reqCtx.Put("job", job)
+		detailCollector.Request("GET", jobURL, nil, reqCtx, nil)
+	}
+
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		postings, itemURLs := extractJSONLD(e.DOM)
+
+		for _, posting := range postings {
+			job := jobFromPosting(posting)
+			if job.JobLink == "" {
+				job.JobLink = e.Request.URL.String()
+			} else {
+				job.JobLink = e.Request.AbsoluteURL(job.JobLink)
+			}
+			addJob(job)
+		}
+		for _, itemURL := range itemURLs {
+			visitDetail(e, itemURL, nil)
+		}
+
+		if config.LinkSelector == nil {
+			return
+		}
+		attr := "href"
+		if config.LinkAttribute != nil && *config.LinkAttribute != "" {
+			attr = *config.LinkAttribute
+		}
+		items := e.DOM
+		if config.JobListItemSelector != nil && *config.JobListItemSelector != "" {
+			items = e.DOM.Find(*config.JobListItemSelector)
+		}
+		items.Each(func(_ int, item *goquery.Selection) {
+			link := item
+			if !item.Is(*config.LinkSelector) {
+				link = item.Find(*config.LinkSelector)
+			}
+			link.Each(func(_ int, a *goquery.Selection) {
+				href, ok := a.Attr(attr)
+				if !ok || href == "" {
+					return
+				}
+				job := &model.Job{JobLink: e.Request.AbsoluteURL(href)}
+				if config.TitleSelector != nil {
+					job.Title = strings.TrimSpace(item.Find(*config.TitleSelector).First().Text())
+				}
+				visitDetail(e, href, job)
+			})
+		})
+	})
+
+	detailCollector.OnHTML("html", func(e *colly.HTMLElement) {
+		job, ok := e.Request.Ctx.GetAny("job").(*model.Job)
+		if !ok {
+			return
+		}
+		postings, _ := extractJSONLD(e.DOM)
+		if len(postings) == 0 {
+			logging.Logger.Warn().Str("url", e.Request.URL.String()).Msg("No JobPosting JSON-LD found on detail page")
+			return
+		}
+		detail := jobFromPosting(postings[0])
+		mu.Lock()
+		mergeJob(job, detail)
+		mu.Unlock()
+	})
+	detailCollector.OnError(func(r *colly.Response, err error) {
+		logging.Logger.Warn().Err(err).Str("url", r.Request.URL.String()).Msg("Failed to fetch job detail page")
+		s.stats.addDetailFailure()
+	})
+
+	crawl := newPageCrawl(config)
+	if config.NextPageSelector != nil {
+		c.OnHTML(*config.NextPageSelector, func(e *colly.HTMLElement) {
+			nextPage := e.Request.AbsoluteURL(e.Attr("href"))
+			if nextPage == "" || !crawl.claim(nextPage) {
+				return
+			}
+			logging.Logger.Debug().Str("url", nextPage).Msg("Visiting next page")
+			e.Request.Visit(nextPage)
+		})
+	}
+
+	listingErr := captureListingError(c)
+
+	done := make(chan error, 1)
+	go func() {
+		crawl.claim(config.BaseURL)
+		if err := c.Visit(config.BaseURL); err != nil {
+			done <- err
+			return
+		}
+		c.Wait()
+		detailCollector.Wait()
+		done <- listingErr()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("scraping timed out for site %s: %w", config.SiteName, ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []*model.Job
+	for _, job := range jobs {
+		if job.Title == "" {
+			logging.Logger.Debug().Str("url", job.JobLink).Msg("Dropping JSON-LD job without title")
+			continue
+		}
+		result = append(result, job)
+	}
+	return result, nil
+}
+
+// extractJSONLD returns the JobPosting objects found in the page's JSON-LD blocks,
+// plus the URLs listed in any ItemList whose entries are not inline postings.
+func extractJSONLD(doc *goquery.Selection) ([]gjson.Result, []string) {
+	var postings []gjson.Result
+	var itemURLs []string
+
+	doc.Find(jsonLDScriptSelector).Each(func(_ int, script *goquery.Selection) {
+		raw := strings.TrimSpace(script.Text())
+		if !gjson.Valid(raw) {
+			// Some sites emit raw newlines or tabs inside strings.
+			raw = strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(raw)
+			if !gjson.Valid(raw) {
+				logging.Logger.Debug().Msg("Skipping invalid JSON-LD block")
+				return
+			}
+		}
+		walkJSONLD(gjson.Parse(raw), &postings, &itemURLs)
+	})
+
+	return postings, itemURLs
+}
+
+func walkJSONLD(node gjson.Result, postings *[]gjson.Result, itemURLs *[]string) {
+	if node.IsArray() {
+		node.ForEach(func(_, child gjson.Result) bool {
+			walkJSONLD(child, postings, itemURLs)
+			return true
+		})
+		return
+	}
+	if !node.IsObject() {
+		return
+	}
+
+	if hasType(node, "JobPosting") {
+		*postings = append(*postings, node)
+		return
+	}
+	if graph := node.Get(`@graph`); graph.Exists() {
+		walkJSONLD(graph, postings, itemURLs)
+	}
+	if hasType(node, "ItemList") {
+		node.Get("itemListElement").ForEach(func(_, element gjson.Result) bool {
+			item := element.Get("item")
+			switch {
+			case item.IsObject() && hasType(item, "JobPosting"):
+				*postings = append(*postings, item)
+			case item.IsObject() && item.Get("url").String() != "":
+				*itemURLs = append(*itemURLs, item.Get("url").String())
+			case item.Type == gjson.String:
+				*itemURLs = append(*itemURLs, item.String())
+			case element.Get("url").String() != "":
+				*itemURLs = append(*itemURLs, element.Get("url").String())
+			}
+			return true
+		})
+	}
+}
+
+func hasType(node gjson.Result, typeName string) bool {
+	found := false
+	t := node.Get(`@type`)
+	if t.IsArray() {
+		t.ForEach(func(_, v gjson.Result) bool {
+			found = v.String() == typeName
+			return !found
+		})
+		return found
+	}
+	return t.String() == typeName
+}
+
+// jobFromPosting maps a schema.org JobPosting to a model.Job.
+func jobFromPosting(p gjson.Result) *model.Job {
+	job := &model.Job{
+		Title:          strings.TrimSpace(html.UnescapeString(p.Get("title").String())),
+		JobLink:        strings.TrimSpace(p.Get("url").String()),
+		Company:        strings.TrimSpace(p.Get("hiringOrganization.name").String()),
+		Description:    htmlToText(p.Get("description").String()),
+		RequisitionID:  postingIdentifier(p.Get("identifier")),
+		EmploymentType: joinValues(p.Get("employmentType")),
+		Location:       postingLocation(p),
+		DatePosted:     parsePostingDate(p.Get("datePosted").String()),
+		Benefits:       stringValues(p.Get("jobBenefits")),
+	}
+	applyBaseSalary(job, p.Get("baseSalary"))
+	return job
+}
+
+// mergeJob fills job with the fields found on its detail page. Detail values win,
+// except that empty detail fields never erase what the listing already had.
+func mergeJob(job, detail *model.Job) {
+	if detail.Title != "" {
+		job.Title = detail.Title
+	}
+	if detail.Company != "" {
+		job.Company = detail.Company
+	}
+	if detail.Description != "" {
+		job.Description = detail.Description
+	}
+	if detail.RequisitionID != "" {
+		job.RequisitionID = detail.RequisitionID
+		job.SyntheticID = false
+	}
+	if detail.EmploymentType != "" {
+		job.EmploymentType = detail.EmploymentType
+	}
+	if detail.Location != "" {
+		job.Location = detail.Location
+	}
+	if detail.DatePosted != nil {
+		job.DatePosted = detail.DatePosted
+	}
+	if detail.SalaryMin != nil || detail.SalaryMax != nil {
+		job.SalaryMin, job.SalaryMax = detail.SalaryMin, detail.SalaryMax
+		job.SalaryCurrency, job.SalaryPeriod = detail.SalaryCurrency, detail.SalaryPeriod
+	}
+	if len(detail.Benefits) > 0 {
+		job.Benefits = detail.Benefits
+	}
+}
+
+// postingIdentifier reads identifier as a plain value or a PropertyValue.
+func postingIdentifier(id gjson.Result) string {
+	if id.IsArray() {
+		id = id.Get("0")
+	}
+	if id.IsObject() {
+		if v := id.Get("value"); v.Exists() {
+			return strings.TrimSpace(v.String())
+		}
+		return strings.TrimSpace(id.Get("name").String())
+	}
+	return strings.TrimSpace(id.String())
+}
+
+func postingLocation(p gjson.Result) string {
+	var places []string
+	seen := make(map[string]bool)
+	addPlace := func(place gjson.Result) {
+		address := place.Get("address")
+		if address.Type == gjson.String {
+			if s := strings.TrimSpace(address.String()); s != "" && !seen[s] {
+				seen[s] = true
+				places = append(places, s)
+			}
+			return
+		}
+		country := address.Get("addressCountry")
+		if country.IsObject() {
+			country = country.Get("name")
+		}
+		var parts []string
+		for _, part := range []string{address.Get("addressLocality").String(), address.Get("addressRegion").String(), country.String()} {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if s := strings.Join(parts, ", "); s != "" && !seen[s] {
+			seen[s] = true
+			places = append(places, s)
+		}
+	}
+
+	loc := p.Get("jobLocation")
+	if loc.IsArray() {
+		loc.ForEach(func(_, place gjson.Result) bool {
+			addPlace(place)
+			return true
+		})
+	} else if loc.IsObject() {
+		addPlace(loc)
+	}
+
+	location := strings.Join(places, "; ")
+	if strings.EqualFold(p.Get("jobLocationType").String(), "TELECOMMUTE") {
+		if location == "" {
+			return "Remote"
+		}
+		return location + " (Remote)"
+	}
+	return location
+}
+
+var postingDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func parsePostingDate(raw string) *time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	for _, layout := range postingDateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// applyBaseSalary reads a MonetaryAmount, whose value is either a number or a
+// QuantitativeValue with value/minValue/maxValue and unitText.
+func applyBaseSalary(job *model.Job, salary gjson.Result) {
+	if !salary.Exists() {
+		return
+	}
+	if salary.IsArray() {
+		salary = salary.Get("0")
+	}
+
+	value := salary.Get("value")
+	unit := salary.Get("unitText").String()
+	var minValue, maxValue gjson.Result
+	if value.IsObject() {
+		if u := value.Get("unitText").String(); u != "" {
+			unit = u
+		}
+		minValue, maxValue = value.Get("minValue"), value.Get("maxValue")
+		if v := value.Get("value"); v.Exists() {
+			minValue, maxValue = v, v
+		}
+	} else {
+		minValue, maxValue = value, value
+	}
+
+	job.SalaryMin = positiveAmount(minValue)
+	job.SalaryMax = positiveAmount(maxValue)
+	if job.SalaryMin == nil && job.SalaryMax == nil {
+		return
+	}
+	job.SalaryCurrency = strings.ToUpper(strings.TrimSpace(salary.Get("currency").String()))
+	job.SalaryPeriod = strings.ToLower(strings.TrimSpace(unit))
+}
+
+func positiveAmount(v gjson.Result) *float64 {
+	if !v.Exists() {
+		return nil
+	}
+	f := v.Float()
+	if f <= 0 {
+		return nil
+	}
+	return &f
+}
+
+func joinValues(v gjson.Result) string {
+	if !v.IsArray() {
+		return strings.TrimSpace(v.String())
+	}
+	return strings.Join(stringValues(v), ",")
+}
+
+// stringValues reads a string or an array of strings, skipping empty ones.
+func stringValues(v gjson.Result) []string {
+	var values []string
+	for _, item := range v.Array() {
+		if s := strings.TrimSpace(item.String()); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// htmlToText turns a JobPosting description (HTML, sometimes entity-escaped) into plain text.
+func htmlToText(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if strings.Contains(raw, "&lt;") {
+		raw = html.UnescapeString(raw)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return raw
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
@@ -0,0 +1,203 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const jsonLDPosting = `{
+	"@context": "https://schema.org/",
+	"@type": "JobPosting",
+	"title": "Desenvolvedor Go",
+	"description": "&lt;p&gt;Construir &lt;b&gt;APIs&lt;/b&gt;&lt;/p&gt;",
+	"identifier": {"@type": "PropertyValue", "name": "Acme", "value": "REQ-42"},
+	"datePosted": "2026-03-01",
+	"employmentType": ["FULL_TIME", "CONTRACTOR"],
+	"hiringOrganization": {"@type": "Organization", "name": "Acme"},
+	"jobLocation": {"@type": "Place", "address": {"@type": "PostalAddress", "addressLocality": "São Paulo", "addressRegion": "SP", "addressCountry": "BR"}},
+	"baseSalary": {"@type": "MonetaryAmount", "currency": "brl", "value": {"@type": "QuantitativeValue", "minValue": 8000, "maxValue": 12000, "unitText": "MONTH"}}
+}`
+
+func htmlPage(jsonLD string, body string) string {
+	return fmt.Sprintf(`<html><head><script type="application/ld+json">%s</script></head><body>%s</body></html>`, jsonLD, body)
+}
+
+func TestJSONLDScrapper_Scrape_DetailPages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage(`{"@type":"Organization","name":"Acme"}`, `<ul><li class="job"><a href="/jobs/42">Dev Go</a></li></ul><a class="next" href="/jobs?page=2">next</a>`))
+	})
+	mux.HandleFunc("/jobs/42", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage(jsonLDPosting, ""))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := model.SiteScrapingConfig{
+		SiteName:            "Acme",
+		BaseURL:             srv.URL + "/jobs",
+		ScrapingType:        "JSONLD",
+		JobListItemSelector: strPtr("li.job"),
+		LinkSelector:        strPtr("a"),
+	}
+
+	jobs, err := NewJSONLDScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0]
+	assert.Equal(t, "Desenvolvedor Go", job.Title)
+	assert.Equal(t, srv.URL+"/jobs/42", job.JobLink)
+	assert.Equal(t, "REQ-42", job.RequisitionID)
+	assert.Equal(t, "Construir APIs", job.Description)
+	assert.Equal(t, "FULL_TIME,CONTRACTOR", job.EmploymentType)
+	assert.Equal(t, "São Paulo, SP, BR", job.Location)
+	require.NotNil(t, job.DatePosted)
+	assert.Equal(t, "2026-03-01", job.DatePosted.Format("2006-01-02"))
+	require.NotNil(t, job.SalaryMin)
+	require.NotNil(t, job.SalaryMax)
+	assert.Equal(t, 8000.0, *job.SalaryMin)
+	assert.Equal(t, 12000.0, *job.SalaryMax)
+	assert.Equal(t, "BRL", job.SalaryCurrency)
+	assert.Equal(t, "month", job.SalaryPeriod)
+}
+
+func TestJSONLDScrapper_Scrape_ListingPostingsAndNextPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, htmlPage(`{"@type":"JobPosting","title":"QA","url":"/jobs/2","identifier":"2","jobLocationType":"TELECOMMUTE"}`, ""))
+			return
+		}
+		fmt.Fprint(w, htmlPage(`{"@graph":[{"@type":"JobPosting","title":"Dev","url":"/jobs/1","identifier":1}]}`, `<a class="next" href="/?page=2">next</a>`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := model.SiteScrapingConfig{
+		SiteName:         "Acme",
+		BaseURL:          srv.URL + "/",
+		ScrapingType:     "JSONLD",
+		NextPageSelector: strPtr("a.next"),
+	}
+
+	jobs, err := NewJSONLDScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	byID := map[string]*model.Job{}
+	for _, job := range jobs {
+		byID[job.RequisitionID] = job
+	}
+	require.Contains(t, byID, "1")
+	require.Contains(t, byID, "2")
+	assert.Equal(t, srv.URL+"/jobs/1", byID["1"].JobLink)
+	assert.Equal(t, "Remote", byID["2"].Location)
+}
+
+func TestJSONLDScrapper_Scrape_FailingListingPageFailsTheScrape(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, htmlPage(`{"@type":"JobPosting","title":"Dev","url":"/jobs/1","identifier":1}`, `<a class="next" href="/?page=2">next</a>`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := model.SiteScrapingConfig{
+		SiteName:         "Acme",
+		BaseURL:          srv.URL + "/",
+		ScrapingType:     "JSONLD",
+		NextPageSelector: strPtr("a.next"),
+	}
+
+	jobs, err := NewJSONLDScraper().Scrape(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status code 500")
+	assert.Nil(t, jobs)
+}
+
+func TestJSONLDScrapper_Scrape_ItemList(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage(`{"@type":"ItemList","itemListElement":[{"@type":"ListItem","position":1,"url":"/jobs/42"}]}`, ""))
+	})
+	mux.HandleFunc("/jobs/42", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage(jsonLDPosting, ""))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	jobs, err := NewJSONLDScraper().Scrape(context.Background(), model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL + "/", ScrapingType: "JSONLD"})
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "REQ-42", jobs[0].RequisitionID)
+}
+
+func TestJSONLDScrapper_Scrape_DetailFilterSkipsDetailPages(t *testing.T) {
+	var detailHits sync.Map
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage(`{"@type":"ItemList","itemListElement":[{"url":"/jobs/41"},{"url":"/jobs/42"}]}`, ""))
+	})
+	mux.HandleFunc("/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		detailHits.Store(r.URL.Path, true)
+		fmt.Fprint(w, htmlPage(jsonLDPosting, ""))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	config := model.SiteScrapingConfig{SiteName: "Acme", BaseURL: srv.URL + "/", ScrapingType: "JSONLD"}
+	s, err := NewScraperFactory(config, WithDetailFilter(func(job *model.Job) bool {
+		if job.JobLink == srv.URL+"/jobs/41" {
+			job.RequisitionID = "known-41"
+			job.Title = "Vaga conhecida"
+			return false
+		}
+		return true
+	}))
+	require.NoError(t, err)
+
+	jobs, err := s.Scrape(context.Background(), config)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.ElementsMatch(t, []string{"known-41", "REQ-42"}, []string{jobs[0].RequisitionID, jobs[1].RequisitionID})
+	_, fetched := detailHits.Load("/jobs/41")
+	assert.False(t, fetched)
+}
+
+func TestApplyBaseSalary_PlainValue(t *testing.T) {
+	job := jobFromPosting(gjson.Parse(`{"@type":"JobPosting","title":"Dev","baseSalary":{"currency":"USD","value":"50","unitText":"HOUR"}}`))
+
+	require.NotNil(t, job.SalaryMin)
+	assert.Equal(t, 50.0, *job.SalaryMin)
+	assert.Equal(t, 50.0, *job.SalaryMax)
+	assert.Equal(t, "hour", job.SalaryPeriod)
+}
+
+func TestJobFromPosting_Benefits(t *testing.T) {
+	job := jobFromPosting(gjson.Parse(`{"@type":"JobPosting","title":"Dev","jobBenefits":["Plano de saúde", " ", "Gympass"]}`))
+
+	assert.Equal(t, []string{"Plano de saúde", "Gympass"}, job.Benefits)
+}
+
+func TestNewScraperFactory_JSONLD(t *testing.T) {
+	s, err := NewScraperFactory(model.SiteScrapingConfig{ScrapingType: "JSONLD"})
+
+	require.NoError(t, err)
+	assert.IsType(t, &JSONLDScrapper{}, s)
+}
@@ -0,0 +1,234 @@
+package scrapper
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// defaultMaxPages is used when a site config does not set its own page limit.
+	defaultMaxPages = 20
+	// maxPagesLimit is the hard ceiling no config can go beyond.
+	maxPagesLimit = 200
+)
+
+const (
+	PaginationOffset  = "offset"
+	PaginationPage    = "page"
+	PaginationCursor  = "cursor"
+	PaginationNextURL = "next_url"
+)
+
+// Pagination describes how to walk every page of an API listing.
+// It lives inside JSONDataMappings under the "pagination" key.
+type Pagination struct {
+	Type      string `json:"type"`       // offset, page, cursor or next_url
+	Param     string `json:"param"`      // query param carrying the offset, page number or cursor; optional when the templates use them
+	SizeParam string `json:"size_param"` // optional query param carrying the page size
+	PageSize  int    `json:"page_size"`
+	Start     *int   `json:"start"`      // first offset (default 0) or first page (default 1); 0 for 0-based page APIs
+	NextPath  string `json:"next_path"`  // gjson path to the next cursor or next URL
+	TotalPath string `json:"total_path"` // optional gjson path to the total number of jobs
+	MaxPages  int    `json:"max_pages"`
+}
+
+func (p *Pagination) validate() error {
+	if p.Start != nil && *p.Start < 0 {
+		return fmt.Errorf("pagination start must not be negative")
+	}
+	switch p.Type {
+	case PaginationOffset, PaginationPage:
+		return nil
+	case PaginationCursor, PaginationNextURL:
+		if p.NextPath == "" {
+			return fmt.Errorf("pagination type %s requires next_path", p.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown pagination type: %s", p.Type)
+	}
+}
+
+func (p *Pagination) maxPages() int {
+	return clampMaxPages(p.MaxPages)
+}
+
+func clampMaxPages(n int) int {
+	if n <= 0 {
+		return defaultMaxPages
+	}
+	if n > maxPagesLimit {
+		return maxPagesLimit
+	}
+	return n
+}
+
+// pageState tracks where the paginator currently is.
+type pageState struct {
+	Page    int    // 1-based page counter, regardless of pagination type
+	Offset  int    // items already consumed (offset type) or current page number (page type)
+	Cursor  string // last cursor read from the response
+	NextURL string // next page URL read from the response (next_url type)
+	fetched int    // total items returned so far
+}
+
+// paginator walks the pages of an API listing according to a Pagination config.
+// A nil config yields a single page.
+type paginator struct {
+	cfg   *Pagination
+	state pageState
+	seen  map[string]bool
+}
+
+func newPaginator(cfg *Pagination) (*paginator, error) {
+	p := &paginator{cfg: cfg, seen: make(map[string]bool)}
+	p.state = pageState{Page: 1}
+
+	if cfg == nil {
+		return p, nil
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case PaginationOffset:
+		if cfg.Start != nil {
+			p.state.Offset = *cfg.Start
+		}
+	case PaginationPage:
+		p.state.Offset = 1
+		if cfg.Start != nil {
+			p.state.Offset = *cfg.Start
+		}
+	}
+	return p, nil
+}
+
+// vars exposes the current position to the endpoint and payload templates.
+func (p *paginator) vars() requestVars {
+	v := requestVars{Page: p.state.Page, Cursor: p.state.Cursor}
+	if p.cfg == nil {
+		return v
+	}
+	v.PageSize = p.cfg.PageSize
+	switch p.cfg.Type {
+	case PaginationOffset:
+		v.Offset = p.state.Offset
+	case PaginationPage:
+		v.Page = p.state.Offset
+		v.Offset = (p.state.Page - 1) * p.cfg.PageSize
+	default:
+		v.Offset = p.state.fetched
+	}
+	return v
+}
+
+// markSeen records a request and reports whether it had already been made,
+// which means the listing is looping.
+func (p *paginator) markSeen(key string) bool {
+	if p.seen[key] {
+		return true
+	}
+	p.seen[key] = true
+	return false
+}
+
+// applyQuery writes the current offset/page/cursor into the URL query, when the
+// config names a query param for it.
+func (p *paginator) applyQuery(rawURL string) (string, error) {
+	if p.cfg == nil || (p.cfg.Param == "" && p.cfg.SizeParam == "") {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint URL %s: %w", rawURL, err)
+	}
+	q := u.Query()
+
+	switch p.cfg.Type {
+	case PaginationOffset, PaginationPage:
+		if p.cfg.Param != "" {
+			q.Set(p.cfg.Param, strconv.Itoa(p.state.Offset))
+		}
+	case PaginationCursor:
+		if p.state.Cursor == "" {
+			q.Del(p.cfg.Param)
+		} else {
+			q.Set(p.cfg.Param, p.state.Cursor)
+		}
+	}
+	if p.cfg.SizeParam != "" && p.cfg.PageSize > 0 {
+		q.Set(p.cfg.SizeParam, strconv.Itoa(p.cfg.PageSize))
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// next advances to the following page based on the page just fetched from currentURL.
+// It returns false when there is nothing left to fetch or the page limit was reached.
+func (p *paginator) next(currentURL string, body []byte, itemsOnPage int) (bool, error) {
+	if p.cfg == nil {
+		return false, nil
+	}
+	p.state.fetched += itemsOnPage
+
+	if p.state.Page >= p.cfg.maxPages() {
+		return false, nil
+	}
+
+	switch p.cfg.Type {
+	case PaginationOffset, PaginationPage:
+		if itemsOnPage == 0 {
+			return false, nil
+		}
+		if p.cfg.PageSize > 0 && itemsOnPage < p.cfg.PageSize {
+			return false, nil
+		}
+		if p.cfg.TotalPath != "" {
+			total := gjson.GetBytes(body, p.cfg.TotalPath)
+			if total.Exists() && p.state.fetched >= int(total.Int()) {
+				return false, nil
+			}
+		}
+		if p.cfg.Type == PaginationOffset {
+			step := p.cfg.PageSize
+			if step <= 0 {
+				step = itemsOnPage
+			}
+			p.state.Offset += step
+		} else {
+			p.state.Offset++
+		}
+
+	case PaginationCursor:
+		cursor := gjson.GetBytes(body, p.cfg.NextPath).String()
+		if cursor == "" || itemsOnPage == 0 {
+			return false, nil
+		}
+		p.state.Cursor = cursor
+
+	case PaginationNextURL:
+		nextRaw := gjson.GetBytes(body, p.cfg.NextPath).String()
+		if nextRaw == "" {
+			return false, nil
+		}
+		base, err := url.Parse(currentURL)
+		if err != nil {
+			return false, err
+		}
+		ref, err := url.Parse(nextRaw)
+		if err != nil {
+			return false, fmt.Errorf("invalid next page URL %s: %w", nextRaw, err)
+		}
+		p.state.NextURL = base.ResolveReference(ref).String()
+	}
+
+	p.state.Page++
+	return true, nil
+}
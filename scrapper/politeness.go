@@ -0,0 +1,281 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"web-scrapper/interfaces"
+	"web-scrapper/logging"
+
+	"github.com/gocolly/colly/v2"
+)
+
+const (
+	// PolicyRobotsDisallow means robots.txt forbids the URL.
+	PolicyRobotsDisallow = "robots_disallow"
+	// PolicyBudgetExhausted means the host's request budget is booked beyond MaxWait.
+	PolicyBudgetExhausted = "budget_exhausted"
+
+	defaultPolitenessInterval = 250 * time.Millisecond
+	defaultPolitenessMaxWait  = 2 * time.Minute
+	defaultRobotsTTL          = 6 * time.Hour
+	// robotsRetryTTL is used when robots.txt could not be read, so we try again soon.
+	robotsRetryTTL  = 10 * time.Minute
+	robotsTimeout   = 10 * time.Second
+	maxRobotsBytes  = 512 * 1024
+	defaultRobotsUA = "ScrapJobs"
+)
+
+// PolicySkipError is returned when a request is not sent because of the politeness
+// policy. A scrape failing with it was skipped, not broken.
+type PolicySkipError struct {
+	URL    string
+	Reason string
+}
+
+func (e *PolicySkipError) Error() string {
+	return fmt.Sprintf("skipped %s by politeness policy: %s", e.URL, e.Reason)
+}
+
+type PolitenessConfig struct {
+	// MinInterval is the minimum spacing between two requests to the same host,
+	// across all workers. A larger robots.txt Crawl-delay wins.
+	MinInterval time.Duration
+	// MaxWait is the longest a request waits for its slot before being skipped.
+	MaxWait time.Duration
+	// RobotsTTL is how long a host's robots.txt is cached.
+	RobotsTTL time.Duration
+	// Agent is the robots.txt user-agent token whose rules we follow (besides "*").
+	Agent string
+}
+
+// Politeness is shared by all scrapers of a process: it caches robots.txt per host,
+// honors Disallow and Crawl-delay, and spaces requests per host through budget.
+// A nil *Politeness allows everything.
+type Politeness struct {
+	cfg    PolitenessConfig
+	budget interfaces.DomainBudgetInterface
+	client *http.Client
+
+	mu     sync.Mutex
+	robots map[string]*robotsEntry
+}
+
+type robotsEntry struct {
+	ready   chan struct{}
+	rules   *robotsRules
+	expires time.Time
+}
+
+// expired reports whether a fetched entry is past its TTL; entries still being
+// fetched are not.
+func (e *robotsEntry) expired() bool {
+	select {
+	case <-e.ready:
+		return time.Now().After(e.expires)
+	default:
+		return false
+	}
+}
+
+// NewPoliteness builds the policy. Without a budget, requests are spaced per
+// process only.
+func NewPoliteness(cfg PolitenessConfig, budget interfaces.DomainBudgetInterface) *Politeness {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = defaultPolitenessInterval
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = defaultPolitenessMaxWait
+	}
+	if cfg.RobotsTTL <= 0 {
+		cfg.RobotsTTL = defaultRobotsTTL
+	}
+	if cfg.Agent == "" {
+		cfg.Agent = defaultRobotsUA
+	}
+	if budget == nil {
+		budget = newLocalDomainBudget()
+	}
+	return &Politeness{
+		cfg:    cfg,
+		budget: budget,
+		client: &http.Client{Timeout: robotsTimeout},
+		robots: make(map[string]*robotsEntry),
+	}
+}
+
+// Allowed checks rawURL against the host's robots.txt without spending budget.
+func (p *Politeness) Allowed(ctx context.Context, rawURL string) error {
+	if p == nil {
+		return nil
+	}
+	_, err := p.rulesFor(ctx, rawURL)
+	return err
+}
+
+// Before must be called before each request: it checks robots.txt and waits for
+// the host's next request slot.
+func (p *Politeness) Before(ctx context.Context, rawURL string) error {
+	if p == nil {
+		return nil
+	}
+	rules, err := p.rulesFor(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+
+	u, _ := url.Parse(rawURL)
+	interval := p.cfg.MinInterval
+	if rules.crawlDelay > interval {
+		interval = rules.crawlDelay
+	}
+
+	wait, ok, err := p.budget.Reserve(ctx, strings.ToLower(u.Host), interval, p.cfg.MaxWait)
+	if err != nil {
+		// Redis being down must not stop scraping: fall back to spacing by the interval alone
+		logging.Logger.Warn().Err(err).Str("host", u.Host).Msg("Domain budget unavailable, using local spacing")
+		wait, ok = interval, true
+	}
+	if !ok {
+		return &PolicySkipError{URL: rawURL, Reason: PolicyBudgetExhausted}
+	}
+	return sleep(ctx, wait)
+}
+
+// rulesFor returns the cached robots.txt rules of rawURL's host, or a
+// PolicySkipError when they disallow rawURL.
+func (p *Politeness) rulesFor(ctx context.Context, rawURL string) (*robotsRules, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", rawURL)
+	}
+	origin := strings.ToLower(u.Scheme + "://" + u.Host)
+
+	p.mu.Lock()
+	entry, ok := p.robots[origin]
+	if !ok || entry.expired() {
+		entry = &robotsEntry{ready: make(chan struct{})}
+		p.robots[origin] = entry
+		p.mu.Unlock()
+		entry.rules, entry.expires = p.fetchRobots(origin)
+		close(entry.ready)
+	} else {
+		p.mu.Unlock()
+	}
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if !entry.rules.allowed(u) {
+		return nil, &PolicySkipError{URL: rawURL, Reason: PolicyRobotsDisallow}
+	}
+	return entry.rules, nil
+}
+
+// fetchRobots reads origin's robots.txt. A missing file (4xx) allows everything;
+// an unreachable one also does, but is retried after robotsRetryTTL.
+func (p *Politeness) fetchRobots(origin string) (*robotsRules, time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), robotsTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{}, time.Now().Add(robotsRetryTTL)
+	}
+	req.Header.Set("User-Agent", p.cfg.Agent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		logging.Logger.Warn().Err(err).Str("origin", origin).Msg("Failed to fetch robots.txt, allowing all")
+		return &robotsRules{}, time.Now().Add(robotsRetryTTL)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+		if err != nil {
+			return &robotsRules{}, time.Now().Add(robotsRetryTTL)
+		}
+		return parseRobots(body, p.cfg.Agent), time.Now().Add(p.cfg.RobotsTTL)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &robotsRules{}, time.Now().Add(p.cfg.RobotsTTL)
+	default:
+		logging.Logger.Warn().Int("status", resp.StatusCode).Str("origin", origin).Msg("robots.txt unavailable, allowing all")
+		return &robotsRules{}, time.Now().Add(robotsRetryTTL)
+	}
+}
+
+// Transport wraps base so every request goes through Before. A nil *Politeness
+// returns base unchanged.
+func (p *Politeness) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if p == nil {
+		return base
+	}
+	return &politeTransport{politeness: p, base: base}
+}
+
+type politeTransport struct {
+	politeness *Politeness
+	base       http.RoundTripper
+}
+
+func (t *politeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.politeness.Before(req.Context(), req.URL.String()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// applyTo routes a colly collector (and its clones) through base and the policy.
+// A nil base uses the default transport.
+func (p *Politeness) applyTo(c *colly.Collector, base http.RoundTripper) {
+	if p == nil && base == nil {
+		return
+	}
+	c.WithTransport(p.Transport(base))
+}
+
+// politeScraper is implemented by scrapers that can send their requests through a
+// Politeness policy.
+type politeScraper interface {
+	usePoliteness(p *Politeness)
+}
+
+// localDomainBudget spaces requests per host within this process only.
+type localDomainBudget struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newLocalDomainBudget() *localDomainBudget {
+	return &localDomainBudget{next: make(map[string]time.Time)}
+}
+
+func (b *localDomainBudget) Reserve(_ context.Context, host string, interval time.Duration, maxWait time.Duration) (time.Duration, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	slot := b.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	wait := slot.Sub(now)
+	if wait > maxWait {
+		return 0, false, nil
+	}
+	b.next[host] = slot.Add(interval)
+	return wait, true, nil
+}
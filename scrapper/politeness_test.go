@@ -0,0 +1,164 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRobots(t *testing.T) {
+	robots := parseRobots([]byte(`
+# comments are ignored
+User-agent: Googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /admin
+Disallow: /*.pdf$
+Allow: /admin/public
+Crawl-delay: 2
+`), "ScrapJobs")
+
+	tests := map[string]bool{
+		"/":                   true,
+		"/vagas/123":          true,
+		"/admin":              false,
+		"/admin/users":        false,
+		"/admin/public/jobs":  true,
+		"/docs/edital.pdf":    false,
+		"/docs/edital.pdf?x=": true,
+	}
+	for path, allowed := range tests {
+		u, _ := url.Parse("https://acme.com" + path)
+		assert.Equal(t, allowed, robots.allowed(u), path)
+	}
+	assert.Equal(t, 2*time.Second, robots.crawlDelay)
+}
+
+func TestParseRobots_SpecificAgentWins(t *testing.T) {
+	robots := parseRobots([]byte(`
+User-agent: *
+Disallow: /
+
+User-agent: scrapjobs
+Disallow: /private
+`), "ScrapJobs")
+
+	public, _ := url.Parse("https://acme.com/vagas")
+	private, _ := url.Parse("https://acme.com/private/1")
+	assert.True(t, robots.allowed(public))
+	assert.False(t, robots.allowed(private))
+}
+
+// robotsServer serves robots.txt plus a CSS listing with two jobs.
+func robotsServer(t *testing.T, robots string) (*httptest.Server, *sync.Map) {
+	var hits sync.Map
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := hits.LoadOrStore(r.URL.Path, new(int))
+		*n.(*int)++
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, robots)
+		case "/jobs":
+			fmt.Fprint(w, `<html><body><ul><li class="job"><a href="/jobs/1">Job 1</a></li><li class="job"><a href="/private/2">Job 2</a></li></ul></body></html>`)
+		default:
+			fmt.Fprintf(w, `<html><body><span class="req">%s</span></body></html>`, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestPoliteness_SkipsSiteDisallowedByRobots(t *testing.T) {
+	srv, hits := robotsServer(t, "User-agent: *\nDisallow: /jobs\n")
+	s, err := NewScraperFactory(cssConfig(srv.URL+"/jobs"), WithPoliteness(NewPoliteness(PolitenessConfig{MinInterval: time.Millisecond}, nil)))
+	require.NoError(t, err)
+
+	_, err = s.Scrape(context.Background(), cssConfig(srv.URL+"/jobs"))
+
+	var skip *PolicySkipError
+	require.ErrorAs(t, err, &skip)
+	assert.Equal(t, PolicyRobotsDisallow, skip.Reason)
+	_, fetched := hits.Load("/jobs")
+	assert.False(t, fetched)
+}
+
+func TestPoliteness_SkipsDisallowedDetailPages(t *testing.T) {
+	srv, hits := robotsServer(t, "User-agent: *\nDisallow: /private\n")
+	s, err := NewScraperFactory(cssConfig(srv.URL+"/jobs"), WithPoliteness(NewPoliteness(PolitenessConfig{MinInterval: time.Millisecond}, nil)))
+	require.NoError(t, err)
+
+	jobs, err := s.Scrape(context.Background(), cssConfig(srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	_, fetched := hits.Load("/private/2")
+	assert.False(t, fetched)
+	robots, _ := hits.Load("/robots.txt")
+	assert.Equal(t, 1, *robots.(*int), "robots.txt is cached per host")
+}
+
+// recordingBudget records the intervals requested per host.
+type recordingBudget struct {
+	mu        sync.Mutex
+	intervals []time.Duration
+	full      bool
+}
+
+func (b *recordingBudget) Reserve(_ context.Context, host string, interval time.Duration, maxWait time.Duration) (time.Duration, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.intervals = append(b.intervals, interval)
+	return 0, !b.full, nil
+}
+
+func TestPoliteness_HonorsCrawlDelay(t *testing.T) {
+	srv, _ := robotsServer(t, "User-agent: *\nCrawl-delay: 3\n")
+	budget := &recordingBudget{}
+	p := NewPoliteness(PolitenessConfig{MinInterval: time.Second}, budget)
+
+	require.NoError(t, p.Before(context.Background(), srv.URL+"/jobs"))
+
+	assert.Equal(t, []time.Duration{3 * time.Second}, budget.intervals)
+}
+
+func TestPoliteness_BudgetExhausted(t *testing.T) {
+	srv, _ := robotsServer(t, "")
+	p := NewPoliteness(PolitenessConfig{}, &recordingBudget{full: true})
+
+	client := &http.Client{Transport: p.Transport(nil)}
+	_, err := client.Get(srv.URL + "/jobs")
+
+	var skip *PolicySkipError
+	require.ErrorAs(t, err, &skip)
+	assert.Equal(t, PolicyBudgetExhausted, skip.Reason)
+}
+
+func TestLocalDomainBudget_SpacesRequests(t *testing.T) {
+	budget := newLocalDomainBudget()
+
+	first, ok, _ := budget.Reserve(context.Background(), "acme.com", time.Second, time.Minute)
+	require.True(t, ok)
+	second, ok, _ := budget.Reserve(context.Background(), "acme.com", time.Second, time.Minute)
+	require.True(t, ok)
+	_, ok, _ = budget.Reserve(context.Background(), "acme.com", time.Minute, 1500*time.Millisecond)
+
+	assert.Zero(t, first)
+	assert.InDelta(t, time.Second, second, float64(50*time.Millisecond))
+	assert.False(t, ok)
+}
+
+func TestPoliteness_NilAllowsEverything(t *testing.T) {
+	var p *Politeness
+
+	assert.NoError(t, p.Before(context.Background(), "https://acme.com/jobs"))
+	assert.Same(t, http.DefaultTransport, p.Transport(nil))
+}
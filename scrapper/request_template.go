@@ -0,0 +1,81 @@
+package scrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// requestVars are the variables available to APIEndpointTemplate and APIPayloadTemplate,
+// e.g. {"limit": {{.PageSize}}, "offset": {{.Offset}}, "searchText": {{json .Keyword}}}.
+type requestVars struct {
+	Page     int
+	Offset   int
+	PageSize int
+	Cursor   string
+	Keyword  string
+	Today    string // YYYY-MM-DD in America/Sao_Paulo
+	Now      time.Time
+}
+
+var templateFuncs = template.FuncMap{
+	// json renders a value as a JSON literal, so strings are quoted and escaped.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+var saoPaulo = loadSaoPaulo()
+
+func loadSaoPaulo() *time.Location {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		return time.FixedZone("BRT", -3*3600)
+	}
+	return loc
+}
+
+// requestTemplate is a parsed endpoint or payload template.
+// Plain strings without template actions are returned untouched.
+type requestTemplate struct {
+	raw  string
+	tmpl *template.Template
+}
+
+func parseRequestTemplate(name, raw string) (*requestTemplate, error) {
+	rt := &requestTemplate{raw: raw}
+	if !strings.Contains(raw, "{{") {
+		return rt, nil
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	rt.tmpl = tmpl
+	return rt, nil
+}
+
+func (rt *requestTemplate) render(vars requestVars) (string, error) {
+	if rt == nil {
+		return "", nil
+	}
+	if rt.tmpl == nil {
+		return rt.raw, nil
+	}
+	var buf bytes.Buffer
+	if err := rt.tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("error rendering %s template: %w", rt.tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+func newRequestVars(v requestVars, keyword string, now time.Time) requestVars {
+	v.Keyword = keyword
+	v.Now = now.In(saoPaulo)
+	v.Today = v.Now.Format("2006-01-02")
+	return v
+}
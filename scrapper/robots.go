@@ -0,0 +1,158 @@
+package scrapper
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRobotsCrawlDelay caps Crawl-delay, so a typo like "Crawl-delay: 3600" cannot
+// stall a scrape forever; the request budget still bounds the total wait.
+const maxRobotsCrawlDelay = 60 * time.Second
+
+// robotsRules are the robots.txt rules that apply to our user agent on one host.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// parseRobots keeps the groups naming agent (case-insensitive), or the "*" groups
+// when none does, following RFC 9309.
+func parseRobots(body []byte, agent string) *robotsRules {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inRules := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || inRules {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+				inRules = false
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	agent = strings.ToLower(agent)
+	rules := &robotsRules{}
+	if !mergeRobotsGroups(rules, groups, func(a string) bool { return a != "*" && strings.Contains(agent, a) }) {
+		mergeRobotsGroups(rules, groups, func(a string) bool { return a == "*" })
+	}
+	if rules.crawlDelay > maxRobotsCrawlDelay {
+		rules.crawlDelay = maxRobotsCrawlDelay
+	}
+	return rules
+}
+
+func mergeRobotsGroups(dst *robotsRules, groups []*robotsGroup, match func(agent string) bool) bool {
+	found := false
+	for _, g := range groups {
+		for _, a := range g.agents {
+			if !match(a) {
+				continue
+			}
+			found = true
+			dst.rules = append(dst.rules, g.rules...)
+			if g.crawlDelay > dst.crawlDelay {
+				dst.crawlDelay = g.crawlDelay
+			}
+			break
+		}
+	}
+	return found
+}
+
+// allowed applies the most specific (longest) matching rule; Allow wins ties.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allow, best := true, -1
+	for _, rule := range r.rules {
+		if rule.pattern == "" || !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > best || (len(rule.pattern) == best && rule.allow) {
+			allow, best = rule.allow, len(rule.pattern)
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a robots.txt path pattern, where "*" is any sequence and a
+// trailing "$" anchors the end of the path.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	if len(parts) == 1 {
+		return !anchored || pos == len(path)
+	}
+
+	for i, part := range parts[1:] {
+		rest := path[pos:]
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	return true
+}
@@ -2,7 +2,9 @@ package scrapper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"web-scrapper/logging"
@@ -12,6 +14,10 @@ import (
 )
 
 type JobScrapper struct{
+	politeness   *Politeness
+	transport    http.RoundTripper
+	detailFilter DetailFilter
+	stats        *ScrapeStats
 }
 
 func NewJobScraper() *JobScrapper {
@@ -19,12 +25,30 @@ func NewJobScraper() *JobScrapper {
 	}
 }
 
-func (s *JobScrapper) configureCollyCallbacks(c *colly.Collector, detailCollector *colly.Collector, jobs *[]*model.Job, wg *sync.WaitGroup, mu *sync.Mutex, selectors model.SiteScrapingConfig){
+func (s *JobScrapper) usePoliteness(p *Politeness) {
+	s.politeness = p
+}
 
-	detailCollector.OnHTML("body", func(e *colly.HTMLElement) {
-		defer wg.Done()
+func (s *JobScrapper) useTransport(rt http.RoundTripper) {
+	s.transport = rt
+}
+
+func (s *JobScrapper) useDetailFilter(f DetailFilter) {
+	s.detailFilter = f
+}
+
+func (s *JobScrapper) useStats(stats *ScrapeStats) {
+	s.stats = stats
+}
 
-		jobPtr := e.Request.Ctx.GetAny("job").(*model.Job)
+func (s *JobScrapper) configureCollyCallbacks(c *colly.Collector, detailCollector *colly.Collector, jobs *[]*model.Job, wg *sync.WaitGroup, mu *sync.Mutex, selectors model.SiteScrapingConfig, crawl *pageCrawl){
+	seenLinks := make(map[string]bool)
+
+	detailCollector.OnHTML("body", func(e *colly.HTMLElement) {
+		jobPtr, ok := e.Request.Ctx.GetAny("job").(*model.Job)
+		if !ok {
+			return
+		}
 
 		if selectors.JobDescriptionSelector != nil{
 			descriptionHTML:= e.ChildText(*selectors.JobDescriptionSelector)
@@ -43,10 +67,27 @@ func (s *JobScrapper) configureCollyCallbacks(c *colly.Collector, detailCollecto
 			} else {
 				logging.Logger.Debug().Str("job_title", jobPtr.Title).Str("requisition_id", jobIDstr).Msg("Parsed requisition ID")
 				jobPtr.RequisitionID = jobIDstr
+				jobPtr.SyntheticID = false
 			}
 		}
 	})
 
+	// Detail requests end in OnScraped and/or OnError; the per-request Once
+	// releases the WaitGroup exactly once, even when a detail page fails.
+	detailDone := func(ctx *colly.Context) {
+		if once, ok := ctx.GetAny("done").(*sync.Once); ok {
+			once.Do(wg.Done)
+		}
+	}
+	detailCollector.OnScraped(func(r *colly.Response) {
+		detailDone(r.Ctx)
+	})
+	detailCollector.OnError(func(r *colly.Response, err error) {
+		logging.Logger.Warn().Err(err).Str("url", r.Request.URL.String()).Msg("Failed to fetch job detail page")
+		s.stats.addDetailFailure()
+		detailDone(r.Ctx)
+	})
+
 	c.OnHTML(*selectors.JobListItemSelector, func(e *colly.HTMLElement) {
 		Title := e.ChildText(*selectors.TitleSelector)
 		JobLink := e.ChildAttr(*selectors.LinkSelector, *selectors.LinkAttribute)
@@ -68,61 +109,148 @@ func (s *JobScrapper) configureCollyCallbacks(c *colly.Collector, detailCollecto
 			JobLink:  JobLink,
 		}
 
+		var jobURL string
 		if JobLink != "" {
-			wg.Add(1)
-			jobURL := e.Request.AbsoluteURL(JobLink)
-			ctx := colly.NewContext()
-			ctx.Put("job", job)
-			detailCollector.Request("GET", jobURL, nil, ctx, nil)
+			jobURL = e.Request.AbsoluteURL(JobLink)
 		}
+
+		// Pages can repeat jobs (pinned postings, last page served again): keep the first.
 		mu.Lock()
+		if jobURL != "" && seenLinks[jobURL] {
+			mu.Unlock()
+			return
+		}
+		if jobURL != "" {
+			seenLinks[jobURL] = true
+		}
 		*jobs = append(*jobs, job)
 		mu.Unlock()
+		e.Request.Ctx.Put("newItems", e.Request.Ctx.GetAny("newItems").(int)+1)
+
+		if jobURL != "" && s.detailFilter.wants(job) {
+			wg.Add(1)
+			ctx := colly.NewContext()
+			ctx.Put("job", job)
+			ctx.Put("done", &sync.Once{})
+			if err := detailCollector.Request("GET", jobURL, nil, ctx, nil); err != nil {
+				logging.Logger.Debug().Err(err).Str("url", jobURL).Msg("Skipping job detail page")
+				wg.Done()
+			}
+		}
 	})
 
 	if selectors.NextPageSelector != nil {
 		c.OnHTML(*selectors.NextPageSelector, func(e *colly.HTMLElement) {
-			if e.Request.Ctx.Get("visitNextPage") == "true" {
-				nextPage := e.Request.AbsoluteURL(e.Attr("href"))
-				if nextPage != "" {
-					logging.Logger.Debug().Str("url", nextPage).Msg("Visiting next page")
-					e.Request.Ctx.Put("visitNextPage", "false")
-					e.Request.Visit(nextPage)
-				}
+			nextPage := e.Request.AbsoluteURL(e.Attr("href"))
+			if nextPage == "" {
+				return
+			}
+			if !crawl.claim(nextPage) {
+				logging.Logger.Debug().Str("url", nextPage).Msg("Next page already visited or page limit reached")
+				return
+			}
+			logging.Logger.Debug().Str("url", nextPage).Msg("Visiting next page")
+			visitListingPage(c, nextPage, e.Request.Ctx.GetAny("page").(int)+1)
+		})
+	}
+
+	if crawl.template != "" {
+		c.OnScraped(func(r *colly.Response) {
+			// A page without new jobs means we went past the last one.
+			if r.Ctx.GetAny("newItems").(int) == 0 {
+				return
+			}
+			page := r.Ctx.GetAny("page").(int) + 1
+			nextPage := crawl.numberedURL(page)
+			if !crawl.claim(nextPage) {
+				return
 			}
+			logging.Logger.Debug().Str("url", nextPage).Int("page", page).Msg("Visiting numbered page")
+			visitListingPage(c, nextPage, page)
 		})
 	}
 }
 
+// visitListingPage queues a listing page with its own context, carrying the page
+// number and the count of new jobs found on it.
+func visitListingPage(c *colly.Collector, pageURL string, page int) error {
+	ctx := colly.NewContext()
+	ctx.Put("page", page)
+	ctx.Put("newItems", 0)
+	err := c.Request("GET", pageURL, nil, ctx, nil)
+	if err != nil {
+		logging.Logger.Debug().Err(err).Str("url", pageURL).Msg("Skipping listing page")
+	}
+	return err
+}
+
+// captureListingError records the first listing page that could not be fetched
+// (policy skip, network error or error status), so the scrape fails instead of
+// looking like a run without jobs. The returned func reads it once the crawl is over.
+func captureListingError(c *colly.Collector) func() error {
+	var mu sync.Mutex
+	var first error
+	c.OnError(func(r *colly.Response, err error) {
+		logging.Logger.Warn().Err(err).Str("url", r.Request.URL.String()).Int("status", r.StatusCode).Msg("Failed to fetch listing page")
+		mu.Lock()
+		defer mu.Unlock()
+		if first != nil {
+			return
+		}
+		var skip *PolicySkipError
+		if errors.As(err, &skip) {
+			first = skip
+			return
+		}
+		if r.StatusCode != 0 {
+			first = fmt.Errorf("listing page %s returned status code %d: %w", r.Request.URL, r.StatusCode, err)
+			return
+		}
+		first = fmt.Errorf("failed to fetch listing page %s: %w", r.Request.URL, err)
+	})
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return first
+	}
+}
+
 func (s *JobScrapper) Scrape(ctx context.Context, config model.SiteScrapingConfig) ([]*model.Job, error) {
 	if config.JobListItemSelector == nil || config.TitleSelector == nil || config.LinkSelector == nil || config.LinkAttribute == nil {
 		return nil, fmt.Errorf("required selectors (JobListItemSelector, TitleSelector, LinkSelector, LinkAttribute) must not be nil for site %s", config.SiteName)
 	}
+	if config.PageURLTemplate != nil && *config.PageURLTemplate != "" && !strings.Contains(*config.PageURLTemplate, "{n}") {
+		return nil, fmt.Errorf("page_url_template must contain {n} for site %s", config.SiteName)
+	}
+
+	if err := s.politeness.Allowed(ctx, config.BaseURL); err != nil {
+		return nil, err
+	}
 
 	var jobs []*model.Job
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
 	c := colly.NewCollector(colly.Async(true))
+	s.politeness.applyTo(c, s.transport)
 	detailCollector := c.Clone()
 	c.Limit(&colly.LimitRule{DomainGlob: "*", Parallelism: 8})
 	c.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"
 
-	c.OnRequest(func(r *colly.Request){
-		r.Ctx.Put("visitNextPage", "true")
-	})
-
-	s.configureCollyCallbacks(c, detailCollector, &jobs, &wg, &mu, config)
+	crawl := newPageCrawl(config)
+	s.configureCollyCallbacks(c, detailCollector, &jobs, &wg, &mu, config, crawl)
+	listingErr := captureListingError(c)
 
 	done := make(chan error, 1)
 	go func() {
-		if err := c.Visit(config.BaseURL); err != nil {
+		crawl.claim(config.BaseURL)
+		if err := visitListingPage(c, config.BaseURL, 1); err != nil {
 			done <- err
 			return
 		}
 		c.Wait()
 		wg.Wait()
-		done <- nil
+		done <- listingErr()
 	}()
 
 	select {
@@ -0,0 +1,211 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cssConfig(baseURL string) model.SiteScrapingConfig {
+	return model.SiteScrapingConfig{
+		SiteName:                 "Acme",
+		BaseURL:                  baseURL,
+		ScrapingType:             "CSS",
+		JobListItemSelector:      strPtr("li.job"),
+		TitleSelector:            strPtr("a"),
+		LinkSelector:             strPtr("a"),
+		LinkAttribute:            strPtr("href"),
+		JobRequisitionIdSelector: strPtr(".req"),
+		NextPageSelector:         strPtr("a.next"),
+	}
+}
+
+// listingServer serves /jobs?page=N with two jobs per page up to lastPage, plus
+// a detail page for every job. nextLink builds the "next" href for page N.
+func listingServer(t *testing.T, lastPage int, nextLink func(page int) string) (*httptest.Server, *int32) {
+	var listings int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/jobs/") {
+			fmt.Fprintf(w, `<html><body><span class="req">%s</span></body></html>`, strings.TrimPrefix(r.URL.Path, "/jobs/"))
+			return
+		}
+		atomic.AddInt32(&listings, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		var b strings.Builder
+		b.WriteString("<html><body><ul>")
+		if page <= lastPage {
+			for i := 1; i <= 2; i++ {
+				id := fmt.Sprintf("%d-%d", page, i)
+				fmt.Fprintf(&b, `<li class="job"><a href="/jobs/%s">Job %s</a></li>`, id, id)
+			}
+		}
+		b.WriteString("</ul>")
+		if next := nextLink(page); next != "" {
+			fmt.Fprintf(&b, `<a class="next" href="%s">Próxima</a>`, next)
+		}
+		b.WriteString("</body></html>")
+		fmt.Fprint(w, b.String())
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &listings
+}
+
+func TestJobScrapper_Scrape_FollowsNextPageUntilItDisappears(t *testing.T) {
+	srv, _ := listingServer(t, 5, func(page int) string {
+		if page >= 5 {
+			return ""
+		}
+		return fmt.Sprintf("/jobs?page=%d", page+1)
+	})
+
+	jobs, err := NewJobScraper().Scrape(context.Background(), cssConfig(srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 10)
+	for _, job := range jobs {
+		assert.NotEmpty(t, job.RequisitionID)
+	}
+}
+
+func TestJobScrapper_Scrape_StopsAtMaxPages(t *testing.T) {
+	srv, listings := listingServer(t, 50, func(page int) string {
+		return fmt.Sprintf("/jobs?page=%d", page+1)
+	})
+	cfg := cssConfig(srv.URL + "/jobs")
+	maxPages := 3
+	cfg.MaxPages = &maxPages
+
+	jobs, err := NewJobScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 6)
+	assert.Equal(t, int32(3), atomic.LoadInt32(listings))
+}
+
+func TestJobScrapper_Scrape_DetectsPaginationLoops(t *testing.T) {
+	// Page 3 links back to page 1 through an equivalent URL.
+	srv, listings := listingServer(t, 3, func(page int) string {
+		if page == 3 {
+			return "/jobs?page=1&sort=#top"
+		}
+		return fmt.Sprintf("/jobs?sort=&page=%d", page+1)
+	})
+
+	jobs, err := NewJobScraper().Scrape(context.Background(), cssConfig(srv.URL+"/jobs?page=1&sort="))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 6)
+	assert.Equal(t, int32(3), atomic.LoadInt32(listings))
+}
+
+func TestJobScrapper_Scrape_PageURLTemplate(t *testing.T) {
+	srv, listings := listingServer(t, 4, func(int) string { return "" })
+	cfg := cssConfig(srv.URL + "/jobs")
+	cfg.NextPageSelector = nil
+	cfg.PageURLTemplate = strPtr(srv.URL + "/jobs?page={n}")
+
+	jobs, err := NewJobScraper().Scrape(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 8)
+	// Pages 1-4 have jobs; page 5 is empty and ends the crawl.
+	assert.Equal(t, int32(5), atomic.LoadInt32(listings))
+}
+
+func TestJobScrapper_Scrape_RejectsTemplateWithoutPlaceholder(t *testing.T) {
+	cfg := cssConfig("https://acme.com/jobs")
+	cfg.PageURLTemplate = strPtr("https://acme.com/jobs?page=2")
+
+	_, err := NewJobScraper().Scrape(context.Background(), cfg)
+
+	assert.ErrorContains(t, err, "{n}")
+}
+
+func TestJobScrapper_Scrape_FailingDetailPageDoesNotHang(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobs":
+			fmt.Fprint(w, `<html><body><ul><li class="job"><a href="/jobs/ok">OK</a></li><li class="job"><a href="/jobs/broken">Broken</a></li></ul></body></html>`)
+		case "/jobs/ok":
+			fmt.Fprint(w, `<html><body><span class="req">ok</span></body></html>`)
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	jobs, err := NewJobScraper().Scrape(ctx, cssConfig(srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+}
+
+func TestJobScrapper_Scrape_FailingListingPageFailsTheScrape(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	jobs, err := NewJobScraper().Scrape(context.Background(), cssConfig(srv.URL+"/jobs"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status code 502")
+	assert.Empty(t, jobs)
+}
+
+func TestJobScrapper_Scrape_BudgetExhaustedOnListingIsPolicySkip(t *testing.T) {
+	srv, hits := robotsServer(t, "")
+	s, err := NewScraperFactory(cssConfig(srv.URL+"/jobs"), WithPoliteness(NewPoliteness(PolitenessConfig{}, &recordingBudget{full: true})))
+	require.NoError(t, err)
+
+	_, err = s.Scrape(context.Background(), cssConfig(srv.URL+"/jobs"))
+
+	var skip *PolicySkipError
+	require.ErrorAs(t, err, &skip)
+	assert.Equal(t, PolicyBudgetExhausted, skip.Reason)
+	assert.Equal(t, model.ScrapeErrorPolicySkip, ClassifyError(err))
+	_, fetched := hits.Load("/jobs")
+	assert.False(t, fetched)
+}
+
+func TestNormalizePageURL(t *testing.T) {
+	assert.Equal(t, normalizePageURL("https://ACME.com/jobs?b=2&a=1#top"), normalizePageURL("https://acme.com/jobs?a=1&b=2"))
+	assert.Equal(t, "https://acme.com/", normalizePageURL("https://acme.com"))
+	assert.Empty(t, normalizePageURL("/jobs?page=2"))
+}
+
+func TestJobScrapper_Scrape_DetailFilterSkipsDetailPages(t *testing.T) {
+	srv, _ := robotsServer(t, "")
+	config := cssConfig(srv.URL + "/jobs")
+	s, err := NewScraperFactory(config, WithDetailFilter(func(job *model.Job) bool {
+		if job.JobLink == "/jobs/1" {
+			job.RequisitionID = "known-1"
+			return false
+		}
+		return true
+	}))
+	require.NoError(t, err)
+
+	jobs, err := s.Scrape(context.Background(), config)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	ids := []string{jobs[0].RequisitionID, jobs[1].RequisitionID}
+	assert.ElementsMatch(t, []string{"known-1", "/private/2"}, ids)
+}
@@ -0,0 +1,73 @@
+package scrapper
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"web-scrapper/model"
+)
+
+// ScrapeStats collects what a scrape reports besides its jobs. A nil *ScrapeStats
+// discards everything.
+type ScrapeStats struct {
+	detailFailures atomic.Int64
+}
+
+// WithStats makes the built scraper report into stats.
+func WithStats(stats *ScrapeStats) Option {
+	return func(o *factoryOptions) {
+		o.stats = stats
+	}
+}
+
+// DetailFailures is the number of detail pages that could not be fetched.
+func (s *ScrapeStats) DetailFailures() int {
+	if s == nil {
+		return 0
+	}
+	return int(s.detailFailures.Load())
+}
+
+func (s *ScrapeStats) addDetailFailure() {
+	if s != nil {
+		s.detailFailures.Add(1)
+	}
+}
+
+// statsScraper is implemented by scrapers that report ScrapeStats.
+type statsScraper interface {
+	useStats(stats *ScrapeStats)
+}
+
+// ClassifyError sorts a scrape error into one of the model.ScrapeError* classes,
+// "" for nil. Scrapers mostly wrap errors as text, so the message is inspected too.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	var skip *PolicySkipError
+	if errors.As(err, &skip) {
+		return model.ScrapeErrorPolicySkip
+	}
+	var netErr net.Error
+	msg := strings.ToLower(err.Error())
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), strings.Contains(msg, "timed out"), strings.Contains(msg, "timeout"):
+		return model.ScrapeErrorTimeout
+	case errors.As(err, &netErr), strings.Contains(msg, "connection refused"), strings.Contains(msg, "no such host"):
+		return model.ScrapeErrorNetwork
+	case strings.Contains(msg, "status code"):
+		return model.ScrapeErrorHTTPStatus
+	}
+	var jsonErr *json.SyntaxError
+	var xmlErr *xml.SyntaxError
+	if errors.As(err, &jsonErr) || errors.As(err, &xmlErr) || strings.Contains(msg, "invalid xml") ||
+		strings.Contains(msg, "parse") || strings.Contains(msg, "path not found") {
+		return model.ScrapeErrorParse
+	}
+	return model.ScrapeErrorOther
+}
@@ -0,0 +1,52 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrapeStats_CountsDetailFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobs":
+			fmt.Fprint(w, `<html><body><ul><li class="job"><a href="/jobs/1">Job 1</a></li><li class="job"><a href="/jobs/2">Job 2</a></li></ul></body></html>`)
+		case "/jobs/2":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			fmt.Fprint(w, `<html><body><span class="req">1</span></body></html>`)
+		}
+	}))
+	defer srv.Close()
+	stats := &ScrapeStats{}
+	s, err := NewScraperFactory(cssConfig(srv.URL+"/jobs"), WithStats(stats))
+	require.NoError(t, err)
+
+	jobs, err := s.Scrape(context.Background(), cssConfig(srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, 1, stats.DetailFailures())
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := map[string]error{
+		"":                          nil,
+		model.ScrapeErrorPolicySkip: fmt.Errorf("scrape: %w", &PolicySkipError{URL: "https://acme.com", Reason: PolicyRobotsDisallow}),
+		model.ScrapeErrorTimeout:    fmt.Errorf("scraping timed out for site Acme: %w", context.DeadlineExceeded),
+		model.ScrapeErrorNetwork:    errors.New("dial tcp 10.0.0.1:443: connect: connection refused"),
+		model.ScrapeErrorHTTPStatus: errors.New("unexpected status code to Acme: 503"),
+		model.ScrapeErrorParse:      errors.New("invalid XML at https://acme.com/feed: EOF"),
+		model.ScrapeErrorOther:      errors.New("scrap strategy not found: FOO"),
+	}
+	for class, err := range tests {
+		assert.Equal(t, class, ClassifyError(err), fmt.Sprint(err))
+	}
+}
@@ -0,0 +1,113 @@
+// ibgemunicipios downloads the list of Brazilian municipalities from the IBGE
+// localities API and writes it as the "municipio,uf" CSV embedded by the normalizer.
+//
+//	go run ./tools/ibgemunicipios -out normalizer/data/municipios.csv
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+const municipiosURL = "https://servicodados.ibge.gov.br/api/v1/localidades/municipios"
+
+// minMunicipios guards against writing a truncated or filtered response; Brazil
+// has about 5,570 municipalities.
+const minMunicipios = 5500
+
+// municipio is the part of the IBGE response we need. Newer municipalities have no
+// microrregiao, so the UF is read from the immediate region as well.
+type municipio struct {
+	Nome         string `json:"nome"`
+	Microrregiao *struct {
+		Mesorregiao struct {
+			UF uf `json:"UF"`
+		} `json:"mesorregiao"`
+	} `json:"microrregiao"`
+	RegiaoImediata *struct {
+		RegiaoIntermediaria struct {
+			UF uf `json:"UF"`
+		} `json:"regiao-intermediaria"`
+	} `json:"regiao-imediata"`
+}
+
+type uf struct {
+	Sigla string `json:"sigla"`
+}
+
+func (m municipio) uf() string {
+	if m.RegiaoImediata != nil && m.RegiaoImediata.RegiaoIntermediaria.UF.Sigla != "" {
+		return m.RegiaoImediata.RegiaoIntermediaria.UF.Sigla
+	}
+	if m.Microrregiao != nil {
+		return m.Microrregiao.Mesorregiao.UF.Sigla
+	}
+	return ""
+}
+
+func main() {
+	out := flag.String("out", "normalizer/data/municipios.csv", "CSV file to write")
+	flag.Parse()
+
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(municipiosURL)
+	if err != nil {
+		log.Fatalf("could not fetch municipalities: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("could not fetch municipalities: status %d", resp.StatusCode)
+	}
+
+	var municipios []municipio
+	if err := json.NewDecoder(resp.Body).Decode(&municipios); err != nil {
+		log.Fatalf("could not decode municipalities: %v", err)
+	}
+
+	records := make([][]string, 0, len(municipios))
+	for _, m := range municipios {
+		state := m.uf()
+		if m.Nome == "" || state == "" {
+			log.Fatalf("municipality without name or UF: %+v", m)
+		}
+		records = append(records, []string{m.Nome, state})
+	}
+	if len(records) < minMunicipios {
+		log.Fatalf("expected at least %d municipalities, got %d", minMunicipios, len(records))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i][1] != records[j][1] {
+			return records[i][1] < records[j][1]
+		}
+		return records[i][0] < records[j][0]
+	})
+
+	if err := writeCSV(*out, records); err != nil {
+		log.Fatalf("could not write %s: %v", *out, err)
+	}
+	log.Printf("wrote %d municipalities to %s", len(records), *out)
+}
+
+func writeCSV(path string, records [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"municipio", "uf"}); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.WriteAll(records); err != nil {
+		f.Close()
+		return fmt.Errorf("writing records: %w", err)
+	}
+	return f.Close()
+}
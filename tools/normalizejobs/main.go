@@ -0,0 +1,56 @@
+// normalizejobs runs the normalizer over the jobs already stored, filling the
+// structured fields of jobs scraped before it existed or after it changed. It also
+// sets the company key that new jobs are compared on to find cross-site duplicates.
+//
+//	DATABASE_URL=postgres://... go run ./tools/normalizejobs
+package main
+
+import (
+	"log"
+	"os"
+
+	"web-scrapper/infra/db"
+	"web-scrapper/normalizer"
+	"web-scrapper/repository"
+
+	"github.com/joho/godotenv"
+)
+
+const batchSize = 500
+
+func main() {
+	godotenv.Load()
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL not set")
+	}
+	conn, err := db.ConnectDBFromURL(dbURL)
+	if err != nil {
+		log.Fatalf("could not connect to db: %v", err)
+	}
+	defer conn.Close()
+	jobRepo := repository.NewJobRepository(conn)
+
+	lastID, updated := 0, 0
+	for {
+		jobs, err := jobRepo.GetJobsToNormalize(lastID, batchSize)
+		if err != nil {
+			log.Fatalf("could not read jobs after %d: %v", lastID, err)
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		for _, job := range jobs {
+			normalizer.Normalize(&job)
+			job.CompanyKey = normalizer.CompanyKey(job.Company)
+			if err := jobRepo.UpdateNormalizedFields(job); err != nil {
+				log.Fatalf("could not update job %d: %v", job.ID, err)
+			}
+			lastID = job.ID
+			updated++
+		}
+		log.Printf("normalized %d jobs", updated)
+	}
+	log.Printf("done: %d jobs normalized", updated)
+}
@@ -0,0 +1,127 @@
+// Command scrapefixture records a site scrape into a fixture directory and
+// replays fixtures offline against their golden files.
+//
+//	go run ./tools/scrapefixture record -config site.json -dir scrapper/testdata/fixtures/acme
+//	go run ./tools/scrapefixture replay -dir scrapper/testdata/fixtures/acme [-update]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+	"web-scrapper/model"
+	"web-scrapper/scrapper"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "record":
+		record(os.Args[2:])
+	case "replay":
+		replay(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: scrapefixture record -config site.json -dir DIR")
+	fmt.Fprintln(os.Stderr, "       scrapefixture replay -dir DIR [-update]")
+	os.Exit(2)
+}
+
+// record scrapes the live site, saving every response, the config and the
+// extracted jobs as the golden file.
+func record(args []string) {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	configPath := fs.String("config", "", "JSON file with the SiteScrapingConfig to scrape")
+	dir := fs.String("dir", "", "fixture directory to write")
+	timeout := fs.Duration("timeout", 5*time.Minute, "scrape timeout")
+	fs.Parse(args)
+	if *configPath == "" || *dir == "" {
+		usage()
+	}
+
+	data, err := os.ReadFile(*configPath)
+	if err != nil {
+		log.Fatalf("could not read config: %v", err)
+	}
+	var config model.SiteScrapingConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		log.Fatalf("could not parse config: %v", err)
+	}
+
+	rec, err := scrapper.NewRecorder(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	politeness := scrapper.NewPoliteness(scrapper.PolitenessConfig{}, nil)
+	s, err := scrapper.NewScraperFactory(config, scrapper.WithRecorder(rec), scrapper.WithPoliteness(politeness))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	jobs, err := s.Scrape(ctx, config)
+	if closeErr := rec.Close(); closeErr != nil {
+		log.Fatal(closeErr)
+	}
+	if err != nil {
+		log.Fatalf("scrape failed: %v", err)
+	}
+
+	scrapper.SortJobs(jobs)
+	if err := scrapper.WriteFixtureConfig(*dir, config); err != nil {
+		log.Fatal(err)
+	}
+	if err := scrapper.WriteGolden(*dir, jobs); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Recorded %d jobs from %s into %s", len(jobs), config.SiteName, *dir)
+}
+
+// replay re-runs the fixture's scraper offline and exits with status 1 when the
+// jobs differ from the golden file.
+func replay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	dir := fs.String("dir", "", "fixture directory to replay")
+	update := fs.Bool("update", false, "rewrite the golden file with the replayed jobs")
+	fs.Parse(args)
+	if *dir == "" {
+		usage()
+	}
+
+	jobs, err := scrapper.ReplayFixture(context.Background(), *dir)
+	if err != nil {
+		log.Fatalf("replay failed: %v", err)
+	}
+
+	if *update {
+		if err := scrapper.WriteGolden(*dir, jobs); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Golden file of %s updated with %d jobs", *dir, len(jobs))
+		return
+	}
+
+	diffs, err := scrapper.DiffGolden(*dir, jobs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(diffs) > 0 {
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+		os.Exit(1)
+	}
+	log.Printf("%s: %d jobs match the golden file", *dir, len(jobs))
+}
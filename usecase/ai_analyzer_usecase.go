@@ -89,11 +89,25 @@ func prompt_builder(curriculum model.Curriculum, job model.Job) (string, error)
 		Title           string `json:"title"`
 		Company         string `json:"company"`
 		Location        string `json:"location"`
+		City            string `json:"city,omitempty"`
+		State           string `json:"state,omitempty"`
+		Country         string `json:"country,omitempty"`
+		WorkModel       string `json:"work_model,omitempty"`
+		Seniority       string `json:"seniority,omitempty"`
+		RoleFamily      string `json:"role_family,omitempty"`
+		ContractType    string `json:"contract_type,omitempty"`
 		DescriptionFull string `json:"description_full"`
 	}{
 		Title:           job.Title,
 		Company:         job.Company,
 		Location:        job.Location,
+		City:            job.LocationCity,
+		State:           job.LocationState,
+		Country:         job.LocationCountry,
+		WorkModel:       job.WorkModel,
+		Seniority:       job.Seniority,
+		RoleFamily:      job.RoleFamily,
+		ContractType:    job.ContractType,
 		DescriptionFull: job.Description,
 	}
 
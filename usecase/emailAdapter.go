@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"html"
 	"os"
 	"strings"
 	"text/template"
@@ -164,7 +165,11 @@ func generateNewJobsEmailBodyText(userName string, jobs []*model.Job) string {
 	sb.WriteString(fmt.Sprintf("Novas vagas encontradas para você, %s!\n\n", userName))
 	sb.WriteString(fmt.Sprintf("Encontramos %d nova(s) vaga(s) nos sites que você está monitorando:\n\n", len(jobs)))
 	for i, job := range jobs {
-		sb.WriteString(fmt.Sprintf("%d. %s — %s (%s)\n   Link: %s\n\n", i+1, job.Title, job.Company, job.Location, job.JobLink))
+		sb.WriteString(fmt.Sprintf("%d. %s — %s (%s)\n   Link: %s\n", i+1, job.Title, job.Company, job.Location, job.JobLink))
+		for _, link := range job.AlternateLinks {
+			sb.WriteString(fmt.Sprintf("   Também em: %s\n", link))
+		}
+		sb.WriteString("\n")
 	}
 	sb.WriteString("Acesse seu painel no ScrapJobs para analisar essas vagas com IA.\n\n")
 	sb.WriteString("Atenciosamente,\nEquipe ScrapJobs\n")
@@ -222,3 +227,27 @@ func (adapter *SESSenderAdapter) SendPasswordResetEmail(ctx context.Context, ema
 
 	return adapter.mailSender.SendEmail(ctx, email, subject, bodyText, bodyHTML)
 }
+
+func generateSiteAnomalyEmailBodyText(anomaly model.SiteAnomaly, adminLink string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Anomalia detectada no scraping do site %s (ID %d).\n\n", anomaly.SiteName, anomaly.SiteID))
+	sb.WriteString(fmt.Sprintf("Tipo: %s\n%s\n\n", anomaly.Kind, anomaly.Message))
+	if anomaly.Disabled {
+		sb.WriteString("O site foi desativado e só volta a ser raspado depois de revalidado no sandbox.\n\n")
+	}
+	sb.WriteString("Veja o histórico de execuções do site em:\n" + adminLink + "\n")
+	return sb.String()
+}
+
+func (adapter *SESSenderAdapter) SendSiteAnomalyEmail(ctx context.Context, adminEmail string, anomaly model.SiteAnomaly) error {
+	subject := fmt.Sprintf("ScrapJobs — Anomalia no site %s", anomaly.SiteName)
+	if anomaly.Disabled {
+		subject += " (desativado)"
+	}
+
+	adminLink := os.Getenv("FRONTEND_URL") + "/admin"
+	bodyText := generateSiteAnomalyEmailBodyText(anomaly, adminLink)
+	bodyHTML := "<pre>" + html.EscapeString(bodyText) + "</pre>"
+
+	return adapter.mailSender.SendEmail(ctx, adminEmail, subject, bodyText, bodyHTML)
+}
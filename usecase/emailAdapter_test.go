@@ -142,6 +142,18 @@ func TestGenerateNewJobsEmailBodyHTML(t *testing.T) {
 		assert.Contains(t, html, "Job2")
 		assert.Contains(t, html, "Job3")
 	})
+
+	t.Run("should link the same opening on other sites", func(t *testing.T) {
+		jobs := []*model.Job{
+			{Title: "Go Dev", Company: "Acme", Location: "SP", JobLink: "https://acme.com/1", AlternateLinks: []string{"https://acme.gupy.io/jobs/9"}},
+		}
+
+		html, err := generateNewJobsEmailBodyHTML("Ana", jobs)
+
+		assert.NoError(t, err)
+		assert.Contains(t, html, `href="https://acme.gupy.io/jobs/9"`)
+		assert.Contains(t, html, "Também publicada em outro site")
+	})
 }
 
 func TestGenerateNewJobsEmailBodyText(t *testing.T) {
@@ -158,5 +170,16 @@ func TestGenerateNewJobsEmailBodyText(t *testing.T) {
 		assert.Contains(t, text, "Remote")
 		assert.Contains(t, text, "https://acme.com/1")
 		assert.Contains(t, text, "1 nova(s) vaga(s)")
+		assert.NotContains(t, text, "Também em")
+	})
+
+	t.Run("should list alternate links", func(t *testing.T) {
+		jobs := []*model.Job{
+			{Title: "Go Dev", Company: "Acme", Location: "SP", JobLink: "https://acme.com/1", AlternateLinks: []string{"https://acme.gupy.io/jobs/9"}},
+		}
+
+		text := generateNewJobsEmailBodyText("Pedro", jobs)
+
+		assert.Contains(t, text, "Também em: https://acme.gupy.io/jobs/9")
 	})
 }
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+)
+
+// syntheticIDPrefix marks requisition IDs we generated because the site has none.
+const syntheticIDPrefix = "syn-"
+
+// trackingParams are query parameters that change between runs (or between links
+// to the same posting) without identifying the job.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "msclkid": true, "mc_cid": true, "mc_eid": true,
+	"_hsenc": true, "_hsmi": true, "ref": true, "referrer": true, "source": true,
+	"src": true, "trk": true, "trackingid": true, "gh_src": true, "lever-source": true,
+	"lever-origin": true, "sessionid": true, "jsessionid": true,
+}
+
+// ensureRequisitionIDs gives every job without a requisition ID a deterministic
+// synthetic one and drops jobs repeated within the batch, so they are deduplicated
+// the same way on every run. Relative links are resolved against baseURL. Jobs
+// with neither a link nor a title are dropped.
+func ensureRequisitionIDs(jobs []*model.Job, baseURL string) []*model.Job {
+	seen := make(map[string]bool, len(jobs))
+	result := make([]*model.Job, 0, len(jobs))
+	for _, job := range jobs {
+		job.RequisitionID = strings.TrimSpace(job.RequisitionID)
+		if job.RequisitionID == "" {
+			id := syntheticRequisitionID(job, baseURL)
+			if id == "" {
+				logging.Logger.Warn().Str("job_link", job.JobLink).Msg("Dropping job without requisition ID, link or title")
+				continue
+			}
+			job.RequisitionID = id
+			job.SyntheticID = true
+		}
+		if seen[job.RequisitionID] {
+			continue
+		}
+		seen[job.RequisitionID] = true
+		result = append(result, job)
+	}
+	return result
+}
+
+// syntheticRequisitionID hashes the canonical job link, or the title and location
+// when the job has no link.
+func syntheticRequisitionID(job *model.Job, baseURL string) string {
+	key := canonicalJobLink(resolveJobLink(baseURL, job.JobLink))
+	if key == "" {
+		title := normalizeIdentityText(job.Title)
+		if title == "" {
+			return ""
+		}
+		key = "title:" + title + "|" + normalizeIdentityText(job.Location)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return syntheticIDPrefix + hex.EncodeToString(sum[:])[:24]
+}
+
+// resolveJobLink makes a relative link absolute against the site's base URL, so
+// "/jobs/42" is hashed as the posting it points to. Unparseable links are kept.
+func resolveJobLink(baseURL, rawLink string) string {
+	link, err := url.Parse(strings.TrimSpace(rawLink))
+	if err != nil || link.IsAbs() || link.String() == "" {
+		return rawLink
+	}
+	base, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || base.Host == "" {
+		return rawLink
+	}
+	return base.ResolveReference(link).String()
+}
+
+// canonicalJobLink lowercases scheme and host, drops the fragment, tracking
+// parameters and a trailing slash, and sorts the remaining query.
+func canonicalJobLink(rawLink string) string {
+	u, err := url.Parse(strings.TrimSpace(rawLink))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	query := u.Query()
+	for param := range query {
+		name := strings.ToLower(param)
+		if trackingParams[name] || strings.HasPrefix(name, "utm_") {
+			query.Del(param)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// contentHash fingerprints what users read in a posting, ignoring whitespace-only
+// changes, so an edited posting can be told apart from a re-scraped one.
+func contentHash(job *model.Job) string {
+	content := normalizeContentText(job.Title) + "\n" + normalizeContentText(job.Location) + "\n" + normalizeContentText(job.Description)
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeContentText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func normalizeIdentityText(s string) string {
+	return strings.ToLower(normalizeContentText(s))
+}
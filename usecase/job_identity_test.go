@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"web-scrapper/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJobLink(t *testing.T) {
+	canonical := canonicalJobLink("https://Acme.com/jobs/42/?utm_source=linkedin&b=2&a=1&gh_src=abc#apply")
+
+	assert.Equal(t, "https://acme.com/jobs/42?a=1&b=2", canonical)
+	assert.Equal(t, canonical, canonicalJobLink("https://acme.com/jobs/42?a=1&b=2&fbclid=xyz"))
+	assert.Empty(t, canonicalJobLink("/jobs/42"))
+}
+
+func TestEnsureRequisitionIDs(t *testing.T) {
+	jobs := []*model.Job{
+		{Title: "Go Dev", JobLink: "https://acme.com/jobs/1?utm_campaign=x", RequisitionID: " 123 "},
+		{Title: "QA", JobLink: "https://acme.com/jobs/2?utm_campaign=x"},
+		{Title: "QA", JobLink: "https://acme.com/jobs/2?utm_campaign=y"},
+		{Title: "Data  Engineer", Location: "São Paulo"},
+		{},
+	}
+
+	result := ensureRequisitionIDs(jobs, "https://acme.com/careers")
+
+	require.Len(t, result, 3)
+	assert.Equal(t, "123", result[0].RequisitionID)
+	assert.False(t, result[0].SyntheticID)
+	for _, job := range result[1:] {
+		assert.True(t, job.SyntheticID)
+		assert.True(t, strings.HasPrefix(job.RequisitionID, syntheticIDPrefix))
+	}
+}
+
+func TestEnsureRequisitionIDs_StableAcrossRuns(t *testing.T) {
+	firstRun := ensureRequisitionIDs([]*model.Job{
+		{Title: "QA", JobLink: "https://acme.com/jobs/2?ref=home"},
+		{Title: "Data Engineer", Location: "São Paulo"},
+	}, "https://acme.com")
+	secondRun := ensureRequisitionIDs([]*model.Job{
+		{Title: "QA (updated)", JobLink: "https://ACME.com/jobs/2/"},
+		{Title: "data engineer ", Location: "são paulo"},
+	}, "https://acme.com")
+
+	assert.Equal(t, firstRun[0].RequisitionID, secondRun[0].RequisitionID)
+	assert.Equal(t, firstRun[1].RequisitionID, secondRun[1].RequisitionID)
+	assert.NotEqual(t, firstRun[0].RequisitionID, firstRun[1].RequisitionID)
+}
+
+func TestEnsureRequisitionIDs_ResolvesRelativeLinks(t *testing.T) {
+	jobs := ensureRequisitionIDs([]*model.Job{
+		{Title: "Analista de Dados", Location: "São Paulo", JobLink: "/jobs/41"},
+		{Title: "Analista de Dados", Location: "São Paulo", JobLink: "/jobs/42"},
+	}, "https://acme.com/careers")
+
+	require.Len(t, jobs, 2)
+	assert.NotEqual(t, jobs[0].RequisitionID, jobs[1].RequisitionID)
+
+	absolute := ensureRequisitionIDs([]*model.Job{{Title: "Analista de Dados", JobLink: "https://acme.com/jobs/41"}}, "")
+	assert.Equal(t, absolute[0].RequisitionID, jobs[0].RequisitionID)
+}
@@ -2,20 +2,28 @@ package usecase
 
 import (
 	"context"
+	"strings"
 	"time"
 	"web-scrapper/interfaces"
 	"web-scrapper/logging"
 	"web-scrapper/model"
+	"web-scrapper/normalizer"
 	"web-scrapper/scrapper"
 )
 
 type JobUseCase struct{
 	Repository interfaces.JobRepositoryInterface
+	// DetailTTL is how long a stored job's details are reused before its detail page is fetched again.
+	DetailTTL time.Duration
+	scraperOptions []scrapper.Option
 }
 
-func NewJobUseCase(jobRepo interfaces.JobRepositoryInterface) *JobUseCase{
+// NewJobUseCase builds the use case; scraperOptions are passed to every scraper it creates.
+func NewJobUseCase(jobRepo interfaces.JobRepositoryInterface, scraperOptions ...scrapper.Option) *JobUseCase{
 	return &JobUseCase{
 		Repository: jobRepo,
+		DetailTTL: defaultDetailTTL,
+		scraperOptions: scraperOptions,
 	}
 }
 
@@ -28,8 +36,8 @@ func (job JobUseCase) CreateJob(jobData model.Job) (int, error){
 	return jobID, nil
 }
 
-func (job JobUseCase) FindJobByRequisitionID(requisition_ID string) (bool, error){
-	hasJob, err := job.Repository.FindJobByRequisitionID(requisition_ID);
+func (job JobUseCase) FindJobByRequisitionID(siteID int, requisition_ID string) (bool, error){
+	hasJob, err := job.Repository.FindJobByRequisitionID(siteID, requisition_ID);
 	if(err != nil){
 		return false, err
 	}
@@ -38,28 +46,64 @@ func (job JobUseCase) FindJobByRequisitionID(requisition_ID string) (bool, error
 }
 
 
-func (uc *JobUseCase) ScrapeAndStoreJobs(ctx context.Context, selectors model.SiteScrapingConfig) ([]*model.Job, error) {
+// ScrapeAndStoreJobs scrapes the site, stores new jobs and refreshes known ones. The
+// counts are filled in as far as the run got, also when it fails.
+func (uc *JobUseCase) ScrapeAndStoreJobs(ctx context.Context, selectors model.SiteScrapingConfig) ([]*model.Job, model.ScrapeCounts, error) {
     ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
     defer cancel()
 
-    scrapInterface, err := scrapper.NewScraperFactory(selectors)
+    var counts model.ScrapeCounts
+    stats := &scrapper.ScrapeStats{}
+    options := append([]scrapper.Option{scrapper.WithStats(stats)}, uc.scraperOptions...)
+    var filter *knownJobFilter
+    if scrapper.UsesDetailPages(selectors) {
+		known, err := uc.Repository.FindKnownJobs(selectors.ID)
+		if err != nil {
+			// Without the stored jobs every detail page is fetched, as before
+			logging.Logger.Warn().Err(err).Int("site_id", selectors.ID).Msg("Failed to load known jobs, fetching all detail pages")
+		}
+		filter = newKnownJobFilter(known, uc.DetailTTL)
+		options = append(options, scrapper.WithDetailFilter(filter.wants))
+    }
+
+    scrapInterface, err := scrapper.NewScraperFactory(selectors, options...)
     if err != nil {
-        return nil, err
+        return nil, counts, err
     }
 
 	jobs, err := scrapInterface.Scrape(ctx, selectors)
+	counts.DetailFailures = stats.DetailFailures()
 	if err != nil {
-		return []*model.Job{}, err
+		return []*model.Job{}, counts, err
+	}
+	jobs = ensureRequisitionIDs(jobs, selectors.BaseURL)
+	for _, job := range jobs {
+		normalizer.Normalize(job)
+	}
+	counts.Listed = len(jobs)
+	for _, job := range jobs {
+		if strings.TrimSpace(job.Title) == "" {
+			counts.EmptyTitles++
+		}
 	}
 
     var newJobsToDatabase []*model.Job
+    var createdJobs []model.Job
+    var refreshedJobIDs []int
 	ids := takeIDs(jobs)
-	exist, err := uc.Repository.FindJobsByRequisitionIDs(ids)
+	exist, err := uc.Repository.FindJobsByRequisitionIDs(selectors.ID, ids)
 	if err != nil{
-		return nil, err
+		return nil, counts, err
+	}
+	// Counted before the jobs of this run are touched, so reopened jobs are not missing
+	counts.Missing, err = uc.Repository.CountMissingJobs(selectors.ID, ids)
+	if err != nil {
+		logging.Logger.Warn().Err(err).Int("site_id", selectors.ID).Msg("Failed to count missing jobs")
 	}
     for _, job := range jobs {
-        if _ , ok := exist[job.RequisitionID]; !ok {
+		job.ContentHash = contentHash(job)
+        storedHash, ok := exist[job.RequisitionID]
+        if !ok {
 			jobToInsert := model.Job{
 				SiteID:        selectors.ID,
 				Title:         job.Title,
@@ -67,23 +111,100 @@ func (uc *JobUseCase) ScrapeAndStoreJobs(ctx context.Context, selectors model.Si
 				Company:       selectors.SiteName,
 				JobLink:       job.JobLink,
 				RequisitionID: job.RequisitionID,
+				SyntheticID:   job.SyntheticID,
+				Description:   job.Description,
+				ContentHash:   job.ContentHash,
+				EmploymentType: job.EmploymentType,
+				DatePosted:     job.DatePosted,
+				SalaryMin:      job.SalaryMin,
+				SalaryMax:      job.SalaryMax,
+				SalaryCurrency: job.SalaryCurrency,
+				SalaryPeriod:   job.SalaryPeriod,
+				SalaryBasis:    job.SalaryBasis,
+				Benefits:       job.Benefits,
+				LocationCity:    job.LocationCity,
+				LocationState:   job.LocationState,
+				LocationCountry: job.LocationCountry,
+				WorkModel:       job.WorkModel,
+				Seniority:       job.Seniority,
+				RoleFamily:      job.RoleFamily,
+				ContractType:    job.ContractType,
+				CompanyKey:      normalizer.CompanyKey(selectors.SiteName),
 			}
             ID, err := uc.Repository.CreateJob(jobToInsert)
 			if err != nil {
 				logging.Logger.Error().Err(err).Str("job_title", job.Title).Msg("Failed to create job")
+			} else {
+				counts.New++
+				jobToInsert.ID = ID
+				createdJobs = append(createdJobs, jobToInsert)
 			}
 			job.ID = ID
 			newJobsToDatabase = append(newJobsToDatabase, job)
         }else{
-			jobID, err := uc.Repository.UpdateLastSeen(job.RequisitionID)
+			jobID, err := uc.Repository.UpdateLastSeen(selectors.ID, job.RequisitionID)
 			if err != nil {
-				logging.Logger.Error().Err(err).Str("requisition_id", job.RequisitionID).Msg("Failed to update last seen")
+				logging.Logger.Error().Err(err).Int("site_id", selectors.ID).Str("requisition_id", job.RequisitionID).Msg("Failed to update last seen")
 			}
 			job.ID = jobID
+			if jobID != 0 && filter != nil && !filter.detailsSkipped(job.RequisitionID) {
+				refreshedJobIDs = append(refreshedJobIDs, jobID)
+			}
+			// Without a description the detail page may just have failed: keep the stored content
+			if jobID != 0 && job.Description != "" && job.ContentHash != storedHash {
+				if err := uc.Repository.UpdateJobContent(jobID, *job); err != nil {
+					logging.Logger.Error().Err(err).Int("job_id", jobID).Msg("Failed to update job content")
+				} else {
+					counts.Updated++
+				}
+			}
 			newJobsToDatabase = append(newJobsToDatabase, job)
 		}
     }
-    return newJobsToDatabase, nil
+
+    uc.clusterDuplicates(selectors, createdJobs)
+
+    if len(refreshedJobIDs) > 0 {
+		if err := uc.Repository.MarkDetailsFetched(refreshedJobIDs); err != nil {
+			logging.Logger.Error().Err(err).Int("site_id", selectors.ID).Msg("Failed to mark job details as fetched")
+		}
+    }
+    if filter != nil {
+		logging.Logger.Info().Str("site_name", selectors.SiteName).Int("jobs", len(jobs)).Int("details_skipped", filter.skippedCount()).Msg("Detail pages skipped for known jobs")
+    }
+    return newJobsToDatabase, counts, nil
+}
+
+// clusterDuplicates puts each job just created under the same opening already
+// stored from another site of the company, so users are notified once. Failures
+// only leave the job unclustered.
+func (uc *JobUseCase) clusterDuplicates(selectors model.SiteScrapingConfig, created []model.Job) {
+	companyKey := normalizer.CompanyKey(selectors.SiteName)
+	if len(created) == 0 || companyKey == "" {
+		return
+	}
+	candidates, err := uc.Repository.FindDuplicateCandidates(selectors.ID, companyKey)
+	if err != nil {
+		logging.Logger.Warn().Err(err).Int("site_id", selectors.ID).Msg("Failed to load duplicate candidates")
+		return
+	}
+	for _, job := range created {
+		for _, candidate := range candidates {
+			if !normalizer.IsDuplicate(job, candidate) {
+				continue
+			}
+			canonicalID := candidate.ID
+			if candidate.CanonicalJobID != nil {
+				canonicalID = *candidate.CanonicalJobID
+			}
+			if err := uc.Repository.SetCanonicalJob(job.ID, canonicalID); err != nil {
+				logging.Logger.Error().Err(err).Int("job_id", job.ID).Msg("Failed to cluster duplicate job")
+			} else {
+				logging.Logger.Info().Int("job_id", job.ID).Int("canonical_job_id", canonicalID).Msg("Job clustered with a posting from another site")
+			}
+			break
+		}
+	}
 }
 
 func takeIDs(jobs []*model.Job) []string{
@@ -1,12 +1,21 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 	"web-scrapper/model"
 	"web-scrapper/repository/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJobUseCase_CreateJob(t *testing.T) {
@@ -50,9 +59,9 @@ func TestJobUseCase_FindJobByRequisitionID(t *testing.T) {
 	uc := NewJobUseCase(mockRepo)
 
 	t.Run("should return true when job exists", func(t *testing.T) {
-		mockRepo.On("FindJobByRequisitionID", "12345").Return(true, nil).Once()
+		mockRepo.On("FindJobByRequisitionID", 1, "12345").Return(true, nil).Once()
 
-		found, err := uc.FindJobByRequisitionID("12345")
+		found, err := uc.FindJobByRequisitionID(1, "12345")
 
 		assert.NoError(t, err)
 		assert.True(t, found)
@@ -60,12 +69,193 @@ func TestJobUseCase_FindJobByRequisitionID(t *testing.T) {
 	})
 
 	t.Run("should return false when job not found", func(t *testing.T) {
-		mockRepo.On("FindJobByRequisitionID", "99999").Return(false, nil).Once()
+		mockRepo.On("FindJobByRequisitionID", 1, "99999").Return(false, nil).Once()
 
-		found, err := uc.FindJobByRequisitionID("99999")
+		found, err := uc.FindJobByRequisitionID(1, "99999")
 
 		assert.NoError(t, err)
 		assert.False(t, found)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func strPtr(s string) *string { return &s }
+
+// cssSiteConfig is a CSS config for the listings served by jobListingServer.
+func cssSiteConfig(id int, siteName string, baseURL string) model.SiteScrapingConfig {
+	return model.SiteScrapingConfig{
+		ID:                       id,
+		SiteName:                 siteName,
+		BaseURL:                  baseURL,
+		ScrapingType:             "CSS",
+		JobListItemSelector:      strPtr("li"),
+		TitleSelector:            strPtr("a"),
+		LinkSelector:             strPtr("a"),
+		LinkAttribute:            strPtr("href"),
+		JobRequisitionIdSelector: strPtr(".req"),
+	}
+}
+
+// jobListingServer serves items as the <ul> of /jobs, and the detail page built by
+// detail for every /jobs/{id}. A nil detail serves just the requisition ID.
+func jobListingServer(t *testing.T, items string, detail func(id string) string) *httptest.Server {
+	if detail == nil {
+		detail = func(id string) string { return fmt.Sprintf(`<span class="req">%s</span>`, id) }
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jobs" {
+			fmt.Fprintf(w, `<html><body><ul>%s</ul></body></html>`, items)
+			return
+		}
+		fmt.Fprintf(w, `<html><body>%s</body></html>`, detail(strings.TrimPrefix(r.URL.Path, "/jobs/")))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// scrapeMocks expects the reads every ScrapeAndStoreJobs run makes for siteID: the
+// known jobs, the content hashes of the stored ones and the count of missing jobs.
+func scrapeMocks(siteID int, known []model.KnownJob, stored map[string]string) (*mocks.MockJobRepository, *JobUseCase) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockRepo.On("FindKnownJobs", siteID).Return(known, nil).Once()
+	mockRepo.On("FindJobsByRequisitionIDs", siteID, mock.Anything).Return(stored, nil).Once()
+	return mockRepo, NewJobUseCase(mockRepo)
+}
+
+func TestJobUseCase_ScrapeAndStoreJobs_ScopesIdentityToSite(t *testing.T) {
+	srv := jobListingServer(t, `<li><a href="/jobs/100">Go Dev</a></li><li><a href="/jobs/200">QA</a></li>`, nil)
+
+	// "100" is already stored for site 7; another site owning "200" must not matter
+	mockRepo, uc := scrapeMocks(7, []model.KnownJob{}, map[string]string{"100": ""})
+	mockRepo.On("CountMissingJobs", 7, mock.Anything).Return(0, nil).Once()
+	mockRepo.On("UpdateLastSeen", 7, "100").Return(1, nil).Once()
+	mockRepo.On("MarkDetailsFetched", []int{1}).Return(nil).Once()
+	mockRepo.On("CreateJob", mock.MatchedBy(func(job model.Job) bool {
+		return job.SiteID == 7 && job.RequisitionID == "200"
+	})).Return(2, nil).Once()
+	mockRepo.On("FindDuplicateCandidates", 7, "acme").Return([]model.Job{}, nil).Once()
+
+	jobs, _, err := uc.ScrapeAndStoreJobs(context.Background(), cssSiteConfig(7, "Acme", srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobUseCase_ScrapeAndStoreJobs_TracksContentChanges(t *testing.T) {
+	srv := jobListingServer(t, `<li><a href="/jobs/100">Go Dev</a></li><li><a href="/jobs/200">QA</a></li><li><a href="/jobs/300">SRE</a></li>`, func(id string) string {
+		if id == "300" {
+			return `<span class="req">300</span>`
+		}
+		return fmt.Sprintf(`<span class="req">%s</span><div class="desc">Descrição da vaga %s</div>`, id, id)
+	})
+	config := cssSiteConfig(7, "Acme", srv.URL+"/jobs")
+	config.JobDescriptionSelector = strPtr(".desc")
+	unchanged := contentHash(&model.Job{Title: "Go Dev", Description: "Descrição da vaga 100"})
+
+	mockRepo, uc := scrapeMocks(7, []model.KnownJob{}, map[string]string{"100": unchanged, "200": "old", "300": "old"})
+	mockRepo.On("CountMissingJobs", 7, []string{"100", "200", "300"}).Return(4, nil).Once()
+	mockRepo.On("MarkDetailsFetched", mock.Anything).Return(nil).Once()
+	mockRepo.On("UpdateLastSeen", 7, "100").Return(1, nil).Once()
+	mockRepo.On("UpdateLastSeen", 7, "200").Return(2, nil).Once()
+	mockRepo.On("UpdateLastSeen", 7, "300").Return(3, nil).Once()
+	// Only the edited posting gets a new revision; 300 lost its description (failed detail) and is kept
+	mockRepo.On("UpdateJobContent", 2, mock.MatchedBy(func(job model.Job) bool {
+		return job.Description == "Descrição da vaga 200" && job.ContentHash != "old"
+	})).Return(nil).Once()
+
+	_, counts, err := uc.ScrapeAndStoreJobs(context.Background(), config)
+
+	require.NoError(t, err)
+	assert.Equal(t, model.ScrapeCounts{Listed: 3, Updated: 1, Missing: 4}, counts)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobUseCase_ScrapeAndStoreJobs_SkipsDetailsOfKnownJobs(t *testing.T) {
+	var detailHits sync.Map
+	srv := jobListingServer(t, `<li><a href="/jobs/100">Go Dev</a></li><li><a href="/jobs/200">QA</a></li><li><a href="/jobs/300">SRE</a></li>`, func(id string) string {
+		detailHits.Store(id, true)
+		return fmt.Sprintf(`<span class="req">%s</span>`, id)
+	})
+	fresh := time.Now().Add(-time.Hour)
+	stale := time.Now().Add(-8 * 24 * time.Hour)
+
+	mockRepo, uc := scrapeMocks(7, []model.KnownJob{
+		{ID: 1, RequisitionID: "100", JobLink: "/jobs/100", DetailsFetchedAt: &fresh},
+		{ID: 2, RequisitionID: "200", JobLink: "/jobs/200", DetailsFetchedAt: &stale},
+	}, map[string]string{"100": "", "200": ""})
+	mockRepo.On("CountMissingJobs", 7, mock.Anything).Return(0, nil).Once()
+	mockRepo.On("UpdateLastSeen", 7, "100").Return(1, nil).Once()
+	mockRepo.On("UpdateLastSeen", 7, "200").Return(2, nil).Once()
+	mockRepo.On("CreateJob", mock.MatchedBy(func(job model.Job) bool { return job.RequisitionID == "300" })).Return(3, nil).Once()
+	mockRepo.On("FindDuplicateCandidates", 7, "acme").Return([]model.Job{}, nil).Once()
+	// Only the stale job had its details refreshed
+	mockRepo.On("MarkDetailsFetched", []int{2}).Return(nil).Once()
+
+	jobs, counts, err := uc.ScrapeAndStoreJobs(context.Background(), cssSiteConfig(7, "Acme", srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, 1, counts.New)
+	_, fetched := detailHits.Load("100")
+	assert.False(t, fetched, "fresh known job must not be fetched again")
+	_, fetched = detailHits.Load("200")
+	assert.True(t, fetched, "stale known job is fetched again")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobUseCase_ScrapeAndStoreJobs_KeepsIdentityWhenKnownJobDetailFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jobs" {
+			fmt.Fprint(w, `<html><body><ul><li><a href="/jobs/200">QA</a></li></ul></body></html>`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	stale := time.Now().Add(-8 * 24 * time.Hour)
+
+	mockRepo, uc := scrapeMocks(7, []model.KnownJob{
+		{ID: 2, RequisitionID: "R-200", JobLink: "/jobs/200", DetailsFetchedAt: &stale},
+	}, map[string]string{"R-200": ""})
+	mockRepo.On("CountMissingJobs", 7, []string{"R-200"}).Return(0, nil).Once()
+	// The stored job is seen again instead of a new synthetic one being created
+	mockRepo.On("UpdateLastSeen", 7, "R-200").Return(2, nil).Once()
+	mockRepo.On("MarkDetailsFetched", mock.Anything).Return(nil).Maybe()
+
+	jobs, counts, err := uc.ScrapeAndStoreJobs(context.Background(), cssSiteConfig(7, "Acme", srv.URL+"/jobs"))
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "R-200", jobs[0].RequisitionID)
+	assert.False(t, jobs[0].SyntheticID)
+	assert.Equal(t, 0, counts.New)
+	mockRepo.AssertNotCalled(t, "CreateJob", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobUseCase_ScrapeAndStoreJobs_ClustersCrossSiteDuplicates(t *testing.T) {
+	srv := jobListingServer(t, `
+		<li><a href="/jobs/1">Desenvolvedor(a) Backend Sênior</a><span class="loc">São Paulo, SP</span></li>
+		<li><a href="/jobs/2">Analista de Dados Pleno</a><span class="loc">São Paulo, SP</span></li>`, nil)
+	config := cssSiteConfig(8, "Acme (Gupy)", srv.URL+"/jobs")
+	config.LocationSelector = strPtr(".loc")
+	canonicalID := 40
+
+	mockRepo, uc := scrapeMocks(8, []model.KnownJob{}, map[string]string{})
+	mockRepo.On("CountMissingJobs", 8, mock.Anything).Return(0, nil).Once()
+	mockRepo.On("CreateJob", mock.MatchedBy(func(job model.Job) bool { return job.RequisitionID == "1" && job.CompanyKey == "acme" })).Return(101, nil).Once()
+	mockRepo.On("CreateJob", mock.MatchedBy(func(job model.Job) bool { return job.RequisitionID == "2" })).Return(102, nil).Once()
+	mockRepo.On("FindDuplicateCandidates", 8, "acme").Return([]model.Job{
+		{ID: 41, SiteID: 3, Title: "Desenvolvedor Backend Sr", LocationCity: "São Paulo", LocationState: "SP", CompanyKey: "acme", CanonicalJobID: &canonicalID},
+		{ID: 42, SiteID: 3, Title: "Analista de Dados Pleno", LocationCity: "Rio de Janeiro", LocationState: "RJ", CompanyKey: "acme"},
+	}, nil).Once()
+	// Only the backend job is the same opening; it joins the cluster of job 41
+	mockRepo.On("SetCanonicalJob", 101, 40).Return(nil).Once()
+
+	_, counts, err := uc.ScrapeAndStoreJobs(context.Background(), config)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts.New)
+	mockRepo.AssertExpectations(t)
+}
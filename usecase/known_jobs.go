@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"web-scrapper/model"
+)
+
+// defaultDetailTTL is how long a stored job's details are trusted before its
+// detail page is fetched again.
+const defaultDetailTTL = 7 * 24 * time.Hour
+
+// knownJobFilter is the scrapper.DetailFilter of one scrape: jobs already stored
+// for the site with details fetched within ttl skip their detail page and get
+// their stored requisition ID, and title when the listing has none, instead.
+type knownJobFilter struct {
+	ttl    time.Duration
+	byLink map[string]model.KnownJob
+
+	mu      sync.Mutex
+	skipped map[string]bool
+}
+
+func newKnownJobFilter(known []model.KnownJob, ttl time.Duration) *knownJobFilter {
+	f := &knownJobFilter{
+		ttl:     ttl,
+		byLink:  make(map[string]model.KnownJob, len(known)),
+		skipped: make(map[string]bool),
+	}
+	for _, job := range known {
+		if key := jobLinkKey(job.JobLink); key != "" {
+			f.byLink[key] = job
+		}
+	}
+	return f
+}
+
+func (f *knownJobFilter) wants(job *model.Job) bool {
+	known, ok := f.byLink[jobLinkKey(job.JobLink)]
+	if !ok {
+		return true
+	}
+	// The stored identity holds until the detail page gives one, so a detail page
+	// that fails does not turn the job into a new one
+	if job.RequisitionID == "" {
+		job.RequisitionID = known.RequisitionID
+		job.SyntheticID = known.SyntheticID
+	}
+	if known.DetailsFetchedAt == nil || time.Since(*known.DetailsFetchedAt) > f.ttl {
+		return true
+	}
+	job.RequisitionID = known.RequisitionID
+	job.SyntheticID = known.SyntheticID
+	if job.Title == "" {
+		job.Title = known.Title
+	}
+
+	f.mu.Lock()
+	f.skipped[known.RequisitionID] = true
+	f.mu.Unlock()
+	return false
+}
+
+// detailsSkipped reports whether the job with requisitionID was kept from the
+// listing alone.
+func (f *knownJobFilter) detailsSkipped(requisitionID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.skipped[requisitionID]
+}
+
+func (f *knownJobFilter) skippedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.skipped)
+}
+
+// jobLinkKey matches listing links to stored ones; links that are not absolute
+// are compared as they are.
+func jobLinkKey(link string) string {
+	if canonical := canonicalJobLink(link); canonical != "" {
+		return canonical
+	}
+	return strings.TrimSpace(link)
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 	"web-scrapper/model"
 	"web-scrapper/repository/mocks"
@@ -73,6 +74,60 @@ func TestNotificationsUsecase_MatchJobsForUser(t *testing.T) {
 		mockNotificationRepo.AssertExpectations(t)
 	})
 
+	t.Run("should match target words naming a level by the job's seniority", func(t *testing.T) {
+		userID := 50
+		jobsWithFilters := []model.JobWithFilters{
+			{JobID: 20, Title: "Desenvolvedor(a) II", Seniority: model.SeniorityMid, Filters: []string{"pleno"}},
+			{JobID: 21, Title: "Desenvolvedor(a) III", Seniority: model.SenioritySenior, Filters: []string{"pleno"}},
+		}
+
+		mockNotificationRepo.On("GetUnnotifiedJobsForUser", userID).Return(jobsWithFilters, nil).Once()
+		mockNotificationRepo.On("BulkInsertPendingNotifications", userID, []int{20}).Return(nil).Once()
+
+		err := notificationUsecase.MatchJobsForUser(context.Background(), userID)
+
+		assert.NoError(t, err)
+		mockNotificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should apply classification filters and keep unclassified jobs", func(t *testing.T) {
+		userID := 60
+		classFilters := model.JobClassFilters{Seniorities: []string{model.SenioritySenior}, ContractTypes: []string{model.ContractCLT}}
+		jobsWithFilters := []model.JobWithFilters{
+			{JobID: 30, Title: "Backend Sr", Seniority: model.SenioritySenior, ContractType: model.ContractCLT, ClassFilters: classFilters},
+			{JobID: 31, Title: "Backend Jr", Seniority: model.SeniorityJunior, ContractType: model.ContractCLT, ClassFilters: classFilters},
+			{JobID: 32, Title: "Backend Sr PJ", Seniority: model.SenioritySenior, ContractType: model.ContractPJ, ClassFilters: classFilters},
+			{JobID: 33, Title: "Backend", ClassFilters: classFilters},
+		}
+
+		mockNotificationRepo.On("GetUnnotifiedJobsForUser", userID).Return(jobsWithFilters, nil).Once()
+		mockNotificationRepo.On("BulkInsertPendingNotifications", userID, []int{30, 33}).Return(nil).Once()
+
+		err := notificationUsecase.MatchJobsForUser(context.Background(), userID)
+
+		assert.NoError(t, err)
+		mockNotificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("should notify one posting per cross-site cluster", func(t *testing.T) {
+		userID := 70
+		jobsWithFilters := []model.JobWithFilters{
+			{JobID: 40, Title: "Go Developer", ClusterID: 40, Filters: []string{"developer"}},
+			{JobID: 41, Title: "QA Analyst", ClusterID: 41, Filters: []string{"developer"}},
+			{JobID: 55, Title: "Go Developer", ClusterID: 40, Filters: []string{"developer"}},
+			{JobID: 56, Title: "Desenvolvedor Go (Developer)", ClusterID: 41, Filters: []string{"developer"}},
+		}
+
+		mockNotificationRepo.On("GetUnnotifiedJobsForUser", userID).Return(jobsWithFilters, nil).Once()
+		// 56 matches while its canonical job 41 does not, so the cluster is notified through it
+		mockNotificationRepo.On("BulkInsertPendingNotifications", userID, []int{40, 56}).Return(nil).Once()
+
+		err := notificationUsecase.MatchJobsForUser(context.Background(), userID)
+
+		assert.NoError(t, err)
+		mockNotificationRepo.AssertExpectations(t)
+	})
+
 	t.Run("should return nil when no unnotified jobs exist", func(t *testing.T) {
 		userID := 40
 
@@ -119,6 +174,31 @@ func TestNotificationsUsecase_SendDigestForUser(t *testing.T) {
 		mockEmailService.AssertExpectations(t)
 	})
 
+	t.Run("should send one entry per cluster with the alternate links", func(t *testing.T) {
+		userID := 40
+		pendingJobs := []model.NotificationWithJob{
+			{ID: 4, JobID: 300, UserID: userID, JobTitle: "Go Dev", JobCompany: "Acme", JobLink: "https://acme.com/1", ClusterID: 300,
+				AlternateLinks: []string{"https://acme.gupy.io/9"}},
+			{ID: 5, JobID: 301, UserID: userID, JobTitle: "QA", JobCompany: "Acme", JobLink: "https://acme.com/2", ClusterID: 301},
+			{ID: 6, JobID: 310, UserID: userID, JobTitle: "Go Dev", JobCompany: "Acme (Gupy)", JobLink: "https://acme.gupy.io/9", ClusterID: 300,
+				AlternateLinks: []string{"https://acme.com/1"}},
+		}
+
+		mockNotificationRepo.On("GetPendingJobsForUser", userID).Return(pendingJobs, nil).Once()
+		mockUserRepo.On("GetUserBasicInfo", userID).Return("Ana", "ana@example.com", nil).Once()
+		mockEmailService.On("SendNewJobsEmail", mock.Anything, "ana@example.com", "Ana", mock.MatchedBy(func(jobs []*model.Job) bool {
+			return len(jobs) == 2 && jobs[0].ID == 300 && slices.Equal(jobs[0].AlternateLinks, []string{"https://acme.gupy.io/9"}) &&
+				jobs[1].ID == 301 && len(jobs[1].AlternateLinks) == 0
+		})).Return(nil).Once()
+		mockNotificationRepo.On("BulkUpdateNotificationStatus", userID, []int{300, 301, 310}, "SENT").Return(nil).Once()
+
+		err := notificationUsecase.SendDigestForUser(context.Background(), userID)
+
+		assert.NoError(t, err)
+		mockNotificationRepo.AssertExpectations(t)
+		mockEmailService.AssertExpectations(t)
+	})
+
 	t.Run("should return nil when no pending notifications exist", func(t *testing.T) {
 		userID := 20
 
@@ -3,10 +3,12 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"web-scrapper/interfaces"
 	"web-scrapper/logging"
 	"web-scrapper/model"
+	"web-scrapper/normalizer"
 )
 
 
@@ -37,7 +39,16 @@ func NewNotificationUsecase(
 	}
 }
 
-func matchJobWithFiltersFromList(jobTitle string, filters []string) bool {
+// matchJobWithFilters checks a job against the user's target words and
+// classification filters for its site.
+func matchJobWithFilters(job model.JobWithFilters) bool {
+	return matchJobWithFiltersFromList(job.Title, job.Seniority, job.Filters) && matchJobClass(job)
+}
+
+// matchJobWithFiltersFromList matches when the title contains one of the filters.
+// A filter that names a level, like "pleno" or "Sr.", also matches jobs classified
+// with that level, whatever the title says ("Desenvolvedor II").
+func matchJobWithFiltersFromList(jobTitle string, seniority string, filters []string) bool {
 	if len(filters) == 0 {
 		return true
 	}
@@ -46,10 +57,47 @@ func matchJobWithFiltersFromList(jobTitle string, filters []string) bool {
 		if strings.Contains(jobTitleLower, strings.ToLower(filter)) {
 			return true
 		}
+		if seniority != "" && normalizer.SeniorityOf(filter) == seniority {
+			return true
+		}
 	}
 	return false
 }
 
+// matchJobClass requires each non-empty classification filter to list the job's
+// value. Jobs the classifier could not place pass, so they are not lost.
+func matchJobClass(job model.JobWithFilters) bool {
+	for _, field := range []struct {
+		value   string
+		allowed []string
+	}{
+		{job.Seniority, job.ClassFilters.Seniorities},
+		{job.RoleFamily, job.ClassFilters.RoleFamilies},
+		{job.ContractType, job.ClassFilters.ContractTypes},
+	} {
+		if field.value != "" && len(field.allowed) > 0 && !slices.Contains(field.allowed, field.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// clusterOf returns the cluster a job belongs to, the job itself when none is known.
+func clusterOf(jobID int, clusterID int) int {
+	if clusterID == 0 {
+		return jobID
+	}
+	return clusterID
+}
+
+// addAlternateLink adds link to the job's alternate links unless it is already shown.
+func addAlternateLink(job *model.Job, link string) []string {
+	if link == "" || link == job.JobLink || slices.Contains(job.AlternateLinks, link) {
+		return job.AlternateLinks
+	}
+	return append(job.AlternateLinks, link)
+}
+
 func (s *NotificationsUsecase) MatchJobsForUser(ctx context.Context, userID int) error {
 	jobs, err := s.notificationRepository.GetUnnotifiedJobsForUser(userID)
 	if err != nil {
@@ -62,10 +110,15 @@ func (s *NotificationsUsecase) MatchJobsForUser(ctx context.Context, userID int)
 	}
 
 	var matchedJobIDs []int
+	matchedClusters := make(map[int]bool)
 	for _, job := range jobs {
-		if matchJobWithFiltersFromList(job.Title, job.Filters) {
-			matchedJobIDs = append(matchedJobIDs, job.JobID)
+		// The same opening found on several sites is notified once
+		cluster := clusterOf(job.JobID, job.ClusterID)
+		if matchedClusters[cluster] || !matchJobWithFilters(job) {
+			continue
 		}
+		matchedClusters[cluster] = true
+		matchedJobIDs = append(matchedJobIDs, job.JobID)
 	}
 
 	if len(matchedJobIDs) == 0 {
@@ -106,17 +159,29 @@ func (s *NotificationsUsecase) SendDigestForUser(ctx context.Context, userID int
 		return fmt.Errorf("error fetching user info for user %d: %w", userID, err)
 	}
 
-	jobs := make([]*model.Job, len(pendingNotifications))
+	// Pending postings of the same opening become one entry listing every link
+	var jobs []*model.Job
+	jobsByCluster := make(map[int]*model.Job)
 	jobIDs := make([]int, len(pendingNotifications))
 	for i, n := range pendingNotifications {
-		jobs[i] = &model.Job{
+		jobIDs[i] = n.JobID
+		cluster := clusterOf(n.JobID, n.ClusterID)
+		if job, ok := jobsByCluster[cluster]; ok {
+			job.AlternateLinks = addAlternateLink(job, n.JobLink)
+			continue
+		}
+		job := &model.Job{
 			ID:       n.JobID,
 			Title:    n.JobTitle,
 			Company:  n.JobCompany,
 			Location: n.JobLocation,
 			JobLink:  n.JobLink,
 		}
-		jobIDs[i] = n.JobID
+		for _, link := range n.AlternateLinks {
+			job.AlternateLinks = addAlternateLink(job, link)
+		}
+		jobsByCluster[cluster] = job
+		jobs = append(jobs, job)
 	}
 
 	if err := s.emailService.SendNewJobsEmail(ctx, userEmail, userName, jobs); err != nil {
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"web-scrapper/interfaces"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+)
+
+const (
+	// healthyStreak successful runs with results must precede a run listing nothing
+	// for it to count as an anomaly.
+	healthyStreak = 3
+	// dropRatio is the fraction of the rolling average below which a run is a sudden drop.
+	dropRatio = 0.3
+	// minDropAverage keeps small sites, whose counts swing naturally, out of drop detection.
+	minDropAverage = 10
+)
+
+type SiteAnomalyUsecase struct {
+	runs         interfaces.ScrapeRunRepositoryInterface
+	sites        interfaces.SiteCareerRepositoryInterface
+	emailService interfaces.EmailService
+	adminEmail   string
+	// AutoDisable switches off sites whose anomaly points at a broken config, until
+	// an admin re-validates them in the sandbox.
+	AutoDisable bool
+}
+
+func NewSiteAnomalyUsecase(
+	runs interfaces.ScrapeRunRepositoryInterface,
+	sites interfaces.SiteCareerRepositoryInterface,
+	emailSvc interfaces.EmailService,
+	adminEmail string,
+) *SiteAnomalyUsecase {
+	return &SiteAnomalyUsecase{
+		runs:         runs,
+		sites:        sites,
+		emailService: emailSvc,
+		adminEmail:   adminEmail,
+	}
+}
+
+// CheckSite looks at the site's history after a run. When the run starts a new
+// anomaly, the admin is alerted and, with AutoDisable, the site is switched off.
+func (uc *SiteAnomalyUsecase) CheckSite(ctx context.Context, siteID int, siteName string) (*model.SiteAnomaly, error) {
+	runs, err := uc.runs.GetSiteRuns(siteID, healthWindow)
+	if err != nil {
+		return nil, err
+	}
+	anomaly := newAnomaly(runs)
+	if anomaly == nil {
+		return nil, nil
+	}
+	anomaly.SiteID = siteID
+	anomaly.SiteName = siteName
+	logging.Logger.Warn().Int("site_id", siteID).Str("kind", anomaly.Kind).Str("detail", anomaly.Message).Msg("Scrape anomaly detected")
+
+	if uc.AutoDisable && anomaly.Kind != model.AnomalySuddenDrop {
+		disabled, err := uc.sites.DisableSite(siteID, anomaly.Message)
+		if err != nil {
+			logging.Logger.Error().Err(err).Int("site_id", siteID).Msg("Failed to disable site after anomaly")
+		}
+		anomaly.Disabled = disabled
+	}
+
+	if uc.adminEmail == "" {
+		logging.Logger.Warn().Int("site_id", siteID).Msg("ADMIN_EMAIL not set, scrape anomaly alert not sent")
+		return anomaly, nil
+	}
+	if err := uc.emailService.SendSiteAnomalyEmail(ctx, uc.adminEmail, *anomaly); err != nil {
+		return anomaly, fmt.Errorf("error sending anomaly alert for site %d: %w", siteID, err)
+	}
+	return anomaly, nil
+}
+
+// newAnomaly returns the anomaly of the latest run unless the run before it already
+// had the same one, so each episode is reported once. A policy skip starts nothing.
+func newAnomaly(runs []model.ScrapeRun) *model.SiteAnomaly {
+	if len(runs) > 0 && runs[0].ErrorClass == model.ScrapeErrorPolicySkip {
+		return nil
+	}
+	counted := make([]model.ScrapeRun, 0, len(runs))
+	for _, run := range runs {
+		if run.ErrorClass != model.ScrapeErrorPolicySkip {
+			counted = append(counted, run)
+		}
+	}
+
+	current := DetectAnomaly(counted)
+	if current == nil || len(counted) < 2 {
+		return current
+	}
+	if previous := DetectAnomaly(counted[1:]); previous != nil && previous.Kind == current.Kind {
+		return nil
+	}
+	return current
+}
+
+// DetectAnomaly checks the latest of runs (newest first, without policy skips) for
+// signs of a broken config: failingAfter consecutive failures, no results after a
+// healthy streak, every title empty, or a sudden drop against the rolling average.
+func DetectAnomaly(runs []model.ScrapeRun) *model.SiteAnomaly {
+	if len(runs) == 0 {
+		return nil
+	}
+	latest := runs[0]
+
+	if latest.Failed() {
+		failures := 0
+		for _, run := range runs {
+			if !run.Failed() {
+				break
+			}
+			failures++
+		}
+		if failures < failingAfter {
+			return nil
+		}
+		return &model.SiteAnomaly{
+			Kind:    model.AnomalyConsecutiveFailures,
+			Message: fmt.Sprintf("%d execuções seguidas falharam (último erro: %s)", failures, latest.ErrorClass),
+		}
+	}
+
+	var previous []model.ScrapeRun
+	for _, run := range runs[1:] {
+		if !run.Failed() {
+			previous = append(previous, run)
+		}
+	}
+
+	if latest.Listed == 0 {
+		if len(previous) < healthyStreak {
+			return nil
+		}
+		for _, run := range previous[:healthyStreak] {
+			if run.Listed == 0 {
+				return nil
+			}
+		}
+		return &model.SiteAnomaly{
+			Kind:    model.AnomalyZeroResults,
+			Message: fmt.Sprintf("nenhuma vaga listada depois de %d execuções com resultados", healthyStreak),
+		}
+	}
+
+	if latest.EmptyTitles == latest.Listed {
+		return &model.SiteAnomaly{
+			Kind:    model.AnomalyEmptyTitles,
+			Message: fmt.Sprintf("todas as %d vagas listadas vieram sem título", latest.Listed),
+		}
+	}
+
+	if len(previous) < healthyStreak {
+		return nil
+	}
+	total := 0
+	for _, run := range previous {
+		total += run.Listed
+	}
+	average := float64(total) / float64(len(previous))
+	if average >= minDropAverage && float64(latest.Listed) < average*dropRatio {
+		return &model.SiteAnomaly{
+			Kind:    model.AnomalySuddenDrop,
+			Message: fmt.Sprintf("%d vagas listadas contra uma média de %.1f nas execuções anteriores", latest.Listed, average),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"web-scrapper/model"
+	"web-scrapper/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAnomaly(t *testing.T) {
+	emptyTitles := runs(5, 6, 7)
+	emptyTitles[0].EmptyTitles = 5
+
+	tests := map[string]struct {
+		runs []model.ScrapeRun
+		kind string
+	}{
+		"steady results":          {runs(12, 10, 11, 12), ""},
+		"two failures":            {runs(-1, -1, 10), ""},
+		"consecutive failures":    {runs(-1, -1, -1, 10), model.AnomalyConsecutiveFailures},
+		"zero after healthy runs": {runs(0, 10, 11, 12), model.AnomalyZeroResults},
+		"zero without a streak":   {runs(0, 10, 0, 12), ""},
+		"always empty":            {runs(0, 0, 0, 0), ""},
+		"all titles empty":        {emptyTitles, model.AnomalyEmptyTitles},
+		"sudden drop":             {runs(3, 20, 22, 18), model.AnomalySuddenDrop},
+		"small site swings":       {runs(1, 4, 5, 3), ""},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			anomaly := DetectAnomaly(tt.runs)
+
+			if tt.kind == "" {
+				assert.Nil(t, anomaly)
+				return
+			}
+			require.NotNil(t, anomaly)
+			assert.Equal(t, tt.kind, anomaly.Kind)
+		})
+	}
+}
+
+func TestNewAnomaly_ReportsEachEpisodeOnce(t *testing.T) {
+	assert.NotNil(t, newAnomaly(runs(-1, -1, -1, 10)))
+	assert.Nil(t, newAnomaly(runs(-1, -1, -1, -1, 10)), "already reported on the third failure")
+	assert.NotNil(t, newAnomaly(runs(0, 10, 11, 12)))
+	assert.Nil(t, newAnomaly(runs(0, 0, 10, 11, 12)))
+
+	skipped := runs(-1, -1, -1, 10)
+	skipped[1].ErrorClass = model.ScrapeErrorPolicySkip
+	assert.Nil(t, newAnomaly(skipped), "policy skips do not count as failures")
+	skipped[0].ErrorClass = model.ScrapeErrorPolicySkip
+	assert.Nil(t, newAnomaly(skipped))
+}
+
+func TestSiteAnomalyUsecase_CheckSite_DisablesAndAlerts(t *testing.T) {
+	runRepo := new(mocks.MockScrapeRunRepository)
+	siteRepo := new(mocks.MockSiteCareerRepository)
+	emailSvc := new(mocks.MockEmailService)
+	uc := NewSiteAnomalyUsecase(runRepo, siteRepo, emailSvc, "admin@scrapjobs.com")
+	uc.AutoDisable = true
+
+	runRepo.On("GetSiteRuns", 7, healthWindow).Return(runs(0, 10, 11, 12), nil).Once()
+	siteRepo.On("DisableSite", 7, mock.AnythingOfType("string")).Return(true, nil).Once()
+	emailSvc.On("SendSiteAnomalyEmail", mock.Anything, "admin@scrapjobs.com", mock.MatchedBy(func(a model.SiteAnomaly) bool {
+		return a.SiteID == 7 && a.SiteName == "Acme" && a.Kind == model.AnomalyZeroResults && a.Disabled
+	})).Return(nil).Once()
+
+	anomaly, err := uc.CheckSite(context.Background(), 7, "Acme")
+
+	require.NoError(t, err)
+	require.NotNil(t, anomaly)
+	runRepo.AssertExpectations(t)
+	siteRepo.AssertExpectations(t)
+	emailSvc.AssertExpectations(t)
+}
+
+func TestSiteAnomalyUsecase_CheckSite_SuddenDropOnlyAlerts(t *testing.T) {
+	runRepo := new(mocks.MockScrapeRunRepository)
+	siteRepo := new(mocks.MockSiteCareerRepository)
+	emailSvc := new(mocks.MockEmailService)
+	uc := NewSiteAnomalyUsecase(runRepo, siteRepo, emailSvc, "admin@scrapjobs.com")
+	uc.AutoDisable = true
+
+	runRepo.On("GetSiteRuns", 7, healthWindow).Return(runs(3, 20, 22, 18), nil).Once()
+	emailSvc.On("SendSiteAnomalyEmail", mock.Anything, "admin@scrapjobs.com", mock.MatchedBy(func(a model.SiteAnomaly) bool {
+		return a.Kind == model.AnomalySuddenDrop && !a.Disabled
+	})).Return(nil).Once()
+
+	_, err := uc.CheckSite(context.Background(), 7, "Acme")
+
+	require.NoError(t, err)
+	siteRepo.AssertNotCalled(t, "DisableSite", mock.Anything, mock.Anything)
+	emailSvc.AssertExpectations(t)
+}
+
+func TestSiteAnomalyUsecase_CheckSite_HealthySiteIsQuiet(t *testing.T) {
+	runRepo := new(mocks.MockScrapeRunRepository)
+	emailSvc := new(mocks.MockEmailService)
+	uc := NewSiteAnomalyUsecase(runRepo, new(mocks.MockSiteCareerRepository), emailSvc, "admin@scrapjobs.com")
+
+	runRepo.On("GetSiteRuns", 7, healthWindow).Return(runs(10, 11), nil).Once()
+
+	anomaly, err := uc.CheckSite(context.Background(), 7, "Acme")
+
+	require.NoError(t, err)
+	assert.Nil(t, anomaly)
+	emailSvc.AssertNotCalled(t, "SendSiteAnomalyEmail", mock.Anything, mock.Anything, mock.Anything)
+}
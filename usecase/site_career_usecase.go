@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"mime/multipart"
+	"reflect"
+	"sort"
+	"strings"
 	"web-scrapper/infra/s3"
 	"web-scrapper/interfaces"
 	"web-scrapper/model"
@@ -23,7 +27,11 @@ func NewSiteCareerUsecase(repo interfaces.SiteCareerRepositoryInterface, uploade
 	}
 }
 
-func (repo *SiteCareerUsecase) InsertNewSiteCareer(ctx context.Context ,site model.SiteScrapingConfig, file *multipart.FileHeader) (model.SiteScrapingConfig, error){
+func (repo *SiteCareerUsecase) InsertNewSiteCareer(ctx context.Context ,site model.SiteScrapingConfig, file *multipart.FileHeader, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error){
+
+	if err := validateSiteConfig(site); err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
 
 	if file != nil {
 		logoURL, err := repo.s3Uploader.UploadFile(ctx, file)
@@ -33,7 +41,7 @@ func (repo *SiteCareerUsecase) InsertNewSiteCareer(ctx context.Context ,site mod
 		site.LogoURL = &logoURL
 	}
 
-	res, err := repo.repo.InsertNewSiteCareer(site)
+	res, err := repo.repo.InsertNewSiteCareer(site, author)
 	if err != nil {
 		return model.SiteScrapingConfig{}, err
 	}
@@ -41,18 +49,200 @@ func (repo *SiteCareerUsecase) InsertNewSiteCareer(ctx context.Context ,site mod
 	return res, nil
 }
 
-func (repo *SiteCareerUsecase) SandboxScrape(ctx context.Context,config model.SiteScrapingConfig) ([]*model.Job, error){
-	scrapInterface, err := scrapper.NewScraperFactory(config)
+// UpdateSiteCareer replaces the config of a site. The logo is kept unless a new
+// one is uploaded, and whether the site is active is changed through SetSiteActive.
+func (repo *SiteCareerUsecase) UpdateSiteCareer(ctx context.Context, siteID int, site model.SiteScrapingConfig, file *multipart.FileHeader, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error){
+	if err := validateSiteConfig(site); err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+
+	current, err := repo.repo.GetSiteByID(siteID)
+	if err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+	site.ID = siteID
+	site.LogoURL = current.LogoURL
+	if file != nil {
+		logoURL, err := repo.s3Uploader.UploadFile(ctx, file)
+		if err != nil {
+			return model.SiteScrapingConfig{}, fmt.Errorf("falha no upload do logo: %w", err)
+		}
+		site.LogoURL = &logoURL
+	}
+
+	return repo.repo.UpdateSiteCareer(site, model.SiteConfigUpdated, "", author)
+}
+
+func (repo *SiteCareerUsecase) SetSiteActive(siteID int, active bool, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error){
+	return repo.repo.SetSiteActive(siteID, active, "", author)
+}
+
+func (repo *SiteCareerUsecase) DeleteSite(siteID int, author model.SiteConfigAuthor) error{
+	return repo.repo.DeleteSite(siteID, author)
+}
+
+func (repo *SiteCareerUsecase) GetSiteVersions(siteID int) ([]model.SiteConfigVersion, error){
+	return repo.repo.GetSiteVersions(siteID)
+}
+
+// DiffSiteVersions lists the config fields that differ between two versions of a
+// site; toVersion 0 means the latest version.
+func (repo *SiteCareerUsecase) DiffSiteVersions(siteID int, fromVersion int, toVersion int) (model.SiteConfigDiff, error){
+	if toVersion == 0 {
+		versions, err := repo.repo.GetSiteVersions(siteID)
+		if err != nil {
+			return model.SiteConfigDiff{}, err
+		}
+		if len(versions) == 0 {
+			return model.SiteConfigDiff{}, fmt.Errorf("site %d: %w", siteID, model.ErrSiteVersionNotFound)
+		}
+		toVersion = versions[0].Version
+	}
+
+	from, err := repo.repo.GetSiteVersion(siteID, fromVersion)
+	if err != nil {
+		return model.SiteConfigDiff{}, err
+	}
+	to, err := repo.repo.GetSiteVersion(siteID, toVersion)
+	if err != nil {
+		return model.SiteConfigDiff{}, err
+	}
+
+	changes, err := diffSiteConfigs(from.Config, to.Config)
+	if err != nil {
+		return model.SiteConfigDiff{}, err
+	}
+	return model.SiteConfigDiff{SiteID: siteID, FromVersion: fromVersion, ToVersion: toVersion, Changes: changes}, nil
+}
+
+// RollbackSite restores the config of a previous version as a new version. Whether
+// the site is active and its logo are left as they are.
+func (repo *SiteCareerUsecase) RollbackSite(siteID int, version int, author model.SiteConfigAuthor) (model.SiteScrapingConfig, error){
+	target, err := repo.repo.GetSiteVersion(siteID, version)
+	if err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+	current, err := repo.repo.GetSiteByID(siteID)
+	if err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+
+	config := target.Config
+	config.ID = siteID
+	config.IsActive = current.IsActive
+	config.LogoURL = current.LogoURL
+	if err := validateSiteConfig(config); err != nil {
+		return model.SiteScrapingConfig{}, err
+	}
+
+	return repo.repo.UpdateSiteCareer(config, model.SiteConfigRolledBack, fmt.Sprintf("rollback para a versão %d", version), author)
+}
+
+func validateSiteConfig(site model.SiteScrapingConfig) error {
+	if scrapper.IsATSType(site.ScrapingType) {
+		if _, err := scrapper.ResolveATSConfig(site); err != nil {
+			return fmt.Errorf("configuração de ATS inválida: %w", err)
+		}
+	}
+
+	if _, err := scrapper.ParseHeadlessActions(site.HeadlessActions); err != nil {
+		return fmt.Errorf("ações headless inválidas: %w", err)
+	}
+
+	if err := validateSiteSchedule(site); err != nil {
+		return fmt.Errorf("agenda inválida: %w", err)
+	}
+	return nil
+}
+
+// diffSiteConfigs compares two configs field by field, by their JSON names.
+func diffSiteConfigs(from, to model.SiteScrapingConfig) ([]model.SiteConfigFieldChange, error) {
+	fromFields, err := configFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := configFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(fromFields)+len(toFields))
+	for name := range fromFields {
+		names = append(names, name)
+	}
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []model.SiteConfigFieldChange{}
+	for _, name := range names {
+		if name == "id" || reflect.DeepEqual(fromFields[name], toFields[name]) {
+			continue
+		}
+		changes = append(changes, model.SiteConfigFieldChange{Field: name, From: fromFields[name], To: toFields[name]})
+	}
+	return changes, nil
+}
+
+func configFields(config model.SiteScrapingConfig) (map[string]interface{}, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding site config: %w", err)
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("error decoding site config: %w", err)
+	}
+	return fields, nil
+}
+
+// SandboxScrape runs config once, without storing anything, and reports how each
+// request and selector behaved. The report is filled in even when the scrape fails.
+func (repo *SiteCareerUsecase) SandboxScrape(ctx context.Context,config model.SiteScrapingConfig) ([]*model.Job, model.SandboxDiagnostics, error){
+	diagnostics := scrapper.NewDiagnostics()
+	scrapInterface, err := scrapper.NewScraperFactory(config, scrapper.WithDiagnostics(diagnostics))
     if err != nil {
-        return nil, err
+        return nil, diagnostics.Report(config, nil), err
     }
 
 	jobs, err := scrapInterface.Scrape(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("erro durante o processo de scraping: %w ", err)
+		return nil, diagnostics.Report(config, nil), fmt.Errorf("erro durante o processo de scraping: %w ", err)
 	}
 
-	return jobs, nil
+	return jobs, diagnostics.Report(config, jobs), nil
+}
+
+// RevalidateSite runs the stored config of a site in the sandbox and, when it finds
+// jobs with titles, switches the site back on. Only sites disabled automatically
+// are reactivated; a site an admin switched off stays off.
+func (repo *SiteCareerUsecase) RevalidateSite(ctx context.Context, siteID int, author model.SiteConfigAuthor) ([]*model.Job, bool, error){
+	config, err := repo.repo.GetSiteByID(siteID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	jobs, _, err := repo.SandboxScrape(ctx, config)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, job := range jobs {
+		if strings.TrimSpace(job.Title) == "" {
+			return jobs, false, nil
+		}
+	}
+	if len(jobs) == 0 || config.DisabledReason == "" {
+		return jobs, false, nil
+	}
+
+	if _, err := repo.repo.SetSiteActive(siteID, true, "revalidado no sandbox", author); err != nil {
+		return jobs, false, err
+	}
+	return jobs, true, nil
 }
 
 func (repo *SiteCareerUsecase) GetAllSites() ([]model.SiteScrapingConfig, error){
@@ -3,7 +3,10 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"web-scrapper/model"
 	"web-scrapper/repository/mocks"
@@ -52,9 +55,9 @@ func TestSiteCareerUsecase_InsertNewSiteCareer(t *testing.T) {
 		site := model.SiteScrapingConfig{SiteName: "Acme", BaseURL: "https://acme.com", ScrapingType: "CSS"}
 		expected := model.SiteScrapingConfig{ID: 1, SiteName: "Acme", BaseURL: "https://acme.com", ScrapingType: "CSS"}
 
-		mockRepo.On("InsertNewSiteCareer", site).Return(expected, nil).Once()
+		mockRepo.On("InsertNewSiteCareer", site, model.SiteConfigAuthor{}).Return(expected, nil).Once()
 
-		result, err := uc.InsertNewSiteCareer(context.Background(), site, nil)
+		result, err := uc.InsertNewSiteCareer(context.Background(), site, nil, model.SiteConfigAuthor{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, result.ID)
@@ -79,9 +82,9 @@ func TestSiteCareerUsecase_InsertNewSiteCareer(t *testing.T) {
 
 		mockRepo.On("InsertNewSiteCareer", mock.MatchedBy(func(s model.SiteScrapingConfig) bool {
 			return s.SiteName == "Beta" && s.LogoURL != nil && *s.LogoURL == logoURL
-		})).Return(expected, nil).Once()
+		}), model.SiteConfigAuthor{}).Return(expected, nil).Once()
 
-		result, err := uc.InsertNewSiteCareer(context.Background(), site, file)
+		result, err := uc.InsertNewSiteCareer(context.Background(), site, file, model.SiteConfigAuthor{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 2, result.ID)
@@ -100,7 +103,7 @@ func TestSiteCareerUsecase_InsertNewSiteCareer(t *testing.T) {
 
 		mockUploader.On("UploadFile", mock.Anything, file).Return("", errors.New("file too large")).Once()
 
-		_, err := uc.InsertNewSiteCareer(context.Background(), site, file)
+		_, err := uc.InsertNewSiteCareer(context.Background(), site, file, model.SiteConfigAuthor{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "falha no upload do logo")
@@ -115,12 +118,188 @@ func TestSiteCareerUsecase_InsertNewSiteCareer(t *testing.T) {
 
 		site := model.SiteScrapingConfig{SiteName: "RepoFail", BaseURL: "https://repofail.com"}
 
-		mockRepo.On("InsertNewSiteCareer", site).Return(model.SiteScrapingConfig{}, errors.New("db error")).Once()
+		mockRepo.On("InsertNewSiteCareer", site, model.SiteConfigAuthor{}).Return(model.SiteScrapingConfig{}, errors.New("db error")).Once()
 
-		_, err := uc.InsertNewSiteCareer(context.Background(), site, nil)
+		_, err := uc.InsertNewSiteCareer(context.Background(), site, nil, model.SiteConfigAuthor{})
 
 		assert.Error(t, err)
 		assert.Equal(t, "db error", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("should reject ATS site with invalid slug", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		mockUploader := new(mocks.MockS3Uploader)
+		uc := NewSiteCareerUsecase(mockRepo, mockUploader)
+
+		slug := "natura"
+		site := model.SiteScrapingConfig{SiteName: "Natura", ScrapingType: "WORKDAY", ATSSlug: &slug}
+
+		_, err := uc.InsertNewSiteCareer(context.Background(), site, nil, model.SiteConfigAuthor{})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "configuração de ATS inválida")
+		mockRepo.AssertNotCalled(t, "InsertNewSiteCareer")
+		mockUploader.AssertNotCalled(t, "UploadFile")
+	})
+
+	t.Run("should reject site with invalid headless actions", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		mockUploader := new(mocks.MockS3Uploader)
+		uc := NewSiteCareerUsecase(mockRepo, mockUploader)
+
+		actions := `[{"type":"click"}]`
+		site := model.SiteScrapingConfig{SiteName: "SPA", ScrapingType: "HEADLESS", HeadlessActions: &actions}
+
+		_, err := uc.InsertNewSiteCareer(context.Background(), site, nil, model.SiteConfigAuthor{})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ações headless inválidas")
+		mockRepo.AssertNotCalled(t, "InsertNewSiteCareer")
+	})
+}
+
+func TestSiteCareerUsecase_RevalidateSite(t *testing.T) {
+	listing := `<html><body><ul><li><a href="/jobs/100">Go Dev</a></li></ul></body></html>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jobs" {
+			fmt.Fprint(w, listing)
+			return
+		}
+		fmt.Fprint(w, `<html><body><span class="req">100</span></body></html>`)
+	}))
+	defer srv.Close()
+
+	str := func(s string) *string { return &s }
+	config := model.SiteScrapingConfig{
+		ID:                       7,
+		SiteName:                 "Acme",
+		BaseURL:                  srv.URL + "/jobs",
+		ScrapingType:             "CSS",
+		JobListItemSelector:      str("li"),
+		TitleSelector:            str("a"),
+		LinkSelector:             str("a"),
+		LinkAttribute:            str("href"),
+		JobRequisitionIdSelector: str(".req"),
+	}
+	admin := model.SiteConfigAuthor{UserID: 1, Email: "admin@scrapjobs.com"}
+
+	autoDisabled := config
+	autoDisabled.DisabledReason = "consecutive_failures: 5 falhas seguidas"
+
+	t.Run("should reactivate the site when jobs are found", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader))
+		mockRepo.On("GetSiteByID", 7).Return(autoDisabled, nil).Once()
+		mockRepo.On("SetSiteActive", 7, true, "revalidado no sandbox", admin).Return(config, nil).Once()
+
+		jobs, reactivated, err := uc.RevalidateSite(context.Background(), 7, admin)
+
+		assert.NoError(t, err)
+		assert.True(t, reactivated)
+		assert.Len(t, jobs, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not reactivate a site an admin switched off", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader))
+		mockRepo.On("GetSiteByID", 7).Return(config, nil).Once()
+
+		jobs, reactivated, err := uc.RevalidateSite(context.Background(), 7, admin)
+
+		assert.NoError(t, err)
+		assert.False(t, reactivated)
+		assert.Len(t, jobs, 1)
+		mockRepo.AssertNotCalled(t, "SetSiteActive", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep the site disabled when titles are empty", func(t *testing.T) {
+		listing = `<html><body><ul><li><a href="/jobs/100"></a></li></ul></body></html>`
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader))
+		mockRepo.On("GetSiteByID", 7).Return(autoDisabled, nil).Once()
+
+		_, reactivated, err := uc.RevalidateSite(context.Background(), 7, admin)
+
+		assert.NoError(t, err)
+		assert.False(t, reactivated)
+		mockRepo.AssertNotCalled(t, "SetSiteActive", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSiteCareerUsecase_UpdateSiteCareer(t *testing.T) {
+	logoURL := "https://bucket.s3.amazonaws.com/logos/acme.png"
+	admin := model.SiteConfigAuthor{UserID: 1, Email: "admin@scrapjobs.com"}
+
+	t.Run("should keep the current logo when no file is uploaded", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		mockUploader := new(mocks.MockS3Uploader)
+		uc := NewSiteCareerUsecase(mockRepo, mockUploader)
+
+		site := model.SiteScrapingConfig{SiteName: "Acme v2", BaseURL: "https://acme.com/jobs", ScrapingType: "CSS"}
+		mockRepo.On("GetSiteByID", 3).Return(model.SiteScrapingConfig{ID: 3, SiteName: "Acme", LogoURL: &logoURL}, nil).Once()
+		mockRepo.On("UpdateSiteCareer", mock.MatchedBy(func(s model.SiteScrapingConfig) bool {
+			return s.ID == 3 && s.SiteName == "Acme v2" && s.LogoURL != nil && *s.LogoURL == logoURL
+		}), model.SiteConfigUpdated, "", admin).Return(site, nil).Once()
+
+		_, err := uc.UpdateSiteCareer(context.Background(), 3, site, nil, admin)
+
+		assert.NoError(t, err)
+		mockUploader.AssertNotCalled(t, "UploadFile")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for unknown site", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader))
+
+		mockRepo.On("GetSiteByID", 99).Return(model.SiteScrapingConfig{}, fmt.Errorf("site 99: %w", model.ErrSiteNotFound)).Once()
+
+		_, err := uc.UpdateSiteCareer(context.Background(), 99, model.SiteScrapingConfig{SiteName: "Ghost"}, nil, admin)
+
+		assert.ErrorIs(t, err, model.ErrSiteNotFound)
+		mockRepo.AssertNotCalled(t, "UpdateSiteCareer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSiteCareerUsecase_DiffSiteVersions(t *testing.T) {
+	str := func(s string) *string { return &s }
+	mockRepo := new(mocks.MockSiteCareerRepository)
+	uc := NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader))
+
+	v1 := model.SiteConfigVersion{SiteID: 5, Version: 1, Config: model.SiteScrapingConfig{ID: 5, SiteName: "Acme", BaseURL: "https://acme.com/jobs", TitleSelector: str("h2")}}
+	v3 := model.SiteConfigVersion{SiteID: 5, Version: 3, Config: model.SiteScrapingConfig{ID: 5, SiteName: "Acme", BaseURL: "https://acme.com/careers", TitleSelector: str("h3")}}
+	mockRepo.On("GetSiteVersions", 5).Return([]model.SiteConfigVersion{v3, {Version: 2}, v1}, nil).Once()
+	mockRepo.On("GetSiteVersion", 5, 1).Return(v1, nil).Once()
+	mockRepo.On("GetSiteVersion", 5, 3).Return(v3, nil).Once()
+
+	diff, err := uc.DiffSiteVersions(5, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, diff.ToVersion)
+	assert.Equal(t, []model.SiteConfigFieldChange{
+		{Field: "base_url", From: "https://acme.com/jobs", To: "https://acme.com/careers"},
+		{Field: "title_selector", From: "h2", To: "h3"},
+	}, diff.Changes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSiteCareerUsecase_RollbackSite(t *testing.T) {
+	logoURL := "https://bucket.s3.amazonaws.com/logos/acme.png"
+	admin := model.SiteConfigAuthor{UserID: 1, Email: "admin@scrapjobs.com"}
+	mockRepo := new(mocks.MockSiteCareerRepository)
+	uc := NewSiteCareerUsecase(mockRepo, new(mocks.MockS3Uploader))
+
+	old := model.SiteConfigVersion{SiteID: 5, Version: 2, Config: model.SiteScrapingConfig{ID: 5, SiteName: "Acme", BaseURL: "https://acme.com/jobs", ScrapingType: "CSS", IsActive: false}}
+	mockRepo.On("GetSiteVersion", 5, 2).Return(old, nil).Once()
+	mockRepo.On("GetSiteByID", 5).Return(model.SiteScrapingConfig{ID: 5, SiteName: "Acme", BaseURL: "https://acme.com/broken", IsActive: true, LogoURL: &logoURL}, nil).Once()
+	mockRepo.On("UpdateSiteCareer", mock.MatchedBy(func(s model.SiteScrapingConfig) bool {
+		return s.BaseURL == "https://acme.com/jobs" && s.IsActive && s.LogoURL == &logoURL
+	}), model.SiteConfigRolledBack, "rollback para a versão 2", admin).Return(old.Config, nil).Once()
+
+	_, err := uc.RollbackSite(5, 2, admin)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
 }
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"fmt"
+	"web-scrapper/interfaces"
+	"web-scrapper/model"
+)
+
+const (
+	// healthWindow is how many of a site's latest runs its health is derived from.
+	healthWindow = 10
+	// failingAfter consecutive failed runs turn a site from degraded into failing.
+	failingAfter     = 3
+	defaultRunsLimit = 50
+	maxRunsLimit     = 500
+)
+
+type SiteHealthUsecase struct {
+	repo interfaces.ScrapeRunRepositoryInterface
+}
+
+func NewSiteHealthUsecase(repo interfaces.ScrapeRunRepositoryInterface) *SiteHealthUsecase {
+	return &SiteHealthUsecase{
+		repo: repo,
+	}
+}
+
+func (uc *SiteHealthUsecase) RecordRun(run model.ScrapeRun) error {
+	return uc.repo.RecordRun(run)
+}
+
+// GetSitesHealth derives the health of every site, keeping only the given status
+// when one is passed.
+func (uc *SiteHealthUsecase) GetSitesHealth(status string) ([]model.SiteHealth, error) {
+	histories, err := uc.repo.GetRecentRunsBySite(healthWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.SiteHealth{}
+	for _, history := range histories {
+		health := DeriveSiteHealth(history)
+		if status != "" && health.Status != status {
+			continue
+		}
+		result = append(result, health)
+	}
+	return result, nil
+}
+
+func (uc *SiteHealthUsecase) GetSiteRuns(siteID int, limit int) ([]model.ScrapeRun, error) {
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		limit = maxRunsLimit
+	}
+	return uc.repo.GetSiteRuns(siteID, limit)
+}
+
+// DeriveSiteHealth rates a site from its latest runs (newest first). Runs skipped by
+// the politeness policy say nothing about the site and are ignored. A site is failing
+// after failingAfter consecutive failures, and degraded after a single one, when its
+// last run listed nothing although earlier ones did, or when most detail pages failed.
+func DeriveSiteHealth(history model.SiteRunHistory) model.SiteHealth {
+	health := model.SiteHealth{
+		SiteID:         history.SiteID,
+		SiteName:       history.SiteName,
+		IsActive:       history.IsActive,
+		Status:         model.SiteHealthUnknown,
+		DisabledReason: history.DisabledReason,
+	}
+	if len(history.Runs) > 0 {
+		lastRunAt := history.Runs[0].StartedAt
+		health.LastRunAt = &lastRunAt
+	}
+
+	var lastSuccess *model.ScrapeRun
+	var earlierListed, earlierSuccesses int
+	counted := 0
+	for i := range history.Runs {
+		run := history.Runs[i]
+		if run.ErrorClass == model.ScrapeErrorPolicySkip {
+			continue
+		}
+		counted++
+		if run.Failed() {
+			if lastSuccess == nil {
+				health.ConsecutiveFailures++
+				if health.LastErrorClass == "" {
+					health.LastErrorClass = run.ErrorClass
+				}
+			}
+			continue
+		}
+		if lastSuccess == nil {
+			lastSuccess = &history.Runs[i]
+			continue
+		}
+		earlierListed += run.Listed
+		earlierSuccesses++
+	}
+	if counted == 0 {
+		return health
+	}
+
+	if lastSuccess != nil {
+		lastSuccessAt := lastSuccess.StartedAt
+		health.LastSuccessAt = &lastSuccessAt
+		health.LastListed = lastSuccess.Listed
+	}
+	if earlierSuccesses > 0 {
+		health.AvgListed = float64(earlierListed) / float64(earlierSuccesses)
+	}
+
+	switch {
+	case health.ConsecutiveFailures >= failingAfter:
+		health.Status = model.SiteHealthFailing
+		health.Reason = fmt.Sprintf("%d execuções seguidas falharam (%s)", health.ConsecutiveFailures, health.LastErrorClass)
+	case health.ConsecutiveFailures > 0:
+		health.Status = model.SiteHealthDegraded
+		health.Reason = fmt.Sprintf("a última execução falhou (%s)", health.LastErrorClass)
+	case lastSuccess.Listed == 0 && earlierListed > 0:
+		health.Status = model.SiteHealthDegraded
+		health.Reason = fmt.Sprintf("nenhuma vaga listada; as execuções anteriores listavam em média %.1f", health.AvgListed)
+	case lastSuccess.Listed > 0 && lastSuccess.DetailFailures*2 > lastSuccess.Listed:
+		health.Status = model.SiteHealthDegraded
+		health.Reason = fmt.Sprintf("%d de %d páginas de detalhe falharam", lastSuccess.DetailFailures, lastSuccess.Listed)
+	default:
+		health.Status = model.SiteHealthHealthy
+	}
+	return health
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+	"web-scrapper/model"
+	"web-scrapper/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runs builds runs newest first from listed counts; a negative count is a failed run.
+func runs(listed ...int) []model.ScrapeRun {
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	result := make([]model.ScrapeRun, len(listed))
+	for i, n := range listed {
+		result[i] = model.ScrapeRun{StartedAt: start.Add(-time.Duration(i) * time.Hour)}
+		if n < 0 {
+			result[i].ErrorClass = model.ScrapeErrorNetwork
+			continue
+		}
+		result[i].Listed = n
+	}
+	return result
+}
+
+func TestDeriveSiteHealth(t *testing.T) {
+	tests := map[string]struct {
+		runs   []model.ScrapeRun
+		status string
+	}{
+		"never scraped":            {nil, model.SiteHealthUnknown},
+		"steady results":           {runs(12, 10, 11), model.SiteHealthHealthy},
+		"one failure":              {runs(-1, 10, 11), model.SiteHealthDegraded},
+		"failing":                  {runs(-1, -1, -1, 10), model.SiteHealthFailing},
+		"dropped to zero":          {runs(0, 10, 11), model.SiteHealthDegraded},
+		"always empty":             {runs(0, 0, 0), model.SiteHealthHealthy},
+		"recovered after failures": {runs(9, -1, -1, -1), model.SiteHealthHealthy},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			health := DeriveSiteHealth(model.SiteRunHistory{SiteID: 1, SiteName: "Acme", Runs: tt.runs})
+
+			assert.Equal(t, tt.status, health.Status)
+		})
+	}
+}
+
+func TestDeriveSiteHealth_IgnoresPolicySkips(t *testing.T) {
+	history := runs(-1, 10, 11)
+	history[0].ErrorClass = model.ScrapeErrorPolicySkip
+
+	health := DeriveSiteHealth(model.SiteRunHistory{SiteID: 1, Runs: history})
+
+	assert.Equal(t, model.SiteHealthHealthy, health.Status)
+	assert.Equal(t, 10, health.LastListed)
+	assert.Equal(t, 11.0, health.AvgListed)
+	require.NotNil(t, health.LastRunAt)
+	assert.Equal(t, history[0].StartedAt, *health.LastRunAt)
+}
+
+func TestDeriveSiteHealth_DetailPagesFailing(t *testing.T) {
+	history := runs(10, 10)
+	history[0].DetailFailures = 8
+
+	health := DeriveSiteHealth(model.SiteRunHistory{SiteID: 1, Runs: history})
+
+	assert.Equal(t, model.SiteHealthDegraded, health.Status)
+	assert.Contains(t, health.Reason, "8 de 10")
+}
+
+func TestSiteHealthUsecase_GetSitesHealth_FiltersByStatus(t *testing.T) {
+	mockRepo := new(mocks.MockScrapeRunRepository)
+	uc := NewSiteHealthUsecase(mockRepo)
+	mockRepo.On("GetRecentRunsBySite", healthWindow).Return([]model.SiteRunHistory{
+		{SiteID: 1, SiteName: "Acme", Runs: runs(10, 10)},
+		{SiteID: 2, SiteName: "Globex", Runs: runs(-1, -1, -1)},
+		{SiteID: 3, SiteName: "Initech"},
+	}, nil).Once()
+
+	sites, err := uc.GetSitesHealth(model.SiteHealthFailing)
+
+	require.NoError(t, err)
+	require.Len(t, sites, 1)
+	assert.Equal(t, 2, sites[0].SiteID)
+	assert.Equal(t, 3, sites[0].ConsecutiveFailures)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSiteHealthUsecase_GetSiteRuns_ClampsLimit(t *testing.T) {
+	mockRepo := new(mocks.MockScrapeRunRepository)
+	uc := NewSiteHealthUsecase(mockRepo)
+	mockRepo.On("GetSiteRuns", 1, defaultRunsLimit).Return([]model.ScrapeRun{}, nil).Once()
+	mockRepo.On("GetSiteRuns", 1, maxRunsLimit).Return([]model.ScrapeRun{}, nil).Once()
+
+	_, err := uc.GetSiteRuns(1, 0)
+	require.NoError(t, err)
+	_, err = uc.GetSiteRuns(1, 10000)
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
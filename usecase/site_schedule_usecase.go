@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"time"
+	"web-scrapper/interfaces"
+	"web-scrapper/logging"
+	"web-scrapper/model"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// scheduleTick is how often the scheduler asks for due sites.
+	scheduleTick = time.Minute
+	// maxCronSpread caps how long after a cron fire time a site may be enqueued, so
+	// sites sharing a cron do not all hit the queue at once.
+	maxCronSpread       = time.Hour
+	minScheduleInterval = 5
+	maxScheduleInterval = 1440
+	// maxCronFires bounds the search for a fire time inside the window.
+	maxCronFires = 4 * 1440
+	// postingWindow is how far back jobs are counted for the posting rate of adaptive sites.
+	postingWindow = 14 * 24 * time.Hour
+	// adaptiveStep rounds adaptive intervals, so small changes in the rate do not move them.
+	adaptiveStep = 5
+)
+
+type SiteScheduleUsecase struct {
+	repo interfaces.SiteCareerRepositoryInterface
+}
+
+func NewSiteScheduleUsecase(repo interfaces.SiteCareerRepositoryInterface) *SiteScheduleUsecase {
+	return &SiteScheduleUsecase{
+		repo: repo,
+	}
+}
+
+// DueSite is a site whose scrape should be enqueued now.
+type DueSite struct {
+	Config model.SiteScrapingConfig
+	// UniqueFor is how long until the site's next run; a scrape of the site still
+	// queued or running within it must not be enqueued again.
+	UniqueFor time.Duration
+}
+
+// DueSites returns the active sites whose next run is due at now. The scheduler
+// calls it every scheduleTick and marks each site it enqueues with MarkScheduled.
+func (uc *SiteScheduleUsecase) DueSites(now time.Time) ([]DueSite, error) {
+	sites, err := uc.repo.GetScheduledSites()
+	if err != nil {
+		return nil, err
+	}
+
+	due := []DueSite{}
+	for _, site := range sites {
+		// Sites never enqueued wait for their next slot instead of all running now
+		after := now.Add(-scheduleTick)
+		if site.LastScheduledAt != nil {
+			after = *site.LastScheduledAt
+		}
+		next, err := NextSiteRun(site, after)
+		if err != nil {
+			logging.Logger.Warn().Err(err).Int("site_id", site.Config.ID).Msg("Skipping site with invalid schedule")
+			continue
+		}
+		if next.After(now) {
+			continue
+		}
+		uniqueFor := scheduleTick
+		if following, err := NextSiteRun(site, now); err == nil && following.Sub(now) > uniqueFor {
+			uniqueFor = following.Sub(now)
+		}
+		due = append(due, DueSite{Config: site.Config, UniqueFor: uniqueFor})
+	}
+	return due, nil
+}
+
+func (uc *SiteScheduleUsecase) MarkScheduled(siteID int, at time.Time) error {
+	return uc.repo.MarkSiteScheduled(siteID, at)
+}
+
+// AdaptIntervals picks the interval of every adaptive site from the jobs it posted
+// during postingWindow and its subscribers, and reports how many changed.
+func (uc *SiteScheduleUsecase) AdaptIntervals(now time.Time) (int, error) {
+	sites, err := uc.repo.GetScheduledSites()
+	if err != nil {
+		return 0, err
+	}
+	stats, err := uc.repo.GetSitePostingStats(now.Add(-postingWindow))
+	if err != nil {
+		return 0, err
+	}
+	statsBySite := make(map[int]model.SitePostingStats, len(stats))
+	for _, stat := range stats {
+		statsBySite[stat.SiteID] = stat
+	}
+
+	changed := 0
+	for _, site := range sites {
+		config := site.Config
+		// Half-set bounds are rejected on save, and skipped by DueSites
+		if config.ScheduleMinIntervalMinutes == nil || config.ScheduleMaxIntervalMinutes == nil {
+			continue
+		}
+		stat := statsBySite[config.ID]
+		minutes := AdaptiveInterval(stat, *config.ScheduleMinIntervalMinutes, *config.ScheduleMaxIntervalMinutes)
+		if site.AdaptiveIntervalMinutes != nil && *site.AdaptiveIntervalMinutes == minutes {
+			continue
+		}
+		if err := uc.repo.SetAdaptiveInterval(config.ID, minutes); err != nil {
+			return changed, err
+		}
+		logging.Logger.Info().Int("site_id", config.ID).Int("new_jobs", stat.NewJobs).Int("subscribers", stat.Subscribers).
+			Int("interval_minutes", minutes).Msg("Adaptive scrape interval updated")
+		changed++
+	}
+	return changed, nil
+}
+
+// AdaptiveInterval aims for about one new job per scrape, scraping sooner the more
+// users follow the site, within [minMinutes, maxMinutes]. Sites nobody follows, or
+// that posted nothing, are scraped every maxMinutes.
+func AdaptiveInterval(stats model.SitePostingStats, minMinutes int, maxMinutes int) int {
+	if stats.NewJobs <= 0 || stats.Subscribers <= 0 {
+		return maxMinutes
+	}
+	jobsPerDay := float64(stats.NewJobs) / postingWindow.Hours() * 24
+	minutes := 24 * 60 / jobsPerDay / (1 + math.Log2(float64(stats.Subscribers)))
+
+	rounded := int(math.Round(minutes/adaptiveStep)) * adaptiveStep
+	return max(minMinutes, min(maxMinutes, rounded))
+}
+
+func isAdaptive(config model.SiteScrapingConfig) bool {
+	return config.ScheduleMinIntervalMinutes != nil || config.ScheduleMaxIntervalMinutes != nil
+}
+
+// NextSiteRun is the first time after the given one the site should be scraped.
+//
+// Interval schedules, fixed or adaptive, run every N minutes from the start of the
+// window, shifted by a per-site offset smaller than N. Cron schedules run at the
+// cron's fire times inside the window, each delayed by a per-site offset smaller
+// than the gap to the next fire (at most maxCronSpread). The offsets spread sites
+// with the same schedule apart.
+func NextSiteRun(site model.ScheduledSite, after time.Time) (time.Time, error) {
+	schedule, err := parseSiteSchedule(site)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if schedule.cron != nil {
+		return schedule.nextCronRun(after)
+	}
+	return schedule.nextIntervalRun(after), nil
+}
+
+type siteSchedule struct {
+	cron     cron.Schedule
+	interval time.Duration
+	loc      *time.Location
+	// The daily window starts windowStart after midnight and lasts windowLength,
+	// possibly past midnight.
+	windowStart  time.Duration
+	windowLength time.Duration
+	seed         uint64
+}
+
+func parseSiteSchedule(site model.ScheduledSite) (siteSchedule, error) {
+	config := site.Config
+	schedule := siteSchedule{windowLength: 24 * time.Hour}
+
+	tz := model.DefaultScheduleTimezone
+	if config.ScheduleTimezone != nil && *config.ScheduleTimezone != "" {
+		tz = *config.ScheduleTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return schedule, fmt.Errorf("fuso horário %q desconhecido", tz)
+	}
+	schedule.loc = loc
+
+	hasCron := config.ScheduleCron != nil && *config.ScheduleCron != ""
+	switch {
+	case hasCron && config.ScheduleIntervalMinutes != nil, (hasCron || config.ScheduleIntervalMinutes != nil) && isAdaptive(config):
+		return schedule, errors.New("use só um entre schedule_cron, schedule_interval_minutes e os limites adaptativos")
+	case config.ScheduleIntervalMinutes != nil:
+		minutes := *config.ScheduleIntervalMinutes
+		if err := checkInterval("schedule_interval_minutes", minutes); err != nil {
+			return schedule, err
+		}
+		schedule.interval = time.Duration(minutes) * time.Minute
+	case isAdaptive(config):
+		if config.ScheduleMinIntervalMinutes == nil || config.ScheduleMaxIntervalMinutes == nil {
+			return schedule, errors.New("informe schedule_min_interval_minutes e schedule_max_interval_minutes")
+		}
+		minMinutes, maxMinutes := *config.ScheduleMinIntervalMinutes, *config.ScheduleMaxIntervalMinutes
+		if err := checkInterval("schedule_min_interval_minutes", minMinutes); err != nil {
+			return schedule, err
+		}
+		if err := checkInterval("schedule_max_interval_minutes", maxMinutes); err != nil {
+			return schedule, err
+		}
+		if minMinutes > maxMinutes {
+			return schedule, errors.New("schedule_min_interval_minutes não pode ser maior que schedule_max_interval_minutes")
+		}
+		// Until an interval is picked, and if the bounds changed since, stay within them
+		minutes := maxMinutes
+		if site.AdaptiveIntervalMinutes != nil {
+			minutes = max(minMinutes, min(maxMinutes, *site.AdaptiveIntervalMinutes))
+		}
+		schedule.interval = time.Duration(minutes) * time.Minute
+	default:
+		expr := model.DefaultScheduleCron
+		if hasCron {
+			expr = *config.ScheduleCron
+		}
+		schedule.cron, err = cron.ParseStandard(expr)
+		if err != nil {
+			return schedule, fmt.Errorf("schedule_cron %q inválido: %w", expr, err)
+		}
+	}
+
+	start, end := config.ScheduleWindowStart, config.ScheduleWindowEnd
+	if (start != nil) != (end != nil) {
+		return schedule, errors.New("informe o início e o fim da janela")
+	}
+	if start != nil {
+		from, err := parseClock(*start)
+		if err != nil {
+			return schedule, err
+		}
+		to, err := parseClock(*end)
+		if err != nil {
+			return schedule, err
+		}
+		if from == to {
+			return schedule, errors.New("a janela não pode começar e terminar no mesmo horário")
+		}
+		schedule.windowStart = from
+		schedule.windowLength = (to - from + 24*time.Hour) % (24 * time.Hour)
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(strconv.Itoa(config.ID)))
+	schedule.seed = hash.Sum64()
+	return schedule, nil
+}
+
+func checkInterval(field string, minutes int) error {
+	if minutes < minScheduleInterval || minutes > maxScheduleInterval {
+		return fmt.Errorf("%s deve estar entre %d e %d", field, minScheduleInterval, maxScheduleInterval)
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into the time since midnight.
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("horário %q inválido, use HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// offset is the site's delay within spread, in whole minutes to match the tick.
+func (s siteSchedule) offset(spread time.Duration) time.Duration {
+	minutes := uint64(spread / time.Minute)
+	if minutes == 0 {
+		return 0
+	}
+	return time.Duration(s.seed%minutes) * time.Minute
+}
+
+func (s siteSchedule) windowOpening(day time.Time) time.Time {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.loc)
+	return midnight.Add(s.windowStart)
+}
+
+func (s siteSchedule) inWindow(t time.Time) bool {
+	t = t.In(s.loc)
+	// The window may have opened the day before
+	for _, day := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		opening := s.windowOpening(day)
+		if !t.Before(opening) && t.Before(opening.Add(s.windowLength)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s siteSchedule) nextIntervalRun(after time.Time) time.Time {
+	local := after.In(s.loc)
+	phase := s.offset(min(s.interval, s.windowLength))
+	for day := -1; ; day++ {
+		opening := s.windowOpening(local.AddDate(0, 0, day))
+		first := opening.Add(phase)
+		if first.After(after) {
+			return first
+		}
+		runs := after.Sub(first)/s.interval + 1
+		next := first.Add(runs * s.interval)
+		if next.Before(opening.Add(s.windowLength)) {
+			return next
+		}
+	}
+}
+
+func (s siteSchedule) nextCronRun(after time.Time) (time.Time, error) {
+	// A fire time up to maxCronSpread ago may still have its slot ahead
+	fire := s.cron.Next(after.In(s.loc).Add(-maxCronSpread))
+	for i := 0; i < maxCronFires && !fire.IsZero(); i++ {
+		next := s.cron.Next(fire)
+		if s.inWindow(fire) {
+			slot := fire.Add(s.offset(min(next.Sub(fire), maxCronSpread)))
+			if slot.After(after) {
+				return slot, nil
+			}
+		}
+		fire = next
+	}
+	return time.Time{}, errors.New("a agenda não tem nenhum horário dentro da janela")
+}
+
+// validateSiteSchedule checks the schedule fields of a config and that they
+// produce at least one run.
+func validateSiteSchedule(site model.SiteScrapingConfig) error {
+	_, err := NextSiteRun(model.ScheduledSite{Config: site}, time.Now())
+	return err
+}
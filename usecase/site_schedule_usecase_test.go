@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"web-scrapper/model"
+	"web-scrapper/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scheduleConfig(id int, cronExpr string, interval int, window ...string) model.SiteScrapingConfig {
+	tz := "America/Sao_Paulo"
+	config := model.SiteScrapingConfig{ID: id, ScheduleTimezone: &tz}
+	if cronExpr != "" {
+		config.ScheduleCron = &cronExpr
+	}
+	if interval > 0 {
+		config.ScheduleIntervalMinutes = &interval
+	}
+	if len(window) == 2 {
+		config.ScheduleWindowStart, config.ScheduleWindowEnd = &window[0], &window[1]
+	}
+	return config
+}
+
+func saoPaulo(t *testing.T, clock string) time.Time {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	at, err := time.ParseInLocation("2006-01-02 15:04", "2026-03-10 "+clock, loc)
+	require.NoError(t, err)
+	return at
+}
+
+func TestNextSiteRun_Interval(t *testing.T) {
+	config := scheduleConfig(1, "", 60, "08:00", "20:00")
+
+	first, err := NextSiteRun(model.ScheduledSite{Config: config}, saoPaulo(t, "09:10"))
+	require.NoError(t, err)
+	second, err := NextSiteRun(model.ScheduledSite{Config: config}, first)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Hour, second.Sub(first))
+	assert.True(t, first.After(saoPaulo(t, "09:10")))
+	assert.True(t, first.Before(saoPaulo(t, "10:11")))
+
+	afterWindow, err := NextSiteRun(model.ScheduledSite{Config: config}, saoPaulo(t, "20:30"))
+	require.NoError(t, err)
+	assert.Equal(t, 11, afterWindow.Day())
+	assert.Equal(t, 8, afterWindow.In(first.Location()).Hour())
+}
+
+func TestNextSiteRun_DailySpreadAcrossWindow(t *testing.T) {
+	runs := make(map[time.Time]bool)
+	for id := 1; id <= 20; id++ {
+		next, err := NextSiteRun(model.ScheduledSite{Config: scheduleConfig(id, "", 1440, "06:00", "22:00")}, saoPaulo(t, "00:00"))
+		require.NoError(t, err)
+		assert.False(t, next.Before(saoPaulo(t, "06:00")))
+		assert.True(t, next.Before(saoPaulo(t, "22:00")))
+		runs[next] = true
+	}
+	assert.Greater(t, len(runs), 10, "sites with the same schedule run at different times")
+}
+
+func TestNextSiteRun_CronWithinWindow(t *testing.T) {
+	config := scheduleConfig(3, "0 * * * *", 0, "22:00", "02:00")
+
+	next, err := NextSiteRun(model.ScheduledSite{Config: config}, saoPaulo(t, "12:00"))
+	require.NoError(t, err)
+
+	assert.False(t, next.Before(saoPaulo(t, "22:00")))
+	assert.True(t, next.Before(saoPaulo(t, "23:00")))
+}
+
+func TestNextSiteRun_DefaultsToLegacyCron(t *testing.T) {
+	next, err := NextSiteRun(model.ScheduledSite{Config: scheduleConfig(4, "", 0)}, saoPaulo(t, "17:59"))
+	require.NoError(t, err)
+
+	assert.False(t, next.Before(saoPaulo(t, "07:00").AddDate(0, 0, 1)))
+	assert.True(t, next.Before(saoPaulo(t, "09:00").AddDate(0, 0, 1)))
+}
+
+func TestValidateSiteSchedule(t *testing.T) {
+	badTZ := "Mars/Olympus"
+	tests := map[string]model.SiteScrapingConfig{
+		"cron and interval":  scheduleConfig(1, "0 * * * *", 60),
+		"invalid cron":       scheduleConfig(1, "every hour", 0),
+		"interval too small": scheduleConfig(1, "", 1),
+		"half a window":      {ScheduleWindowStart: scheduleConfig(1, "", 60, "08:00", "09:00").ScheduleWindowStart},
+		"invalid clock":      scheduleConfig(1, "", 60, "8h", "18h"),
+		"empty window":       scheduleConfig(1, "", 60, "08:00", "08:00"),
+		"no fire in window":  scheduleConfig(1, "0 3 * * *", 0, "08:00", "18:00"),
+		"unknown timezone":   {ScheduleTimezone: &badTZ},
+	}
+	for name, config := range tests {
+		assert.Error(t, validateSiteSchedule(config), name)
+	}
+	assert.NoError(t, validateSiteSchedule(scheduleConfig(1, "*/30 * * * *", 0, "08:00", "18:00")))
+}
+
+func TestSiteScheduleUsecase_DueSites(t *testing.T) {
+	now := saoPaulo(t, "12:00")
+	hourly := scheduleConfig(1, "", 60)
+	daily := scheduleConfig(2, "", 1440)
+	justRan := now.Add(-time.Minute)
+	longAgo := now.Add(-2 * time.Hour)
+
+	t.Run("should return sites whose slot has passed", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteScheduleUsecase(mockRepo)
+		mockRepo.On("GetScheduledSites").Return([]model.ScheduledSite{
+			{Config: hourly, LastScheduledAt: &longAgo},
+			{Config: daily, LastScheduledAt: &justRan},
+		}, nil).Once()
+
+		due, err := uc.DueSites(now)
+
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, 1, due[0].Config.ID)
+		assert.Greater(t, due[0].UniqueFor, time.Duration(0))
+		assert.LessOrEqual(t, due[0].UniqueFor, time.Hour)
+	})
+
+	t.Run("should skip sites with invalid schedules", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteScheduleUsecase(mockRepo)
+		mockRepo.On("GetScheduledSites").Return([]model.ScheduledSite{
+			{Config: scheduleConfig(5, "bad cron", 0), LastScheduledAt: &longAgo},
+		}, nil).Once()
+
+		due, err := uc.DueSites(now)
+
+		require.NoError(t, err)
+		assert.Empty(t, due)
+	})
+
+	t.Run("should return error when repo fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockSiteCareerRepository)
+		uc := NewSiteScheduleUsecase(mockRepo)
+		mockRepo.On("GetScheduledSites").Return([]model.ScheduledSite(nil), errors.New("db error")).Once()
+
+		_, err := uc.DueSites(now)
+
+		assert.Error(t, err)
+	})
+}
+
+func adaptiveConfig(id int, minMinutes int, maxMinutes int, cronExpr string) model.SiteScrapingConfig {
+	config := scheduleConfig(id, cronExpr, 0)
+	config.ScheduleMinIntervalMinutes = &minMinutes
+	config.ScheduleMaxIntervalMinutes = &maxMinutes
+	return config
+}
+
+func TestAdaptiveInterval(t *testing.T) {
+	tests := map[string]struct {
+		stats    model.SitePostingStats
+		expected int
+	}{
+		"no subscribers":           {model.SitePostingStats{NewJobs: 200, Subscribers: 0}, 1440},
+		"no new jobs":              {model.SitePostingStats{NewJobs: 0, Subscribers: 30}, 1440},
+		"a few jobs a week":        {model.SitePostingStats{NewJobs: 6, Subscribers: 1}, 1440},
+		"five jobs a day, 8 users": {model.SitePostingStats{NewJobs: 70, Subscribers: 8}, 70},
+		"very busy board":          {model.SitePostingStats{NewJobs: 2000, Subscribers: 100}, 60},
+	}
+	for name, tt := range tests {
+		assert.Equal(t, tt.expected, AdaptiveInterval(tt.stats, 60, 1440), name)
+	}
+}
+
+func TestNextSiteRun_Adaptive(t *testing.T) {
+	picked := 120
+	site := model.ScheduledSite{Config: adaptiveConfig(1, 60, 720, ""), AdaptiveIntervalMinutes: &picked}
+
+	first, err := NextSiteRun(site, saoPaulo(t, "09:00"))
+	require.NoError(t, err)
+	second, err := NextSiteRun(site, first)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, second.Sub(first))
+
+	// Not picked yet: the max bound
+	site.AdaptiveIntervalMinutes = nil
+	first, _ = NextSiteRun(site, saoPaulo(t, "09:00"))
+	second, _ = NextSiteRun(site, first)
+	assert.Equal(t, 12*time.Hour, second.Sub(first))
+}
+
+func TestSiteScheduleUsecase_AdaptIntervals(t *testing.T) {
+	now := saoPaulo(t, "12:00")
+	current := 1440
+	mockRepo := new(mocks.MockSiteCareerRepository)
+	uc := NewSiteScheduleUsecase(mockRepo)
+	mockRepo.On("GetScheduledSites").Return([]model.ScheduledSite{
+		{Config: adaptiveConfig(1, 60, 1440, "")},
+		{Config: adaptiveConfig(2, 60, 1440, ""), AdaptiveIntervalMinutes: &current},
+		{Config: scheduleConfig(3, "", 60)},
+	}, nil).Once()
+	mockRepo.On("GetSitePostingStats", now.Add(-postingWindow)).Return([]model.SitePostingStats{
+		{SiteID: 1, NewJobs: 70, Subscribers: 8},
+		{SiteID: 2, NewJobs: 1, Subscribers: 1},
+		{SiteID: 3, NewJobs: 500, Subscribers: 50},
+	}, nil).Once()
+	mockRepo.On("SetAdaptiveInterval", 1, 70).Return(nil).Once()
+
+	changed, err := uc.AdaptIntervals(now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"web-scrapper/interfaces"
+	"web-scrapper/model"
 )
 
 type UserSiteUsecase struct {
@@ -17,7 +20,11 @@ func NewUserSiteUsecase(rep interfaces.UserSiteRepositoryInterface, planRepo int
 	}
 }
 
-func (usu *UserSiteUsecase) InsertUserSite(userId int, siteId int, filters []string) error {
+func (usu *UserSiteUsecase) InsertUserSite(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error {
+	if err := validateJobClassFilters(classFilters); err != nil {
+		return err
+	}
+
 	plan, err := usu.planRepo.GetPlanByUserID(userId)
 	if err != nil {
 		return fmt.Errorf("erro ao buscar plano do usuário: %w", err)
@@ -36,14 +43,36 @@ func (usu *UserSiteUsecase) InsertUserSite(userId int, siteId int, filters []str
 		return fmt.Errorf("limite de sites atingido (%d/%d). Faça upgrade do seu plano para monitorar mais sites", count, plan.MaxSites)
 	}
 
-	return usu.rep.InsertNewUserSite(userId, siteId, filters)
+	return usu.rep.InsertNewUserSite(userId, siteId, filters, classFilters)
 }
 
 func (usu *UserSiteUsecase) DeleteUserSite(userId int, siteId string) error {
 	return usu.rep.DeleteUserSite(userId, siteId)
 }
 
-// UpdateUserSiteFilters atualiza os filtros (palavras-chave) de monitoramento de um site
-func (usu *UserSiteUsecase) UpdateUserSiteFilters(userId int, siteId int, filters []string) error {
-	return usu.rep.UpdateUserSiteFilters(userId, siteId, filters)
+// UpdateUserSiteFilters atualiza os filtros (palavras-chave e classificação) de monitoramento de um site
+func (usu *UserSiteUsecase) UpdateUserSiteFilters(userId int, siteId int, filters []string, classFilters model.JobClassFilters) error {
+	if err := validateJobClassFilters(classFilters); err != nil {
+		return err
+	}
+	return usu.rep.UpdateUserSiteFilters(userId, siteId, filters, classFilters)
+}
+
+func validateJobClassFilters(classFilters model.JobClassFilters) error {
+	for _, field := range []struct {
+		name    string
+		values  []string
+		allowed []string
+	}{
+		{"seniorities", classFilters.Seniorities, model.Seniorities},
+		{"role_families", classFilters.RoleFamilies, model.RoleFamilies},
+		{"contract_types", classFilters.ContractTypes, model.ContractTypes},
+	} {
+		for _, value := range field.values {
+			if !slices.Contains(field.allowed, value) {
+				return fmt.Errorf("%w: %s aceita %s", model.ErrInvalidJobClassFilter, field.name, strings.Join(field.allowed, ", "))
+			}
+		}
+	}
+	return nil
 }
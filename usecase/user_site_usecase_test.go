@@ -18,9 +18,9 @@ func TestUserSiteUsecase_InsertUserSite(t *testing.T) {
 		plan := &model.Plan{ID: 1, MaxSites: 5}
 		mockPlanRepo.On("GetPlanByUserID", 1).Return(plan, nil).Once()
 		mockUserSiteRepo.On("GetUserSiteCount", 1).Return(2, nil).Once()
-		mockUserSiteRepo.On("InsertNewUserSite", 1, 10, []string{"golang"}).Return(nil).Once()
+		mockUserSiteRepo.On("InsertNewUserSite", 1, 10, []string{"golang"}, model.JobClassFilters{}).Return(nil).Once()
 
-		err := uc.InsertUserSite(1, 10, []string{"golang"})
+		err := uc.InsertUserSite(1, 10, []string{"golang"}, model.JobClassFilters{})
 
 		assert.NoError(t, err)
 		mockPlanRepo.AssertExpectations(t)
@@ -34,7 +34,7 @@ func TestUserSiteUsecase_InsertUserSite(t *testing.T) {
 
 		mockPlanRepo.On("GetPlanByUserID", 1).Return(nil, nil).Once()
 
-		err := uc.InsertUserSite(1, 10, []string{"golang"})
+		err := uc.InsertUserSite(1, 10, []string{"golang"}, model.JobClassFilters{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "nenhum plano associado")
@@ -50,7 +50,7 @@ func TestUserSiteUsecase_InsertUserSite(t *testing.T) {
 		mockPlanRepo.On("GetPlanByUserID", 1).Return(plan, nil).Once()
 		mockUserSiteRepo.On("GetUserSiteCount", 1).Return(3, nil).Once()
 
-		err := uc.InsertUserSite(1, 10, []string{"golang"})
+		err := uc.InsertUserSite(1, 10, []string{"golang"}, model.JobClassFilters{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "limite de sites atingido")
@@ -67,7 +67,7 @@ func TestUserSiteUsecase_InsertUserSite(t *testing.T) {
 		mockPlanRepo.On("GetPlanByUserID", 1).Return(plan, nil).Once()
 		mockUserSiteRepo.On("GetUserSiteCount", 1).Return(0, errors.New("db error")).Once()
 
-		err := uc.InsertUserSite(1, 10, []string{"golang"})
+		err := uc.InsertUserSite(1, 10, []string{"golang"}, model.JobClassFilters{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "erro ao contar sites")
@@ -82,7 +82,7 @@ func TestUserSiteUsecase_InsertUserSite(t *testing.T) {
 
 		mockPlanRepo.On("GetPlanByUserID", 1).Return(nil, errors.New("plan db error")).Once()
 
-		err := uc.InsertUserSite(1, 10, []string{"golang"})
+		err := uc.InsertUserSite(1, 10, []string{"golang"}, model.JobClassFilters{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "erro ao buscar plano")
@@ -111,11 +111,28 @@ func TestUserSiteUsecase_UpdateUserSiteFilters(t *testing.T) {
 	uc := NewUserSiteUsecase(mockUserSiteRepo, mockPlanRepo)
 
 	t.Run("should update filters successfully", func(t *testing.T) {
-		mockUserSiteRepo.On("UpdateUserSiteFilters", 1, 10, []string{"go", "backend"}).Return(nil).Once()
+		mockUserSiteRepo.On("UpdateUserSiteFilters", 1, 10, []string{"go", "backend"}, model.JobClassFilters{}).Return(nil).Once()
 
-		err := uc.UpdateUserSiteFilters(1, 10, []string{"go", "backend"})
+		err := uc.UpdateUserSiteFilters(1, 10, []string{"go", "backend"}, model.JobClassFilters{})
 
 		assert.NoError(t, err)
 		mockUserSiteRepo.AssertExpectations(t)
 	})
+
+	t.Run("should save classification filters", func(t *testing.T) {
+		classFilters := model.JobClassFilters{Seniorities: []string{"mid", "senior"}, ContractTypes: []string{"clt"}}
+		mockUserSiteRepo.On("UpdateUserSiteFilters", 1, 10, []string{}, classFilters).Return(nil).Once()
+
+		err := uc.UpdateUserSiteFilters(1, 10, []string{}, classFilters)
+
+		assert.NoError(t, err)
+		mockUserSiteRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown classification values", func(t *testing.T) {
+		err := uc.UpdateUserSiteFilters(1, 10, nil, model.JobClassFilters{RoleFamilies: []string{"backend", "cobol"}})
+
+		assert.ErrorIs(t, err, model.ErrInvalidJobClassFilter)
+		mockUserSiteRepo.AssertNotCalled(t, "UpdateUserSiteFilters", 1, 10, []string(nil), model.JobClassFilters{RoleFamilies: []string{"backend", "cobol"}})
+	})
 }